@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+)
+
+func TestResolveFeedForAdd_DiscoveredURLMatchesExistingFeed(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+
+	discover := func(ctx context.Context, pageURL string) (string, error) {
+		if pageURL != "https://blog.example.com" {
+			t.Fatalf("expected discovery on normalized page URL, got %q", pageURL)
+		}
+		return "https://Blog.example.com/feed.xml", nil
+	}
+
+	url, existing, err := resolveFeedForAdd(context.Background(), db, discover, "https://blog.example.com/")
+	if err != nil {
+		t.Fatalf("resolveFeedForAdd returned error: %v", err)
+	}
+	if existing == nil || existing.ID != feed.ID {
+		t.Fatalf("expected existing feed %s, got %v", feed.ID, existing)
+	}
+	if url != feed.Url {
+		t.Fatalf("url = %q; want %q", url, feed.Url)
+	}
+
+	s, out, _ := newTestState(db, false)
+	if err := followExistingFeed(s, bob, *existing); err != nil {
+		t.Fatalf("followExistingFeed returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Now following Blog as bob") {
+		t.Fatalf("expected follow confirmation, got %q", out.String())
+	}
+
+	follows, err := db.GetFeedFollowsForUser(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 1 || follows[0].FeedID != feed.ID {
+		t.Fatalf("expected bob to follow the existing feed, got %v", follows)
+	}
+
+	feeds, err := db.GetFeedsWithUsers(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeedsWithUsers returned error: %v", err)
+	}
+	if len(feeds) != 1 {
+		t.Fatalf("expected no duplicate feed, got %d feeds", len(feeds))
+	}
+}
+
+func TestAddAndFollowFeed_ConcurrentAddsShareOneFeed(t *testing.T) {
+	conn := dbtest.Open(t)
+	db := database.New(conn)
+
+	users := []database.User{createTestUser(t, db, "alice"), createTestUser(t, db, "bob")}
+	const url = "https://blog.example.com/feed.xml"
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(users))
+	created := make([]bool, len(users))
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user database.User) {
+			defer wg.Done()
+			_, created[i], errs[i] = addAndFollowFeed(context.Background(), conn, user, "Blog", url)
+		}(i, user)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("addAndFollowFeed for %s returned error: %v", users[i].Name, err)
+		}
+	}
+	if created[0] == created[1] {
+		t.Fatalf("expected exactly one call to create the feed, got %v", created)
+	}
+
+	feeds, err := db.GetFeedsWithUsers(context.Background())
+	if err != nil {
+		t.Fatalf("GetFeedsWithUsers returned error: %v", err)
+	}
+	if len(feeds) != 1 {
+		t.Fatalf("expected one feed, got %d", len(feeds))
+	}
+
+	for _, user := range users {
+		follows, err := db.GetFeedFollowsForUser(context.Background(), user.ID)
+		if err != nil {
+			t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+		}
+		if len(follows) != 1 || follows[0].FeedID != feeds[0].ID {
+			t.Fatalf("expected %s to follow the one feed, got %v", user.Name, follows)
+		}
+	}
+}
+
+func TestParseCredentialFlags(t *testing.T) {
+	cred, ok, rest, err := parseCredentialFlags([]string{"--user", "alice", "Intranet", "--pass=s3cret", "https://intranet.example.com/feed"})
+	if err != nil || !ok {
+		t.Fatalf("parseCredentialFlags = %v, %v; want credentials", ok, err)
+	}
+	if cred.Username != "alice" || cred.Password != "s3cret" || cred.PasswordEnv != "" {
+		t.Fatalf("unexpected credentials: %+v", cred)
+	}
+	if strings.Join(rest, " ") != "Intranet https://intranet.example.com/feed" {
+		t.Fatalf("rest = %q; want the name and URL", rest)
+	}
+
+	if _, ok, rest, err := parseCredentialFlags([]string{"https://example.com/feed"}); err != nil || ok || len(rest) != 1 {
+		t.Fatalf("expected no credentials without flags, got %v, %q, %v", ok, rest, err)
+	}
+
+	t.Setenv("INTRANET_PASSWORD", "from-env")
+	cred, _, _, err = parseCredentialFlags([]string{"--user", "alice", "--pass-env", "INTRANET_PASSWORD", "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("parseCredentialFlags returned error: %v", err)
+	}
+	if auth := basicAuth(cred); auth.Username != "alice" || auth.Password != "from-env" {
+		t.Fatalf("basicAuth = %+v; want the password from the environment", auth)
+	}
+
+	for _, args := range [][]string{
+		{"--pass", "s3cret", "https://example.com/feed"},
+		{"--user", "alice", "https://example.com/feed"},
+		{"--user", "alice", "--pass", "a", "--pass-env", "B", "https://example.com/feed"},
+	} {
+		if _, _, _, err := parseCredentialFlags(args); err == nil {
+			t.Errorf("parseCredentialFlags(%q) = nil error; want one", args)
+		}
+	}
+}
+
+func TestHandlerAddFeed_OnlyOwnerCanSetCredentials(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "Intranet", "https://intranet.example.com/feed.xml")
+	if err := saveFeedCredentials(context.Background(), db, feed.ID, database.FeedCredential{Username: "alice", Password: "s3cret"}); err != nil {
+		t.Fatalf("saveFeedCredentials returned error: %v", err)
+	}
+
+	s, _, _ := newTestState(db, false)
+	cmd := command{name: "addfeed", args: []string{"--user", "bob", "--pass", "wrong", feed.Url}}
+	err := handlerAddFeed(s, cmd, bob)
+	if err == nil || !strings.Contains(err.Error(), "only the feed's owner can set its credentials") {
+		t.Fatalf("expected ownership error, got %v", err)
+	}
+
+	cred, err := db.GetFeedCredentials(context.Background(), feed.ID)
+	if err != nil {
+		t.Fatalf("GetFeedCredentials returned error: %v", err)
+	}
+	if cred.Username != "alice" || cred.Password != "s3cret" {
+		t.Fatalf("expected alice's credentials to be kept, got %q/%q", cred.Username, cred.Password)
+	}
+	follows, err := db.GetFeedFollowsForUser(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 0 {
+		t.Fatalf("expected bob not to follow the feed, got %v", follows)
+	}
+
+	s, out, _ := newTestState(db, false)
+	cmd = command{name: "addfeed", args: []string{"--user", "alice", "--pass", "n3w", feed.Url}}
+	if err := handlerAddFeed(s, cmd, alice); err != nil {
+		t.Fatalf("handlerAddFeed for the owner returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Saved credentials for Intranet") {
+		t.Fatalf("expected saved confirmation, got %q", out.String())
+	}
+}
+
+func TestSaveOwnFeedCredentials_RejectsOtherUsersFeed(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	// As when bob's addfeed finds the feed alice added concurrently
+	feed := createTestFeed(t, db, alice, "Intranet", "https://intranet.example.com/feed.xml")
+
+	s, _, _ := newTestState(db, false)
+	err := saveOwnFeedCredentials(s, bob, feed, database.FeedCredential{Username: "bob", Password: "wrong"})
+	if err == nil || !strings.Contains(err.Error(), "only the feed's owner can set its credentials") {
+		t.Fatalf("expected ownership error, got %v", err)
+	}
+	if _, err := db.GetFeedCredentials(context.Background(), feed.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no credentials to be stored, got %v", err)
+	}
+}
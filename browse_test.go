@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func TestFetchBrowsePosts_LikedOnly(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+
+	now := time.Now().UTC()
+	liked := createTestPost(t, db, feed, "liked", "https://a.example.com/1", now.Add(-time.Hour))
+	createTestPost(t, db, feed, "not liked", "https://a.example.com/2", now)
+	bobsLike := createTestPost(t, db, feed, "liked by bob", "https://a.example.com/3", now.Add(-2*time.Hour))
+	likeTestPost(t, db, alice, liked)
+	likeTestPost(t, db, bob, bobsLike)
+
+	all, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 posts without --liked, got %d", len(all))
+	}
+
+	posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{liked: true}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != liked.ID {
+		t.Fatalf("expected only alice's liked post, got %+v", posts)
+	}
+	if posts[0].FeedName != "A" {
+		t.Fatalf("expected feed name A, got %q", posts[0].FeedName)
+	}
+}
+
+func TestFetchNewPosts_OnlyPostsSinceLastBrowse(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+	createTestPost(t, db, feed, "old", "https://a.example.com/1", time.Now())
+
+	// First run has no timestamp and shows everything
+	firstBrowse := time.Now().UTC().Add(time.Minute)
+	posts, since, err := fetchNewPosts(ctx, db, alice.ID, firstBrowse, 10)
+	if err != nil {
+		t.Fatalf("fetchNewPosts returned error: %v", err)
+	}
+	if !since.IsZero() || len(posts) != 1 {
+		t.Fatalf("expected every post on first run, got %d posts since %v", len(posts), since)
+	}
+
+	// Posts stored after the first browse are the only new ones
+	_, err = db.CreatePost(ctx, database.CreatePostParams{
+		ID:        uuid.New(),
+		CreatedAt: firstBrowse.Add(time.Minute),
+		UpdatedAt: firstBrowse.Add(time.Minute),
+		Title:     "new",
+		Url:       "https://a.example.com/2",
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create post: %v", err)
+	}
+
+	secondBrowse := firstBrowse.Add(time.Hour)
+	posts, since, err = fetchNewPosts(ctx, db, alice.ID, secondBrowse, 10)
+	if err != nil {
+		t.Fatalf("fetchNewPosts returned error: %v", err)
+	}
+	if !since.Equal(firstBrowse.Truncate(time.Microsecond)) {
+		t.Fatalf("since = %v; want %v", since, firstBrowse)
+	}
+	if len(posts) != 1 || posts[0].Title != "new" {
+		t.Fatalf("expected only the new post, got %+v", posts)
+	}
+
+	last, err := db.GetLastBrowsedAt(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetLastBrowsedAt returned error: %v", err)
+	}
+	if !last.Equal(secondBrowse.Truncate(time.Microsecond)) {
+		t.Fatalf("expected timestamp to advance to %v, got %v", secondBrowse, last)
+	}
+}
+
+func TestPostTags_TagUntagAndBrowseByTag(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+
+	now := time.Now().UTC()
+	first := createTestPost(t, db, feed, "first", "https://a.example.com/1", now.Add(-time.Hour))
+	second := createTestPost(t, db, feed, "second", "https://a.example.com/2", now)
+	createTestPost(t, db, feed, "untagged", "https://a.example.com/3", now.Add(-2*time.Hour))
+
+	tag := func(user database.User, post database.Post, name string) int64 {
+		t.Helper()
+		n, err := db.TagPost(ctx, database.TagPostParams{
+			ID: uuid.New(), CreatedAt: now, UserID: user.ID, PostID: post.ID, Tag: name,
+		})
+		if err != nil {
+			t.Fatalf("TagPost returned error: %v", err)
+		}
+		return n
+	}
+	tag(alice, first, "later")
+	tag(alice, second, "later")
+	tag(alice, second, "work")
+	tag(bob, first, "work")
+	if n := tag(alice, first, "later"); n != 0 {
+		t.Fatalf("expected re-tagging to be a no-op, got %d rows", n)
+	}
+
+	posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{tag: "later"}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != second.ID || posts[1].ID != first.ID {
+		t.Fatalf("expected alice's two 'later' posts newest first, got %+v", posts)
+	}
+
+	// Tags are per user: bob's "work" tag doesn't show up for alice
+	posts, err = fetchBrowsePosts(ctx, db, alice.ID, browseFilter{tag: "work"}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != second.ID {
+		t.Fatalf("expected only alice's 'work' post, got %+v", posts)
+	}
+
+	n, err := db.UntagPost(ctx, database.UntagPostParams{UserID: alice.ID, PostID: second.ID, Tag: "later"})
+	if err != nil {
+		t.Fatalf("UntagPost returned error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected one tag removed, got %d", n)
+	}
+
+	posts, err = fetchBrowsePosts(ctx, db, alice.ID, browseFilter{tag: "later"}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != first.ID {
+		t.Fatalf("expected only the still-tagged post, got %+v", posts)
+	}
+}
+
+func TestBrowseByCategory_MatchesFeedCategoriesIgnoringCase(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	followed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	other := createTestFeed(t, db, alice, "B", "https://b.example.com/feed.xml")
+	followTestFeed(t, db, alice, followed)
+
+	now := time.Now().UTC()
+	first := createTestPost(t, db, followed, "first", "https://a.example.com/1", now.Add(-time.Hour))
+	second := createTestPost(t, db, followed, "second", "https://a.example.com/2", now)
+	unfollowed := createTestPost(t, db, other, "elsewhere", "https://b.example.com/1", now)
+	createTestPost(t, db, followed, "uncategorized", "https://a.example.com/3", now)
+
+	categorize := func(post database.Post, categories ...string) {
+		t.Helper()
+		for _, category := range categories {
+			if err := db.AddPostCategory(ctx, database.AddPostCategoryParams{PostID: post.ID, Category: category}); err != nil {
+				t.Fatalf("AddPostCategory returned error: %v", err)
+			}
+		}
+	}
+	categorize(first, "Go", "Databases")
+	categorize(second, "go", "GO")
+	categorize(unfollowed, "Go")
+
+	posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{category: "go"}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != second.ID || posts[1].ID != first.ID {
+		t.Fatalf("expected the two followed 'go' posts once each, newest first, got %+v", posts)
+	}
+
+	posts, err = fetchBrowsePosts(ctx, db, alice.ID, browseFilter{category: "Databases"}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != first.ID {
+		t.Fatalf("expected only the 'Databases' post, got %+v", posts)
+	}
+}
+
+func TestPostReads_ToggleAndBrowseUnread(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+	followTestFeed(t, db, bob, feed)
+
+	now := time.Now().UTC()
+	older := createTestPost(t, db, feed, "older", "https://a.example.com/1", now.Add(-time.Hour))
+	newer := createTestPost(t, db, feed, "newer", "https://a.example.com/2", now)
+
+	s, out, _ := newTestState(db, false)
+	if err := handlerMarkRead(s, command{name: "mark-read", args: []string{newer.ID.String()}}, alice); err != nil {
+		t.Fatalf("handlerMarkRead returned error: %v", err)
+	}
+	if err := handlerMarkRead(s, command{name: "mark-read", args: []string{newer.ID.String()}}, alice); err != nil {
+		t.Fatalf("handlerMarkRead returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "already marked read") {
+		t.Fatalf("expected marking twice to be reported, got %q", out.String())
+	}
+
+	posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{unread: true}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != older.ID {
+		t.Fatalf("expected only the unread post, got %+v", posts)
+	}
+
+	// Reads are per user
+	posts, err = fetchBrowsePosts(ctx, db, bob.ID, browseFilter{unread: true}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("expected bob to still have both posts unread, got %+v", posts)
+	}
+
+	if err := handlerMarkUnread(s, command{name: "mark-unread", args: []string{newer.ID.String()}}, alice); err != nil {
+		t.Fatalf("handlerMarkUnread returned error: %v", err)
+	}
+	posts, err = fetchBrowsePosts(ctx, db, alice.ID, browseFilter{unread: true}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != newer.ID {
+		t.Fatalf("expected the post to be unread again, got %+v", posts)
+	}
+}
+
+func TestFetchBrowsePosts_ByFeedURLOrName(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, alice, "B", "https://b.example.com/feed.xml")
+	unfollowed := createTestFeed(t, db, bob, "C", "https://c.example.com/feed.xml")
+	followTestFeed(t, db, alice, feedA)
+	followTestFeed(t, db, alice, feedB)
+
+	now := time.Now().UTC()
+	older := createTestPost(t, db, feedA, "older", "https://a.example.com/1", now.Add(-time.Hour))
+	newer := createTestPost(t, db, feedA, "newer", "https://a.example.com/2", now)
+	createTestPost(t, db, feedB, "other feed", "https://b.example.com/1", now)
+	createTestPost(t, db, unfollowed, "not followed", "https://c.example.com/1", now)
+
+	for _, feed := range []string{feedA.Url, feedA.Name} {
+		posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{feed: feed}, 10, 0)
+		if err != nil {
+			t.Fatalf("fetchBrowsePosts returned error: %v", err)
+		}
+		if len(posts) != 2 || posts[0].ID != newer.ID || posts[1].ID != older.ID {
+			t.Fatalf("expected feed A's posts newest first for %q, got %+v", feed, posts)
+		}
+	}
+
+	// Pagination works the same as the unfiltered listing
+	posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{feed: feedA.Url}, 1, 1)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != older.ID {
+		t.Fatalf("expected the second page to hold the older post, got %+v", posts)
+	}
+
+	// Feeds the user doesn't follow show nothing
+	posts, err = fetchBrowsePosts(ctx, db, alice.ID, browseFilter{feed: unfollowed.Url}, 10, 0)
+	if err != nil {
+		t.Fatalf("fetchBrowsePosts returned error: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Fatalf("expected no posts from an unfollowed feed, got %+v", posts)
+	}
+}
+
+func TestFetchBrowsePosts_SortOrders(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+
+	// Stored in this order, so the added order differs from the published order
+	now := time.Now().UTC()
+	yesterday := createTestPost(t, db, feed, "yesterday", "https://a.example.com/1", now.AddDate(0, 0, -1))
+	time.Sleep(time.Millisecond)
+	lastWeek := createTestPost(t, db, feed, "last week", "https://a.example.com/2", now.AddDate(0, 0, -7))
+	time.Sleep(time.Millisecond)
+	undated := createTestPost(t, db, feed, "undated", "https://a.example.com/3", time.Time{})
+
+	cases := map[string][]uuid.UUID{
+		"":               {yesterday.ID, lastWeek.ID, undated.ID},
+		browseSortNewest: {yesterday.ID, lastWeek.ID, undated.ID},
+		browseSortOldest: {lastWeek.ID, yesterday.ID, undated.ID},
+		browseSortAdded:  {undated.ID, lastWeek.ID, yesterday.ID},
+	}
+	for sort, want := range cases {
+		posts, err := fetchBrowsePosts(ctx, db, alice.ID, browseFilter{sort: sort}, 10, 0)
+		if err != nil {
+			t.Fatalf("fetchBrowsePosts returned error: %v", err)
+		}
+		if len(posts) != len(want) {
+			t.Fatalf("sort %q: expected %d posts, got %d", sort, len(want), len(posts))
+		}
+		for i := range want {
+			if posts[i].ID != want[i] {
+				t.Fatalf("sort %q: post %d is %q, want order %v", sort, i, posts[i].Title, want)
+			}
+		}
+	}
+}
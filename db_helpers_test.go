@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+// openTestQueries returns queries backed by a freshly migrated test database,
+// skipping the test when no database is configured
+func openTestQueries(t *testing.T) *database.Queries {
+	t.Helper()
+	return database.New(dbtest.Open(t))
+}
+
+func createTestUser(t *testing.T, db *database.Queries, name string) database.User {
+	t.Helper()
+	user, err := db.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create user %s: %v", name, err)
+	}
+	return user
+}
+
+func createTestFeed(t *testing.T, db *database.Queries, owner database.User, name, url string) database.Feed {
+	t.Helper()
+	feed, err := db.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		Url:       url,
+		UserID:    owner.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed %s: %v", url, err)
+	}
+	return feed
+}
+
+func followTestFeed(t *testing.T, db *database.Queries, user database.User, feed database.Feed) {
+	t.Helper()
+	_, err := db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't follow feed %s: %v", feed.Url, err)
+	}
+}
+
+// failingDB is a database.DBTX whose queries all fail with err, for exercising
+// handler error paths without a database. QueryRowContext is not supported.
+type failingDB struct {
+	err error
+}
+
+func (f failingDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, f.err
+}
+
+func (f failingDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, f.err
+}
+
+func (f failingDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, f.err
+}
+
+func (f failingDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	panic("failingDB does not support QueryRowContext")
+}
+
+func createTestPost(t *testing.T, db *database.Queries, feed database.Feed, title, url string, publishedAt time.Time) database.Post {
+	t.Helper()
+	post, err := db.CreatePost(context.Background(), database.CreatePostParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		Title:       title,
+		Url:         url,
+		PublishedAt: sql.NullTime{Time: publishedAt, Valid: !publishedAt.IsZero()},
+		FeedID:      feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create post %s: %v", url, err)
+	}
+	return post
+}
+
+func likeTestPost(t *testing.T, db *database.Queries, user database.User, post database.Post) {
+	t.Helper()
+	_, err := db.CreateLike(context.Background(), database.CreateLikeParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't like post %s: %v", post.Url, err)
+	}
+}
+
+func bookmarkTestPost(t *testing.T, db *database.Queries, user database.User, post database.Post) {
+	t.Helper()
+	_, err := db.CreateBookmark(context.Background(), database.CreateBookmarkParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't bookmark post %s: %v", post.Url, err)
+	}
+}
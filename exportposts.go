@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gator/internal/database"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportPostsPageSize is how many posts `export-posts` loads at a time
+const exportPostsPageSize = 500
+
+// exportedPost is one post as written by `export-posts`
+type exportedPost struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	FeedName    string     `json:"feed_name"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+func newExportedPost(post database.GetPostsForUserRow) exportedPost {
+	exported := exportedPost{
+		ID:          post.ID,
+		Title:       post.Title,
+		URL:         post.Url,
+		Description: post.Description.String,
+		FeedName:    post.FeedName,
+	}
+	if post.PublishedAt.Valid {
+		publishedAt := post.PublishedAt.Time
+		exported.PublishedAt = &publishedAt
+	}
+	return exported
+}
+
+// postWriter writes exported posts one at a time. Close finishes the output
+// and must be called once every post is written.
+type postWriter interface {
+	Write(post exportedPost) error
+	Close() error
+}
+
+// csvPostWriter writes posts as CSV with a header row
+type csvPostWriter struct {
+	w *csv.Writer
+}
+
+var exportCSVHeader = []string{"id", "title", "url", "description", "feed_name", "published_at"}
+
+func newCSVPostWriter(w io.Writer) (*csvPostWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return nil, err
+	}
+	return &csvPostWriter{w: cw}, nil
+}
+
+func (c *csvPostWriter) Write(post exportedPost) error {
+	publishedAt := ""
+	if post.PublishedAt != nil {
+		publishedAt = post.PublishedAt.Format(time.RFC3339)
+	}
+	return c.w.Write([]string{post.ID.String(), post.Title, post.URL, post.Description, post.FeedName, publishedAt})
+}
+
+func (c *csvPostWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonPostWriter writes posts as a versioned JSON document holding a "posts"
+// array, one post per line
+type jsonPostWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonPostWriter) Write(post exportedPost) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if j.written == 0 {
+		separator = fmt.Sprintf("{\"schema_version\":%d,\"posts\":[\n", jsonSchemaVersion)
+	}
+	j.written++
+	_, err = fmt.Fprintf(j.w, "%s%s", separator, data)
+	return err
+}
+
+func (j *jsonPostWriter) Close() error {
+	if j.written == 0 {
+		_, err := fmt.Fprintf(j.w, "{\"schema_version\":%d,\"posts\":[]}\n", jsonSchemaVersion)
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]}\n")
+	return err
+}
+
+// newPostWriter returns the postWriter for an `export-posts` format, which
+// must be "json" or "csv"
+func newPostWriter(w io.Writer, format string) (postWriter, error) {
+	if format == "csv" {
+		return newCSVPostWriter(w)
+	}
+	return &jsonPostWriter{w: w}, nil
+}
+
+// exportPosts writes every post from the user's followed feeds to w, newest
+// first, loading pageSize posts at a time. A non-empty feed limits the export
+// to that feed, matched by URL or exact name as by `browse --feed`. Pages are
+// keyed by the last post written, so posts saved meanwhile by `agg` can't
+// shift a page and get written twice. It returns the number of posts written.
+func exportPosts(ctx context.Context, db *database.Queries, userID uuid.UUID, feed string, w postWriter, pageSize int32) (int, error) {
+	exported := 0
+	params := database.GetPostsForUserAfterParams{UserID: userID, Feed: feed, First: true, Limit: pageSize}
+	for {
+		posts, err := db.GetPostsForUserAfter(ctx, params)
+		if err != nil {
+			return exported, fmt.Errorf("couldn't retrieve posts: %w", err)
+		}
+		for _, post := range posts {
+			if err := w.Write(newExportedPost(database.GetPostsForUserRow(post))); err != nil {
+				return exported, fmt.Errorf("couldn't write post: %w", err)
+			}
+			exported++
+		}
+		if int32(len(posts)) < pageSize {
+			return exported, nil
+		}
+		last := posts[len(posts)-1]
+		params.First = false
+		params.AfterPublishedAt = last.PublishedAt
+		params.AfterID = last.ID
+	}
+}
+
+// handlerExportPosts writes all posts from the user's followed feeds as JSON
+// or CSV, to stdout or the file named by --output
+func handlerExportPosts(s *state, cmd command, user database.User) (err error) {
+	format, args, err := flagValue(cmd.args, "--format")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unsupported export format %q (supported: json, csv)", format)
+	}
+	feed, args, err := flagValue(args, "--feed")
+	if err != nil {
+		return err
+	}
+	outputPath, _, err := flagValue(args, "--output")
+	if err != nil {
+		return err
+	}
+
+	out := s.dataOut
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("couldn't create %s: %w", outputPath, err)
+		}
+		// A failed close can mean the last posts never reached the file
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("couldn't write %s: %w", outputPath, closeErr)
+			}
+		}()
+		out = file
+	}
+
+	w, err := newPostWriter(out, format)
+	if err != nil {
+		return err
+	}
+	exported, err := exportPosts(context.Background(), s.db, user.ID, feed, w, exportPostsPageSize)
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("couldn't write posts: %w", err)
+	}
+
+	if outputPath != "" {
+		fmt.Fprintf(s.out, "Exported %d posts to %s\n", exported, outputPath)
+	}
+	return nil
+}
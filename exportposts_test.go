@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVPostWriter_QuotesFields(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newPostWriter(&buf, "csv")
+	if err != nil {
+		t.Fatalf("newPostWriter returned error: %v", err)
+	}
+	published := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)
+	post := exportedPost{
+		Title:       `Commas, "quotes", and more`,
+		URL:         "https://example.com/1",
+		Description: "First line\nsecond line",
+		FeedName:    "Blog",
+		PublishedAt: &published,
+	}
+	if err := w.Write(post); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output isn't valid CSV: %v", err)
+	}
+	if len(records) != 2 || strings.Join(records[0], ",") != strings.Join(exportCSVHeader, ",") {
+		t.Fatalf("unexpected records: %q", records)
+	}
+	if got := records[1]; got[1] != post.Title || got[3] != post.Description || got[5] != "2024-03-05T09:30:00Z" {
+		t.Fatalf("unexpected row: %q", got)
+	}
+}
+
+func TestExportPosts_StreamsEveryPageAsJSONAndCSV(t *testing.T) {
+	db := openTestQueries(t)
+	alice := createTestUser(t, db, "alice")
+	blog := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed")
+	news := createTestFeed(t, db, alice, "News", "https://news.example.com/feed")
+	unfollowed := createTestFeed(t, db, alice, "Other", "https://other.example.com/feed")
+	followTestFeed(t, db, alice, blog)
+	followTestFeed(t, db, alice, news)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	createTestPost(t, db, blog, "Blog 1", "https://blog.example.com/1", now.Add(-3*time.Hour))
+	createTestPost(t, db, news, "News 1", "https://news.example.com/1", now.Add(-2*time.Hour))
+	createTestPost(t, db, blog, "Blog 2", "https://blog.example.com/2", now.Add(-time.Hour))
+	createTestPost(t, db, unfollowed, "Other 1", "https://other.example.com/1", now)
+
+	// A page size of 2 makes the export load three pages
+	var jsonOut bytes.Buffer
+	exported, err := exportPosts(context.Background(), db, alice.ID, "", &jsonPostWriter{w: &jsonOut}, 2)
+	if err != nil {
+		t.Fatalf("exportPosts returned error: %v", err)
+	}
+	if exported != 3 {
+		t.Fatalf("exported %d posts; want 3", exported)
+	}
+	var doc struct {
+		SchemaVersion int            `json:"schema_version"`
+		Posts         []exportedPost `json:"posts"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &doc); err != nil {
+		t.Fatalf("output isn't a JSON document: %v\n%s", err, jsonOut.String())
+	}
+	if doc.SchemaVersion != jsonSchemaVersion {
+		t.Fatalf("schema_version = %d; want %d", doc.SchemaVersion, jsonSchemaVersion)
+	}
+	posts := doc.Posts
+	if len(posts) != 3 || posts[0].Title != "Blog 2" || posts[1].FeedName != "News" || posts[2].Title != "Blog 1" {
+		t.Fatalf("unexpected posts: %+v", posts)
+	}
+	if posts[0].PublishedAt == nil || !posts[0].PublishedAt.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("published_at = %v; want %v", posts[0].PublishedAt, now.Add(-time.Hour))
+	}
+
+	s, out, _ := newTestState(db, false)
+	if err := handlerExportPosts(s, command{name: "export-posts", args: []string{"--format", "csv", "--feed", blog.Url}}, alice); err != nil {
+		t.Fatalf("handlerExportPosts returned error: %v", err)
+	}
+	records, err := csv.NewReader(out).ReadAll()
+	if err != nil {
+		t.Fatalf("output isn't valid CSV: %v", err)
+	}
+	if len(records) != 3 || records[1][1] != "Blog 2" || records[2][1] != "Blog 1" || records[1][4] != "Blog" {
+		t.Fatalf("unexpected CSV export of one feed: %q", records)
+	}
+}
+
+// insertingPostWriter saves a new post after the first post is written, as a
+// concurrent `agg` might
+type insertingPostWriter struct {
+	postWriter
+	insert func()
+}
+
+func (w *insertingPostWriter) Write(post exportedPost) error {
+	if w.insert != nil {
+		w.insert()
+		w.insert = nil
+	}
+	return w.postWriter.Write(post)
+}
+
+func TestExportPosts_NewPostsDontShiftPages(t *testing.T) {
+	db := openTestQueries(t)
+	alice := createTestUser(t, db, "alice")
+	blog := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed")
+	followTestFeed(t, db, alice, blog)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 1; i <= 3; i++ {
+		createTestPost(t, db, blog, fmt.Sprintf("Post %d", i), fmt.Sprintf("https://blog.example.com/%d", i), now.Add(time.Duration(i-5)*time.Hour))
+	}
+	createTestPost(t, db, blog, "Undated 1", "https://blog.example.com/undated-1", time.Time{})
+	createTestPost(t, db, blog, "Undated 2", "https://blog.example.com/undated-2", time.Time{})
+
+	var jsonOut bytes.Buffer
+	w := &insertingPostWriter{postWriter: &jsonPostWriter{w: &jsonOut}, insert: func() {
+		createTestPost(t, db, blog, "Newest", "https://blog.example.com/newest", now)
+	}}
+	exported, err := exportPosts(context.Background(), db, alice.ID, "", w, 2)
+	if err != nil {
+		t.Fatalf("exportPosts returned error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	var doc struct {
+		Posts []exportedPost `json:"posts"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &doc); err != nil {
+		t.Fatalf("output isn't a JSON document: %v\n%s", err, jsonOut.String())
+	}
+	seen := map[string]bool{}
+	for _, post := range doc.Posts {
+		if seen[post.URL] {
+			t.Fatalf("post %s exported twice: %+v", post.URL, doc.Posts)
+		}
+		seen[post.URL] = true
+	}
+	if exported != 5 || len(doc.Posts) != 5 || doc.Posts[0].Title != "Post 3" || doc.Posts[3].PublishedAt != nil {
+		t.Fatalf("expected the 5 posts there were when the export started, newest first, got %+v", doc.Posts)
+	}
+}
+
+func TestHandlerExportPosts_EmptyAndUnsupportedFormat(t *testing.T) {
+	db := openTestQueries(t)
+	alice := createTestUser(t, db, "alice")
+	s, out, _ := newTestState(db, false)
+
+	if err := handlerExportPosts(s, command{name: "export-posts"}, alice); err != nil {
+		t.Fatalf("handlerExportPosts returned error: %v", err)
+	}
+	if want := fmt.Sprintf("{\"schema_version\":%d,\"posts\":[]}\n", jsonSchemaVersion); out.String() != want {
+		t.Fatalf("output = %q; want %q", out.String(), want)
+	}
+
+	err := handlerExportPosts(s, command{name: "export-posts", args: []string{"--format", "xml"}}, alice)
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format") {
+		t.Fatalf("expected an unsupported format error, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feeds that keep failing are skipped for feedRetryBase after the first
+// failure, doubling with each further failure up to feedRetryMax
+const (
+	feedRetryBase = 10 * time.Minute
+	feedRetryMax  = 24 * time.Hour
+)
+
+// FeedFetchRecorder tracks the outcome of each feed's fetch so that feeds
+// failing repeatedly are backed off instead of retried on every run. Failed
+// is given the error that failed the fetch.
+type FeedFetchRecorder interface {
+	Succeeded(ctx context.Context, feedID uuid.UUID) error
+	Failed(ctx context.Context, feedID uuid.UUID, fetchErr error) error
+}
+
+// dbFeedFetches is a FeedFetchRecorder backed by the feeds table
+type dbFeedFetches struct {
+	db  *database.Queries
+	now func() time.Time
+}
+
+func (f dbFeedFetches) Succeeded(ctx context.Context, feedID uuid.UUID) error {
+	return f.db.MarkFeedFetched(ctx, feedID)
+}
+
+// Failed records another failure for feedID and skips it for the backoff
+// delay, or for as long as the server asked in a Retry-After header if that's
+// longer
+func (f dbFeedFetches) Failed(ctx context.Context, feedID uuid.UUID, fetchErr error) error {
+	failures, err := f.db.RecordFeedFailure(ctx, feedID)
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if f.now != nil {
+		now = f.now
+	}
+	delay := feedRetryDelay(int(failures))
+	var statusErr *rss.StatusError
+	if errors.As(fetchErr, &statusErr) {
+		delay = max(delay, statusErr.RetryAfter)
+	}
+	return f.db.ScheduleFeedRetry(ctx, database.ScheduleFeedRetryParams{
+		ID:          feedID,
+		NextRetryAt: sql.NullTime{Time: now().UTC().Add(delay), Valid: true},
+	})
+}
+
+// feedRetryDelay is how long a feed is skipped after failing the given
+// number of times in a row
+func feedRetryDelay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	delay := feedRetryBase
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= feedRetryMax {
+			return feedRetryMax
+		}
+	}
+	return delay
+}
+
+// handlerFeedsFailing lists feeds whose recent fetches failed and when
+// they'll next be tried
+func handlerFeedsFailing(s *state) error {
+	feeds, err := s.db.GetFailingFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve failing feeds: %w", err)
+	}
+
+	if len(feeds) == 0 {
+		fmt.Fprintln(s.out, "No feeds are failing.")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "%d feeds are failing:\n", len(feeds))
+	for _, feed := range feeds {
+		fmt.Fprintf(s.out, "* %s (%s)\n", feed.Name, feed.Url)
+		fmt.Fprintf(s.out, "  %d failures in a row", feed.ConsecutiveFailures)
+		if feed.NextRetryAt.Valid {
+			fmt.Fprintf(s.out, ", next try after %s", feed.NextRetryAt.Time.Format("2006-01-02 15:04"))
+		}
+		fmt.Fprintln(s.out)
+		if feed.LastFetchedAt.Valid {
+			fmt.Fprintf(s.out, "  Last fetched successfully on %s\n", feed.LastFetchedAt.Time.Format("2006-01-02 15:04"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/rss"
+
+	"github.com/google/uuid"
+)
+
+func TestFeedRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  0,
+		1:  10 * time.Minute,
+		2:  20 * time.Minute,
+		3:  40 * time.Minute,
+		5:  160 * time.Minute,
+		8:  1280 * time.Minute,
+		9:  feedRetryMax,
+		50: feedRetryMax,
+	}
+	for failures, want := range cases {
+		if got := feedRetryDelay(failures); got != want {
+			t.Errorf("feedRetryDelay(%d) = %s; want %s", failures, got, want)
+		}
+	}
+}
+
+// fakeFeedFetches records which feeds succeeded and failed
+type fakeFeedFetches struct {
+	mu        sync.Mutex
+	succeeded map[uuid.UUID]int
+	failed    map[uuid.UUID]int
+}
+
+func newFakeFeedFetches() *fakeFeedFetches {
+	return &fakeFeedFetches{succeeded: map[uuid.UUID]int{}, failed: map[uuid.UUID]int{}}
+}
+
+func (f *fakeFeedFetches) Succeeded(ctx context.Context, feedID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.succeeded[feedID]++
+	return nil
+}
+
+func (f *fakeFeedFetches) Failed(ctx context.Context, feedID uuid.UUID, fetchErr error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed[feedID]++
+	return nil
+}
+
+func TestAggregateFeeds_RecordsFetchOutcomes(t *testing.T) {
+	good := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "good", Url: "https://good.example.com/feed"}
+	badFetch := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "bad fetch", Url: "https://down.example.com/feed"}
+	badSave := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "bad save", Url: "https://unsaveable.example.com/feed"}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if url == badFetch.Url {
+			return nil, errors.New("connection refused")
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: url + "/1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		if feedID == badSave.ID {
+			return nil, errors.New("disk full")
+		}
+		return nil, nil
+	}
+
+	fetches := newFakeFeedFetches()
+	aggregateFeeds(context.Background(), []database.GetFeedsWithUsersRow{good, badFetch, badSave}, AggregationConfig{
+		Workers: 2,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Fetches: fetches,
+	})
+
+	if fetches.succeeded[good.ID] != 1 || fetches.failed[good.ID] != 0 {
+		t.Fatalf("expected the good feed to be recorded as a success, got %+v", fetches)
+	}
+	if fetches.failed[badFetch.ID] != 1 || fetches.failed[badSave.ID] != 1 {
+		t.Fatalf("expected fetch and save errors to be recorded as failures, got %+v", fetches)
+	}
+	if fetches.succeeded[badFetch.ID] != 0 || fetches.succeeded[badSave.ID] != 0 {
+		t.Fatalf("expected failing feeds not to be recorded as successes, got %+v", fetches)
+	}
+}
+
+// ctxCheckingFetches records whether outcomes were reported with a live context
+type ctxCheckingFetches struct {
+	mu      sync.Mutex
+	ctxErrs []error
+}
+
+func (f *ctxCheckingFetches) Succeeded(ctx context.Context, feedID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ctxErrs = append(f.ctxErrs, ctx.Err())
+	return nil
+}
+
+func (f *ctxCheckingFetches) Failed(ctx context.Context, feedID uuid.UUID, fetchErr error) error {
+	return f.Succeeded(ctx, feedID)
+}
+
+func TestAggregateFeeds_RecordsSuccessAfterSlowSave(t *testing.T) {
+	feed := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "slow", Url: "https://slow.example.com/feed"}
+
+	fetches := &ctxCheckingFetches{}
+	aggregateFeeds(context.Background(), []database.GetFeedsWithUsersRow{feed}, AggregationConfig{
+		Workers:     1,
+		FeedTimeout: 20 * time.Millisecond,
+		Fetch: func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+			return &rss.RSSFeed{}, nil
+		},
+		// Saving finishes just as the feed's timeout runs out
+		Save: func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+			<-ctx.Done()
+			return nil, nil
+		},
+		Client:  &http.Client{},
+		Fetches: fetches,
+	})
+
+	if len(fetches.ctxErrs) != 1 || fetches.ctxErrs[0] != nil {
+		t.Fatalf("expected the success to be recorded with a live context, got %v", fetches.ctxErrs)
+	}
+}
+
+func TestDBFeedFetches_IncrementResetAndBackoff(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "Flaky", "https://flaky.example.com/feed")
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetches := dbFeedFetches{db: db, now: func() time.Time { return now }}
+
+	for i := 0; i < 2; i++ {
+		if err := fetches.Failed(ctx, feed.ID, errConnRefused); err != nil {
+			t.Fatalf("Failed returned error: %v", err)
+		}
+	}
+	stored, err := db.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if stored.ConsecutiveFailures != 2 || !stored.NextRetryAt.Valid || !stored.NextRetryAt.Time.Equal(now.Add(feedRetryDelay(2))) {
+		t.Fatalf("expected 2 failures and a retry 20 minutes out, got %d / %+v", stored.ConsecutiveFailures, stored.NextRetryAt)
+	}
+
+	// A feed backing off is left out of aggregation runs
+	batch, err := db.GetFeedsBatch(ctx, database.GetFeedsBatchParams{StartedAt: time.Now(), AfterID: uuid.Nil, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetFeedsBatch returned error: %v", err)
+	}
+	if len(batch) != 0 {
+		t.Fatalf("expected the backing-off feed to be skipped, got %+v", batch)
+	}
+
+	if err := fetches.Succeeded(ctx, feed.ID); err != nil {
+		t.Fatalf("Succeeded returned error: %v", err)
+	}
+	stored, err = db.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if stored.ConsecutiveFailures != 0 || stored.NextRetryAt.Valid {
+		t.Fatalf("expected a success to reset the backoff, got %d / %+v", stored.ConsecutiveFailures, stored.NextRetryAt)
+	}
+}
+
+func TestDBFeedFetches_HonorsLongerRetryAfter(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "Busy", "https://busy.example.com/feed")
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetches := dbFeedFetches{db: db, now: func() time.Time { return now }}
+
+	tooMany := &rss.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	if err := fetches.Failed(ctx, feed.ID, fmt.Errorf("fetching: %w", tooMany)); err != nil {
+		t.Fatalf("Failed returned error: %v", err)
+	}
+	stored, err := db.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if !stored.NextRetryAt.Valid || !stored.NextRetryAt.Time.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected the retry an hour out as Retry-After asked, got %+v", stored.NextRetryAt)
+	}
+
+	// A Retry-After shorter than the backoff doesn't shorten it
+	tooMany.RetryAfter = time.Minute
+	if err := fetches.Failed(ctx, feed.ID, tooMany); err != nil {
+		t.Fatalf("Failed returned error: %v", err)
+	}
+	stored, err = db.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if !stored.NextRetryAt.Valid || !stored.NextRetryAt.Time.Equal(now.Add(feedRetryDelay(2))) {
+		t.Fatalf("expected the 20 minute backoff to stand, got %+v", stored.NextRetryAt)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedCredentialSource looks up the Basic Auth credentials of private feeds
+type FeedCredentialSource interface {
+	// Credentials returns the feed's credentials, or ok false if it has none
+	Credentials(ctx context.Context, feedID uuid.UUID) (auth rss.BasicAuth, ok bool, err error)
+}
+
+// dbFeedCredentials is a FeedCredentialSource backed by the feed_credentials table
+type dbFeedCredentials struct {
+	db *database.Queries
+}
+
+func (c dbFeedCredentials) Credentials(ctx context.Context, feedID uuid.UUID) (rss.BasicAuth, bool, error) {
+	cred, err := c.db.GetFeedCredentials(ctx, feedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rss.BasicAuth{}, false, nil
+	}
+	if err != nil {
+		return rss.BasicAuth{}, false, err
+	}
+	return basicAuth(cred), true, nil
+}
+
+// basicAuth turns stored credentials into the ones to send, reading the
+// password from its environment variable when one is named
+func basicAuth(cred database.FeedCredential) rss.BasicAuth {
+	password := cred.Password
+	if cred.PasswordEnv != "" {
+		password = os.Getenv(cred.PasswordEnv)
+	}
+	return rss.BasicAuth{Username: cred.Username, Password: password}
+}
+
+// parseCredentialFlags extracts --user with --pass or --pass-env from args.
+// ok is false when none of them is given.
+func parseCredentialFlags(args []string) (cred database.FeedCredential, ok bool, rest []string, err error) {
+	username, rest, err := flagValue(args, "--user")
+	if err != nil {
+		return cred, false, nil, err
+	}
+	password, rest, err := flagValue(rest, "--pass")
+	if err != nil {
+		return cred, false, nil, err
+	}
+	passwordEnv, rest, err := flagValue(rest, "--pass-env")
+	if err != nil {
+		return cred, false, nil, err
+	}
+
+	if username == "" && password == "" && passwordEnv == "" {
+		return cred, false, rest, nil
+	}
+	if username == "" {
+		return cred, false, nil, fmt.Errorf("--pass and --pass-env need --user")
+	}
+	if (password == "") == (passwordEnv == "") {
+		return cred, false, nil, fmt.Errorf("--user needs exactly one of --pass or --pass-env")
+	}
+	cred = database.FeedCredential{
+		Username:    username,
+		Password:    password,
+		PasswordEnv: passwordEnv,
+	}
+	return cred, true, rest, nil
+}
+
+// saveFeedCredentials stores cred as the Basic Auth credentials of feedID
+func saveFeedCredentials(ctx context.Context, db *database.Queries, feedID uuid.UUID, cred database.FeedCredential) error {
+	err := db.SetFeedCredentials(ctx, database.SetFeedCredentialsParams{
+		FeedID:      feedID,
+		Username:    cred.Username,
+		Password:    cred.Password,
+		PasswordEnv: cred.PasswordEnv,
+		UpdatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't save feed credentials: %w", err)
+	}
+	return nil
+}
+
+// saveOwnFeedCredentials stores cred as feed's credentials on behalf of user,
+// who must own the feed: its credentials are used for everyone following it
+func saveOwnFeedCredentials(s *state, user database.User, feed database.Feed, cred database.FeedCredential) error {
+	if feed.UserID != user.ID {
+		return fmt.Errorf("only the feed's owner can set its credentials; %s isn't owned by %s", feed.Url, user.Name)
+	}
+	if err := saveFeedCredentials(context.Background(), s.db, feed.ID, cred); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Saved credentials for %s\n", feed.Name)
+	return nil
+}
+
+// withFeedCredentials adds feed's credentials from config.Credentials, if it
+// has any, to ctx. A failed lookup is logged and the feed fetched without them.
+func withFeedCredentials(ctx context.Context, feed database.GetFeedsWithUsersRow, config *AggregationConfig) context.Context {
+	if config.Credentials == nil {
+		return ctx
+	}
+	auth, ok, err := config.Credentials.Credentials(ctx, feed.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error looking up credentials for feed %s: %v\n", feed.Url, err)
+		return ctx
+	}
+	if !ok {
+		return ctx
+	}
+	return rss.WithBasicAuth(ctx, auth)
+}
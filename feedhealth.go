@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedHealthRecorder tracks feeds that used to have posts but now fetch
+// successfully with no items, which usually means the feed moved or changed format
+type FeedHealthRecorder interface {
+	HasPosts(ctx context.Context, feedID uuid.UUID) (bool, error)
+	Flag(ctx context.Context, feedID uuid.UUID, suggestedURL string) error
+	Clear(ctx context.Context, feedID uuid.UUID) error
+}
+
+// dbFeedHealth is a FeedHealthRecorder backed by the feed_health table
+type dbFeedHealth struct {
+	db *database.Queries
+}
+
+func (h dbFeedHealth) HasPosts(ctx context.Context, feedID uuid.UUID) (bool, error) {
+	return h.db.FeedHasPosts(ctx, feedID)
+}
+
+func (h dbFeedHealth) Flag(ctx context.Context, feedID uuid.UUID, suggestedURL string) error {
+	return h.db.FlagFeedPossiblyMoved(ctx, database.FlagFeedPossiblyMovedParams{
+		FeedID:       feedID,
+		FlaggedAt:    time.Now().UTC(),
+		SuggestedUrl: sql.NullString{String: suggestedURL, Valid: suggestedURL != ""},
+	})
+}
+
+func (h dbFeedHealth) Clear(ctx context.Context, feedID uuid.UUID) error {
+	return h.db.ClearFeedHealthFlag(ctx, feedID)
+}
+
+// checkFeedHealth flags feed as possibly moved if it fetched with no items but
+// previously had posts, clearing any earlier flag otherwise. When the feed URL
+// now serves an HTML page advertising a different feed, that URL is suggested.
+// It reports whether the feed was flagged.
+func checkFeedHealth(ctx context.Context, feed database.GetFeedsWithUsersRow, rssFeed *rss.RSSFeed, config *AggregationConfig) bool {
+	if len(rssFeed.Channel.Items) > 0 {
+		if err := config.Health.Clear(ctx, feed.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing health flag for feed %s: %v\n", feed.Url, err)
+		}
+		return false
+	}
+
+	hasPosts, err := config.Health.HasPosts(ctx, feed.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error checking posts for feed %s: %v\n", feed.Url, err)
+		return false
+	}
+	if !hasPosts {
+		return false
+	}
+
+	suggested := ""
+	if discovered, err := config.Discover(ctx, config.Client, feed.Url); err == nil && rss.NormalizeURL(discovered) != rss.NormalizeURL(feed.Url) {
+		suggested = discovered
+	}
+
+	if err := config.Health.Flag(ctx, feed.ID, suggested); err != nil {
+		fmt.Fprintf(os.Stderr, "Error flagging feed %s: %v\n", feed.Url, err)
+		return false
+	}
+	return true
+}
+
+// FeedMoveRecorder updates the stored URL of feeds that permanently redirect
+// to a new one
+type FeedMoveRecorder interface {
+	Moved(ctx context.Context, feedID uuid.UUID, newURL string) error
+}
+
+// dbFeedMoves is a FeedMoveRecorder backed by the feeds table
+type dbFeedMoves struct {
+	db *database.Queries
+}
+
+func (m dbFeedMoves) Moved(ctx context.Context, feedID uuid.UUID, newURL string) error {
+	return m.db.UpdateFeedURL(ctx, database.UpdateFeedURLParams{ID: feedID, Url: newURL})
+}
+
+// recordFeedMove stores the URL feed was permanently redirected to, reporting
+// whether it was updated. A URL already used by another feed is left alone.
+func recordFeedMove(ctx context.Context, feed database.GetFeedsWithUsersRow, rssFeed *rss.RSSFeed, config *AggregationConfig) bool {
+	if !rssFeed.MovedPermanently || rssFeed.FinalURL == "" || rssFeed.FinalURL == feed.Url {
+		return false
+	}
+	if err := config.Moves.Moved(ctx, feed.ID, rssFeed.FinalURL); err != nil {
+		if isUniqueViolation(err) {
+			fmt.Fprintf(os.Stderr, "Feed %s moved to %s, which is already another feed; not updating it\n", feed.Url, rssFeed.FinalURL)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error updating URL of feed %s: %v\n", feed.Url, err)
+		}
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Feed %s moved permanently; now using %s\n", feed.Url, rssFeed.FinalURL)
+	return true
+}
+
+// duplicatePost is one post in a group of likely duplicates
+type duplicatePost struct {
+	ID       uuid.UUID
+	Title    string
+	URL      string
+	FeedName string
+}
+
+// duplicateGroup is a set of posts sharing a normalized URL or title
+type duplicateGroup struct {
+	Reason string // "url" or "title"
+	Key    string
+	Posts  []duplicatePost
+}
+
+// findDuplicatePosts groups posts that share a normalized URL, then posts that
+// share a normalized title. A post can appear in both kinds of group.
+func findDuplicatePosts(ctx context.Context, db *database.Queries) ([]duplicateGroup, error) {
+	var groups []duplicateGroup
+	add := func(reason, key string, post duplicatePost) {
+		if n := len(groups); n == 0 || groups[n-1].Reason != reason || groups[n-1].Key != key {
+			groups = append(groups, duplicateGroup{Reason: reason, Key: key})
+		}
+		last := &groups[len(groups)-1]
+		last.Posts = append(last.Posts, post)
+	}
+
+	byURL, err := db.GetDuplicatePostsByURL(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find duplicate URLs: %w", err)
+	}
+	for _, row := range byURL {
+		add("url", row.DupKey, duplicatePost{ID: row.ID, Title: row.Title, URL: row.Url, FeedName: row.FeedName})
+	}
+
+	byTitle, err := db.GetDuplicatePostsByTitle(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find duplicate titles: %w", err)
+	}
+	for _, row := range byTitle {
+		add("title", row.DupKey, duplicatePost{ID: row.ID, Title: row.Title, URL: row.Url, FeedName: row.FeedName})
+	}
+	return groups, nil
+}
+
+// handlerDoctorDuplicates reports groups of likely duplicate posts
+func handlerDoctorDuplicates(s *state) error {
+	groups, err := findDuplicatePosts(context.Background(), s.db)
+	if err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintln(s.out, "No duplicate posts found.")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "%d groups of possibly duplicate posts:\n", len(groups))
+	for _, group := range groups {
+		fmt.Fprintf(s.out, "* Same %s: %s\n", group.Reason, group.Key)
+		for _, post := range group.Posts {
+			fmt.Fprintf(s.out, "  %s  %s [%s] %s\n", post.ID, post.Title, post.FeedName, post.URL)
+		}
+	}
+	return nil
+}
+
+// handlerDoctor reports feeds that look broken or moved, or with
+// --duplicates, posts that look like duplicates of each other
+func handlerDoctor(s *state, cmd command) error {
+	if duplicates, _ := hasFlag(cmd.args, "--duplicates"); duplicates {
+		return handlerDoctorDuplicates(s)
+	}
+
+	flagged, err := s.db.GetFlaggedFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve feed health: %w", err)
+	}
+
+	if len(flagged) == 0 {
+		fmt.Fprintln(s.out, "No problems found.")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "%d feeds may be broken or moved:\n", len(flagged))
+	for _, feed := range flagged {
+		fmt.Fprintf(s.out, "* %s (%s)\n", feed.Name, feed.Url)
+		fmt.Fprintf(s.out, "  Returned no items on %s but previously had posts\n", feed.FlaggedAt.Format("2006-01-02 15:04"))
+		if feed.SuggestedUrl.Valid {
+			fmt.Fprintf(s.out, "  Suggested new URL: %s\n", feed.SuggestedUrl.String)
+		}
+	}
+	return nil
+}
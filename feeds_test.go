@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/rss"
+
+	"github.com/google/uuid"
+)
+
+func TestMoveFeed_ChangesOwnerAndKeepsPostsAndFollows(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	carol := createTestUser(t, db, "carol")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+	followTestFeed(t, db, carol, feed)
+	post := createTestPost(t, db, feed, "hello", "https://blog.example.com/hello", time.Now())
+
+	moved, err := moveFeed(context.Background(), db, feed.Url, "bob")
+	if err != nil {
+		t.Fatalf("moveFeed returned error: %v", err)
+	}
+	if moved.ID != feed.ID || moved.UserID != bob.ID {
+		t.Fatalf("expected feed %s to be owned by bob, got owner %s", feed.ID, moved.UserID)
+	}
+
+	stored, err := db.GetFeedByURL(context.Background(), feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if stored.UserID != bob.ID {
+		t.Fatalf("expected stored owner to be bob, got %s", stored.UserID)
+	}
+
+	posts, err := db.GetPostsForFeed(context.Background(), database.GetPostsForFeedParams{FeedID: feed.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPostsForFeed returned error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != post.ID {
+		t.Fatalf("expected post to be untouched, got %v", posts)
+	}
+
+	for _, follower := range []string{"alice", "carol"} {
+		user, _ := db.GetUser(context.Background(), follower)
+		follows, err := db.GetFeedFollowsForUser(context.Background(), user.ID)
+		if err != nil {
+			t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+		}
+		if len(follows) != 1 || follows[0].FeedID != feed.ID {
+			t.Fatalf("expected %s to still follow the feed, got %v", follower, follows)
+		}
+	}
+}
+
+func TestMoveFeed_ValidatesFeedAndUser(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+
+	if _, err := moveFeed(context.Background(), db, "https://missing.example.com/feed.xml", "alice"); err == nil {
+		t.Fatalf("expected error for unknown feed")
+	}
+	if _, err := moveFeed(context.Background(), db, feed.Url, "nobody"); err == nil {
+		t.Fatalf("expected error for unknown user")
+	}
+}
+
+func TestGetUnfollowedFeeds_ExcludesFollowedFeeds(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	carol := createTestUser(t, db, "carol")
+	followed := createTestFeed(t, db, alice, "Followed", "https://followed.example.com/feed.xml")
+	popular := createTestFeed(t, db, bob, "Popular", "https://popular.example.com/feed.xml")
+	quiet := createTestFeed(t, db, bob, "Quiet", "https://quiet.example.com/feed.xml")
+	followTestFeed(t, db, alice, followed)
+	followTestFeed(t, db, bob, popular)
+	followTestFeed(t, db, carol, popular)
+
+	feeds, err := db.GetUnfollowedFeeds(context.Background(), alice.ID)
+	if err != nil {
+		t.Fatalf("GetUnfollowedFeeds returned error: %v", err)
+	}
+
+	if len(feeds) != 2 {
+		t.Fatalf("expected 2 unfollowed feeds, got %+v", feeds)
+	}
+	if feeds[0].ID != popular.ID || feeds[0].FollowerCount != 2 || feeds[0].OwnerName != "bob" {
+		t.Fatalf("expected Popular first with 2 followers, got %+v", feeds[0])
+	}
+	if feeds[1].ID != quiet.ID || feeds[1].FollowerCount != 0 {
+		t.Fatalf("expected Quiet second with no followers, got %+v", feeds[1])
+	}
+}
+
+func TestCountUnreadPerFeed_CountsUnreadPostsByFeed(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	busy := createTestFeed(t, db, alice, "Busy", "https://busy.example.com/feed.xml")
+	caughtUp := createTestFeed(t, db, alice, "Caught up", "https://caughtup.example.com/feed.xml")
+	empty := createTestFeed(t, db, alice, "Empty", "https://empty.example.com/feed.xml")
+	unfollowed := createTestFeed(t, db, bob, "Unfollowed", "https://unfollowed.example.com/feed.xml")
+	for _, feed := range []database.Feed{caughtUp, busy, empty} {
+		followTestFeed(t, db, alice, feed)
+	}
+
+	now := time.Now().UTC()
+	read := createTestPost(t, db, busy, "read", "https://busy.example.com/1", now)
+	createTestPost(t, db, busy, "unread 1", "https://busy.example.com/2", now)
+	createTestPost(t, db, busy, "unread 2", "https://busy.example.com/3", now)
+	done := createTestPost(t, db, caughtUp, "done", "https://caughtup.example.com/1", now)
+	createTestPost(t, db, unfollowed, "elsewhere", "https://unfollowed.example.com/1", now)
+	for _, post := range []database.Post{read, done} {
+		if _, err := db.MarkPostRead(ctx, database.MarkPostReadParams{UserID: alice.ID, PostID: post.ID, ReadAt: now}); err != nil {
+			t.Fatalf("MarkPostRead returned error: %v", err)
+		}
+	}
+	// Another user's reads don't count
+	if _, err := db.MarkPostRead(ctx, database.MarkPostReadParams{UserID: bob.ID, PostID: read.ID, ReadAt: now}); err != nil {
+		t.Fatalf("MarkPostRead returned error: %v", err)
+	}
+
+	feeds, err := db.CountUnreadPerFeed(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("CountUnreadPerFeed returned error: %v", err)
+	}
+	if len(feeds) != 3 {
+		t.Fatalf("expected alice's 3 followed feeds, got %+v", feeds)
+	}
+	if feeds[0].FeedID != busy.ID || feeds[0].UnreadCount != 2 {
+		t.Fatalf("expected Busy first with 2 unread, got %+v", feeds[0])
+	}
+	// Ties are broken by name
+	if feeds[1].FeedID != caughtUp.ID || feeds[1].UnreadCount != 0 || feeds[2].FeedID != empty.ID || feeds[2].UnreadCount != 0 {
+		t.Fatalf("expected Caught up then Empty with 0 unread, got %+v", feeds[1:])
+	}
+
+	s, out, _ := newTestState(db, false)
+	if err := handlerFollowing(s, command{name: "following", args: []string{"--counts"}}, alice); err != nil {
+		t.Fatalf("handlerFollowing returned error: %v", err)
+	}
+	want := "You're following 3 feeds:\n* Busy (2 unread)\n* Caught up (0 unread)\n* Empty (0 unread)\n"
+	if out.String() != want {
+		t.Fatalf("output = %q; want %q", out.String(), want)
+	}
+}
+
+func TestDBFeedSource_StalestFirst(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	oldest := createTestFeed(t, db, alice, "Oldest", "https://oldest.example.com/feed")
+	newest := createTestFeed(t, db, alice, "Newest", "https://newest.example.com/feed")
+	createTestFeed(t, db, alice, "Never 1", "https://never1.example.com/feed")
+	createTestFeed(t, db, alice, "Never 2", "https://never2.example.com/feed")
+
+	for _, feed := range []database.Feed{oldest, newest} {
+		if err := db.MarkFeedFetched(ctx, feed.ID); err != nil {
+			t.Fatalf("MarkFeedFetched returned error: %v", err)
+		}
+		// Keep the two fetch times apart
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	source := dbFeedSource(db)
+	first, err := source(ctx, uuid.Nil, 2)
+	if err != nil {
+		t.Fatalf("source returned error: %v", err)
+	}
+	if len(first) != 2 || !strings.HasPrefix(first[0].Name, "Never") || !strings.HasPrefix(first[1].Name, "Never") {
+		t.Fatalf("expected the never-fetched feeds first, got %+v", first)
+	}
+
+	// A feed fetched during the run isn't handed out again
+	if err := db.MarkFeedFetched(ctx, first[0].ID); err != nil {
+		t.Fatalf("MarkFeedFetched returned error: %v", err)
+	}
+	second, err := source(ctx, first[1].ID, 2)
+	if err != nil {
+		t.Fatalf("source returned error: %v", err)
+	}
+	if len(second) != 2 || second[0].ID != oldest.ID || second[1].ID != newest.ID {
+		t.Fatalf("expected oldest, then newest; got %+v", second)
+	}
+	rest, err := source(ctx, second[1].ID, 2)
+	if err != nil {
+		t.Fatalf("source returned error: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("expected no more feeds, got %+v", rest)
+	}
+
+	// The next run starts over, with the feed fetched last at the end
+	next, err := source(ctx, uuid.Nil, 10)
+	if err != nil {
+		t.Fatalf("source returned error: %v", err)
+	}
+	if len(next) != 4 || next[0].ID != first[1].ID || next[3].ID != first[0].ID {
+		t.Fatalf("expected the remaining never-fetched feed first and the feed fetched last at the end, got %+v", next)
+	}
+}
+
+func TestDeleteFeed_RemovesPostsAndFollows(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+	followTestFeed(t, db, bob, feed)
+	post := createTestPost(t, db, feed, "hello", "https://blog.example.com/hello", time.Now())
+	bookmarkTestPost(t, db, bob, post)
+	likeTestPost(t, db, bob, post)
+
+	if _, err := deleteFeed(ctx, db, bob, feed.Url); err == nil || !strings.Contains(err.Error(), "only the feed's owner") {
+		t.Fatalf("expected a non-owner to be refused, got %v", err)
+	}
+	if _, err := db.GetPostByID(ctx, post.ID); err != nil {
+		t.Fatalf("expected post to survive a refused delete: %v", err)
+	}
+
+	deleted, err := deleteFeed(ctx, db, alice, feed.Url)
+	if err != nil {
+		t.Fatalf("deleteFeed returned error: %v", err)
+	}
+	if deleted.ID != feed.ID {
+		t.Fatalf("expected feed %s to be deleted, got %s", feed.ID, deleted.ID)
+	}
+
+	if _, err := db.GetFeedByURL(ctx, feed.Url); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected feed to be gone, got %v", err)
+	}
+	if _, err := db.GetPostByID(ctx, post.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected feed's posts to be gone, got %v", err)
+	}
+	follows, err := db.GetFeedFollowsForUser(ctx, bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 0 {
+		t.Fatalf("expected follows of the deleted feed to be gone, got %+v", follows)
+	}
+
+	if _, err := deleteFeed(ctx, db, alice, feed.Url); err == nil || !strings.Contains(err.Error(), "feed not found") {
+		t.Fatalf("expected deleting a missing feed to fail, got %v", err)
+	}
+}
+
+func TestRenameFeed_UpdatesNameForOwnerOnly(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "blog feed", "https://blog.example.com/feed.xml")
+	post := createTestPost(t, db, feed, "hello", "https://blog.example.com/hello", time.Now())
+
+	if _, _, err := renameFeed(ctx, db, bob, feed.Url, "Bob's Blog"); err == nil || !strings.Contains(err.Error(), "only the feed's owner") {
+		t.Fatalf("expected a non-owner to be refused, got %v", err)
+	}
+	if _, _, err := renameFeed(ctx, db, alice, "https://missing.example.com/feed", "Nope"); err == nil || !strings.Contains(err.Error(), "feed not found") {
+		t.Fatalf("expected a missing feed to be reported, got %v", err)
+	}
+
+	s, out, _ := newTestState(db, false)
+	if err := handlerRenameFeed(s, command{name: "renamefeed", args: []string{feed.Url, "Alice's", "Blog"}}, alice); err != nil {
+		t.Fatalf("handlerRenameFeed returned error: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, `"blog feed"`) || !strings.Contains(got, `"Alice's Blog"`) {
+		t.Fatalf("expected old and new names in output, got %q", got)
+	}
+
+	stored, err := db.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		t.Fatalf("GetFeedByURL returned error: %v", err)
+	}
+	if stored.Name != "Alice's Blog" || !stored.UpdatedAt.After(feed.UpdatedAt) {
+		t.Fatalf("expected name and updated_at to change, got %+v", stored)
+	}
+	if _, err := db.GetPostByID(ctx, post.ID); err != nil {
+		t.Fatalf("expected posts to survive a rename: %v", err)
+	}
+}
+
+func TestSaveFeedMetadata_UsesChannelTitleOnlyWithoutAName(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	unnamed := createTestFeed(t, db, alice, "https://blog.example.com/feed.xml", "https://blog.example.com/feed.xml")
+	named := createTestFeed(t, db, alice, "My blog", "https://other.example.com/feed.xml")
+	rssFeed := &rss.RSSFeed{Channel: rss.RSSChannel{Title: " Example Blog ", Description: "Notes on Go"}}
+
+	updated, err := rss.SaveFeedMetadata(ctx, db, unnamed, rssFeed, false)
+	if err != nil {
+		t.Fatalf("SaveFeedMetadata returned error: %v", err)
+	}
+	if updated.Name != "Example Blog" || updated.Description.String != "Notes on Go" {
+		t.Fatalf("got name %q, description %q; want the channel's title and description", updated.Name, updated.Description.String)
+	}
+
+	updated, err = rss.SaveFeedMetadata(ctx, db, named, rssFeed, true)
+	if err != nil {
+		t.Fatalf("SaveFeedMetadata returned error: %v", err)
+	}
+	if updated.Name != "My blog" || updated.Description.String != "Notes on Go" {
+		t.Fatalf("got name %q, description %q; want the given name kept", updated.Name, updated.Description.String)
+	}
+
+	followTestFeed(t, db, alice, named)
+	follows, err := db.GetFeedFollowsForUser(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 1 || follows[0].FeedDescription.String != "Notes on Go" {
+		t.Fatalf("expected the description on the followed feed, got %v", follows)
+	}
+}
+
+func TestHandlerFollow_FindsFeedUnderAnotherSpelling(t *testing.T) {
+	db := openTestQueries(t)
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed")
+
+	s, out, _ := newTestState(db, false)
+	err := handlerFollow(s, command{name: "follow", args: []string{"http://Blog.example.com:80/feed/?utm_source=newsletter"}}, bob)
+	if err != nil {
+		t.Fatalf("handlerFollow returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Now following Blog as bob") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	follows, err := db.GetFeedFollowsForUser(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 1 || follows[0].FeedID != feed.ID {
+		t.Fatalf("expected bob to follow the stored feed, got %+v", follows)
+	}
+}
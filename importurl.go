@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// subscriptionImportResult summarizes an import of a subscription list
+type subscriptionImportResult struct {
+	// Added counts feeds that were new to the database
+	Added int
+	// Followed counts feeds that were already stored and are now followed
+	Followed         int
+	AlreadyFollowing int
+	// Skipped lists entries that aren't http(s) URLs
+	Skipped []string
+}
+
+// importSubscriptions follows every feed in subscriptions, adding the ones
+// not stored yet. Entries repeating an earlier one's normalized URL are
+// ignored.
+func importSubscriptions(ctx context.Context, conn *sql.DB, db *database.Queries, user database.User, subscriptions []rss.Subscription) (subscriptionImportResult, error) {
+	var result subscriptionImportResult
+	seen := make(map[string]bool)
+
+	for _, sub := range subscriptions {
+		feedURL := rss.NormalizeURL(sub.URL)
+		if u, err := url.Parse(feedURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result.Skipped = append(result.Skipped, sub.URL)
+			continue
+		}
+		if seen[feedURL] {
+			continue
+		}
+		seen[feedURL] = true
+
+		feed, err := rss.LookupFeed(ctx, db, feedURL)
+		if errors.Is(err, sql.ErrNoRows) {
+			name := sub.Title
+			if name == "" {
+				name = feedURL
+			}
+			if _, _, err := addAndFollowFeed(ctx, conn, user, name, feedURL); err != nil {
+				return result, err
+			}
+			result.Added++
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("database error while looking up feed with URL %s: %w", feedURL, err)
+		}
+
+		_, err = db.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				result.AlreadyFollowing++
+				continue
+			}
+			return result, fmt.Errorf("couldn't follow feed %s: %w", feedURL, err)
+		}
+		result.Followed++
+	}
+
+	return result, nil
+}
+
+// handlerImportURL downloads an OPML or JSON subscription list, such as
+// another reader's export endpoint, and follows every feed in it
+func handlerImportURL(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("import-url requires a url argument")
+	}
+	listURL := cmd.args[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	subscriptions, err := rss.FetchSubscriptions(ctx, rss.NewHTTPClient(), listURL, rss.FetchOptions{UserAgent: s.cfg.FetchUserAgent()})
+	if err != nil {
+		return fmt.Errorf("couldn't download subscription list: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return fmt.Errorf("no feeds found in %s", listURL)
+	}
+
+	result, err := importSubscriptions(context.Background(), s.conn, s.db, user, subscriptions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Added %d new feeds and followed %d existing ones (%d already followed)\n", result.Added, result.Followed, result.AlreadyFollowing)
+	for _, entry := range result.Skipped {
+		fmt.Fprintf(s.out, "Skipped invalid feed URL: %s\n", entry)
+	}
+	if result.Added > 0 {
+		fmt.Fprintln(s.out, "Run `gator agg` to fetch posts from the new feeds.")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+)
+
+func TestHandlerImportURL_FollowsFeedsFromOPML(t *testing.T) {
+	conn := dbtest.Open(t)
+	db := database.New(conn)
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	existing := createTestFeed(t, db, alice, "Go Blog", "https://go.dev/blog/feed.atom")
+	followed := createTestFeed(t, db, alice, "Followed", "https://followed.example.com/feed")
+	followTestFeed(t, db, bob, followed)
+
+	opml := `<?xml version="1.0"?>
+<opml version="1.0"><body>
+  <outline text="Go Blog" xmlUrl="http://go.dev/blog/feed.atom/"/>
+  <outline text="Followed" xmlUrl="https://followed.example.com/feed"/>
+  <outline text="News">
+    <outline text="New Feed" xmlUrl="https://new.example.com/rss?utm_source=export"/>
+    <outline text="New Feed again" xmlUrl="https://new.example.com/rss"/>
+  </outline>
+  <outline text="Broken" xmlUrl="mailto:someone@example.com"/>
+</body></opml>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+		w.Write([]byte(opml))
+	}))
+	defer server.Close()
+
+	s, out, _ := newTestState(db, false)
+	s.conn = conn
+	if err := handlerImportURL(s, command{name: "import-url", args: []string{server.URL + "/export.opml"}}, bob); err != nil {
+		t.Fatalf("handlerImportURL returned error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "Added 1 new feeds and followed 1 existing ones (1 already followed)") || !strings.Contains(got, "Skipped invalid feed URL: mailto:someone@example.com") {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	follows, err := db.GetFeedFollowsForUser(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	urls := map[string]bool{}
+	for _, follow := range follows {
+		urls[follow.FeedUrl] = true
+	}
+	if len(follows) != 3 || !urls[existing.Url] || !urls[followed.Url] || !urls["https://new.example.com/rss"] {
+		t.Fatalf("unexpected follows after import: %+v", follows)
+	}
+}
@@ -3,9 +3,10 @@ package api
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"gator/internal/database"
 	"net/http"
@@ -26,8 +27,8 @@ type AuthenticatedUser struct {
 	Name string    `json:"name"`
 }
 
-// generateAPIKey generates a secure random API key
-func generateAPIKey() (string, error) {
+// GenerateAPIKey generates a secure random API key
+func GenerateAPIKey() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
@@ -35,9 +36,22 @@ func generateAPIKey() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// HashAPIKey returns the hex-encoded SHA-256 hash that is stored in place of
+// apiKey. Keys are random and high-entropy, so a plain hash is enough.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
 // requireAuth is middleware that requires API key authentication
 func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// The rate limiter may have verified the key already
+		if _, ok := r.Context().Value(userContextKey).(AuthenticatedUser); ok {
+			next(w, r)
+			return
+		}
+
 		// Get API key from Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -58,9 +72,9 @@ func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Look up user by API key
-		user, err := s.db.GetUserByAPIKey(context.Background(), sql.NullString{
-			String: apiKey,
+		// Look up user by the hash of the API key
+		user, err := s.db.GetUserByAPIKeyHash(context.Background(), sql.NullString{
+			String: HashAPIKey(apiKey),
 			Valid:  true,
 		})
 		if err != nil {
@@ -93,39 +107,45 @@ type registerRequest struct {
 }
 
 type registerResponse struct {
+	Versioned
 	User   AuthenticatedUser `json:"user"`
 	APIKey string            `json:"api_key"`
 }
 
 func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
 	var req registerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	if req.Name == "" {
-		s.respondWithError(w, http.StatusBadRequest, "Name is required")
+	errs := fieldErrors{}
+	errs.username("name", req.Name)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
 
 	// Generate API key
-	apiKey, err := generateAPIKey()
+	apiKey, err := GenerateAPIKey()
 	if err != nil {
 		s.respondWithError(w, http.StatusInternalServerError, "Failed to generate API key")
 		return
 	}
 
-	// Create user
-	user, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      req.Name,
-		ApiKey:    sql.NullString{String: apiKey, Valid: true},
+	// Create user; a taken name yields no row rather than a constraint error
+	user, err := s.db.CreateUserIfNotExists(context.Background(), database.CreateUserIfNotExistsParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+		Name:       req.Name,
+		ApiKeyHash: sql.NullString{String: HashAPIKey(apiKey), Valid: true},
 	})
 	if err != nil {
-		s.respondWithError(w, http.StatusConflict, "User already exists")
+		if errors.Is(err, sql.ErrNoRows) {
+			s.respondWithError(w, http.StatusConflict, "User already exists")
+			return
+		}
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to create user")
 		return
 	}
 
@@ -137,7 +157,7 @@ func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
 		APIKey: apiKey,
 	}
 
-	s.respondWithJSON(w, http.StatusCreated, response)
+	s.respondWithJSON(w, http.StatusCreated, &response)
 }
 
 type loginRequest struct {
@@ -145,19 +165,21 @@ type loginRequest struct {
 }
 
 type loginResponse struct {
+	Versioned
 	User   AuthenticatedUser `json:"user"`
 	APIKey string            `json:"api_key"`
 }
 
 func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	if req.Name == "" {
-		s.respondWithError(w, http.StatusBadRequest, "Name is required")
+	errs := fieldErrors{}
+	errs.required("name", req.Name)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
 
@@ -169,16 +191,16 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate new API key
-	apiKey, err := generateAPIKey()
+	apiKey, err := GenerateAPIKey()
 	if err != nil {
 		s.respondWithError(w, http.StatusInternalServerError, "Failed to generate API key")
 		return
 	}
 
-	// Update user's API key
+	// Update user's API key; only its hash is stored
 	err = s.db.UpdateUserAPIKey(context.Background(), database.UpdateUserAPIKeyParams{
-		ID:     user.ID,
-		ApiKey: sql.NullString{String: apiKey, Valid: true},
+		ID:         user.ID,
+		ApiKeyHash: sql.NullString{String: HashAPIKey(apiKey), Valid: true},
 	})
 	if err != nil {
 		s.respondWithError(w, http.StatusInternalServerError, "Failed to update API key")
@@ -193,5 +215,5 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		APIKey: apiKey,
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithJSON(w, http.StatusOK, &response)
 }
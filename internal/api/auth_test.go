@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+)
+
+func TestHandleRegister_ConcurrentSameName(t *testing.T) {
+	db := dbtest.Open(t)
+	s := NewServer(database.New(db), "0")
+
+	const attempts = 2
+	codes := make([]int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "racer"}`))
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		default:
+			t.Fatalf("unexpected status code %d", code)
+		}
+	}
+	if created != 1 || conflicts != 1 {
+		t.Fatalf("expected exactly one success and one conflict, got codes %v", codes)
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	// SHA-256 of "abc"
+	const want = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashAPIKey("abc"); got != want {
+		t.Fatalf("HashAPIKey(abc) = %q; want %q", got, want)
+	}
+	if HashAPIKey("abc") == HashAPIKey("abd") {
+		t.Fatalf("expected different keys to hash differently")
+	}
+}
+
+func TestAPIKeys_StoredHashedAndLookedUpByHash(t *testing.T) {
+	queries := database.New(dbtest.Open(t))
+	s := NewServer(queries, "0")
+
+	post := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name": "hasher"}`))
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+	me := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/me", nil)
+		req.Header.Set("Authorization", "ApiKey "+key)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	rec := post("/api/auth/register")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register returned status %d", rec.Code)
+	}
+	var registered registerResponse
+	if err := json.NewDecoder(rec.Body).Decode(&registered); err != nil {
+		t.Fatalf("couldn't decode register response: %v", err)
+	}
+
+	user, err := queries.GetUser(context.Background(), "hasher")
+	if err != nil {
+		t.Fatalf("GetUser returned error: %v", err)
+	}
+	if user.ApiKeyHash.String == registered.APIKey || user.ApiKeyHash.String != HashAPIKey(registered.APIKey) {
+		t.Fatalf("expected only the key's hash to be stored, got %q", user.ApiKeyHash.String)
+	}
+
+	if code := me(registered.APIKey); code != http.StatusOK {
+		t.Fatalf("expected the plaintext key to authenticate, got %d", code)
+	}
+	if code := me(user.ApiKeyHash.String); code != http.StatusUnauthorized {
+		t.Fatalf("expected the stored hash to be rejected as a key, got %d", code)
+	}
+
+	// Logging in issues a new key and the old one stops working
+	rec = post("/api/auth/login")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login returned status %d", rec.Code)
+	}
+	var loggedIn loginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&loggedIn); err != nil {
+		t.Fatalf("couldn't decode login response: %v", err)
+	}
+	if code := me(loggedIn.APIKey); code != http.StatusOK {
+		t.Fatalf("expected the new key to authenticate, got %d", code)
+	}
+	if code := me(registered.APIKey); code != http.StatusUnauthorized {
+		t.Fatalf("expected the old key to be rejected, got %d", code)
+	}
+}
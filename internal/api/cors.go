@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Headers sent to allowed browser origins
+const (
+	corsAllowMethods = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders = "Authorization, Content-Type"
+	corsMaxAge       = "600" // seconds browsers may cache a preflight
+)
+
+// SetAllowedOrigins sets which browser origins may call the API, such as
+// "https://app.example.com". "*" allows any origin. With no origins, no CORS
+// headers are sent and browsers only allow same-origin requests.
+func (s *Server) SetAllowedOrigins(origins []string) {
+	s.allowedOrigins = nil
+	for _, origin := range origins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			s.allowedOrigins = append(s.allowedOrigins, origin)
+		}
+	}
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or ""
+// when it isn't allowed
+func (s *Server) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	if slices.Contains(s.allowedOrigins, "*") {
+		return "*"
+	}
+	if slices.Contains(s.allowedOrigins, origin) {
+		return origin
+	}
+	return ""
+}
+
+// cors adds CORS headers for allowed origins and answers preflight requests
+// with 204 before they reach the router
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		if allowed := s.allowOrigin(r.Header.Get("Origin")); allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
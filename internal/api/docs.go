@@ -27,11 +27,26 @@ const apiDocTemplate = `
     <p>Most endpoints require authentication using an API key. Include your API key in the Authorization header:</p>
     <pre>Authorization: ApiKey &lt;your-api-key&gt;</pre>
     
+    <h2>Responses</h2>
+    <p>Every JSON object in a response has a <code>schema_version</code> field that identifies the format, and errors are returned under <code>error</code>. List endpoints return a bare array unless the request sends a <code>Gator-Schema-Version</code> header with the version the client understands. The items are then returned under a key named after what they hold (<code>users</code>, <code>feeds</code>, <code>feed_follows</code>, <code>posts</code>, <code>bookmarks</code>, <code>likes</code>):</p>
+    <pre>{
+  "schema_version": 1,
+  "posts": [
+    {"id": "uuid-of-post", "title": "Post title", "url": "https://example.com/post"}
+  ]
+}</pre>
+    <p>The version is only bumped when a field is removed, renamed, or changes type. New fields may be added without a bump.</p>
+    
     <h2>Endpoints</h2>
     
     <div class="endpoint">
-        <h3><span class="method">GET</span> /health</h3>
-        <p>Health check endpoint</p>
+        <h3><span class="method">GET</span> /health/live</h3>
+        <p>Liveness probe: 200 whenever the server is running</p>
+    </div>
+    
+    <div class="endpoint">
+        <h3><span class="method">GET</span> /health/ready</h3>
+        <p>Readiness probe: 200 when the database is reachable, 503 otherwise. <code>/health</code> is an alias.</p>
     </div>
     
     <div class="endpoint">
@@ -68,13 +83,18 @@ const apiDocTemplate = `
     
     <div class="endpoint">
         <h3><span class="method">POST</span> /api/feeds <span class="auth">🔒 Auth Required</span></h3>
-        <p>Create a new feed</p>
+        <p>Create a new feed. The name is optional; without it the feed is named after its own title. If the feed is already stored under another spelling of the URL, it is followed and returned with status 200 instead</p>
         <pre>{
   "name": "Feed Name",
   "url": "https://example.com/feed.xml"
 }</pre>
     </div>
     
+    <div class="endpoint">
+        <h3><span class="method">DELETE</span> /api/feeds/{feedId} <span class="auth">🔒 Auth Required</span></h3>
+        <p>Delete a feed you own, along with its posts, follows, bookmarks, and likes. Returns 204, 403 if you don't own it, or 404 if it doesn't exist</p>
+    </div>
+    
     <div class="endpoint">
         <h3><span class="method">GET</span> /api/feed-follows <span class="auth">🔒 Auth Required</span></h3>
         <p>Get feeds you're following</p>
@@ -96,6 +116,11 @@ const apiDocTemplate = `
 }</pre>
     </div>
     
+    <div class="endpoint">
+        <h3><span class="method">DELETE</span> /api/feed-follows/{feedId} <span class="auth">🔒 Auth Required</span></h3>
+        <p>Unfollow a feed by its ID</p>
+    </div>
+    
     <div class="endpoint">
         <h3><span class="method">GET</span> /api/posts <span class="auth">🔒 Auth Required</span></h3>
         <p>Get posts from feeds you follow</p>
@@ -104,10 +129,22 @@ const apiDocTemplate = `
     
     <div class="endpoint">
         <h3><span class="method">GET</span> /api/posts/search <span class="auth">🔒 Auth Required</span></h3>
-        <p>Search posts</p>
+        <p>Search posts, most relevant first</p>
         <p>Query parameters: <code>q</code> (required), <code>page</code> (default: 1), <code>limit</code> (default: 10, max: 100)</p>
     </div>
     
+    <div class="endpoint">
+        <h3><span class="method">GET</span> /api/posts/recent <span class="auth">🔒 Auth Required</span></h3>
+        <p>Get the newest posts across all feeds, regardless of follows</p>
+        <p>Query parameters: <code>limit</code> (default: 10, max: 100)</p>
+    </div>
+    
+    <div class="endpoint">
+        <h3><span class="method">GET</span> /api/feed.xml <span class="auth">🔒 Auth Required</span></h3>
+        <p>Get posts from your followed feeds as an RSS 2.0 feed. Supports ETag/If-None-Match and Range requests</p>
+        <p>Query parameters: <code>limit</code> (default: 50, max: 100)</p>
+    </div>
+    
     <div class="endpoint">
         <h3><span class="method">GET</span> /api/bookmarks <span class="auth">🔒 Auth Required</span></h3>
         <p>Get your bookmarked posts</p>
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+func TestHandleDeleteFeedFollowByID(t *testing.T) {
+	queries := database.New(dbtest.Open(t))
+	s := NewServer(queries, "0")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "reader"}`))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register returned status %d", rec.Code)
+	}
+	var registered registerResponse
+	if err := json.NewDecoder(rec.Body).Decode(&registered); err != nil {
+		t.Fatalf("couldn't decode register response: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now,
+		Name: "Blog", Url: "https://blog.example.com/feed.xml", UserID: registered.User.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed: %v", err)
+	}
+	if _, err := queries.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now, UserID: registered.User.ID, FeedID: feed.ID,
+	}); err != nil {
+		t.Fatalf("couldn't follow feed: %v", err)
+	}
+
+	unfollow := func(feedID string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/feed-follows/"+feedID, nil)
+		req.Header.Set("Authorization", "ApiKey "+registered.APIKey)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := unfollow(feed.ID.String()); code != http.StatusNoContent {
+		t.Fatalf("expected 204 for unfollow, got %d", code)
+	}
+	follows, err := queries.GetFeedFollowsForUser(ctx, registered.User.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 0 {
+		t.Fatalf("expected no follows after unfollow, got %d", len(follows))
+	}
+
+	if code := unfollow(feed.ID.String()); code != http.StatusNotFound {
+		t.Fatalf("expected 404 when not following, got %d", code)
+	}
+	if code := unfollow("not-a-uuid"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid ID, got %d", code)
+	}
+}
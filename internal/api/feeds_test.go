@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+// registerTestUser registers name through the API and returns its response
+func registerTestUser(t *testing.T, s *Server, name string) registerResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "`+name+`"}`))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register %s returned status %d", name, rec.Code)
+	}
+	var registered registerResponse
+	if err := json.NewDecoder(rec.Body).Decode(&registered); err != nil {
+		t.Fatalf("couldn't decode register response: %v", err)
+	}
+	return registered
+}
+
+func TestHandleDeleteFeed(t *testing.T) {
+	queries := database.New(dbtest.Open(t))
+	s := NewServer(queries, "0")
+	owner := registerTestUser(t, s, "owner")
+	other := registerTestUser(t, s, "other")
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now,
+		Name: "Blog", Url: "https://blog.example.com/feed.xml", UserID: owner.User.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed: %v", err)
+	}
+	if _, err := queries.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now, UserID: other.User.ID, FeedID: feed.ID,
+	}); err != nil {
+		t.Fatalf("couldn't follow feed: %v", err)
+	}
+
+	deleteFeed := func(apiKey, feedID string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/feeds/"+feedID, nil)
+		req.Header.Set("Authorization", "ApiKey "+apiKey)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := deleteFeed(other.APIKey, feed.ID.String()); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a non-owner, got %d", code)
+	}
+	if _, err := queries.GetFeedByID(ctx, feed.ID); err != nil {
+		t.Fatalf("expected the feed to survive a non-owner's delete: %v", err)
+	}
+
+	if code := deleteFeed(owner.APIKey, feed.ID.String()); code != http.StatusNoContent {
+		t.Fatalf("expected 204 for the owner, got %d", code)
+	}
+	if _, err := queries.GetFeedByID(ctx, feed.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected the feed to be gone, got %v", err)
+	}
+	follows, err := queries.GetFeedFollowsForUser(ctx, other.User.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	if len(follows) != 0 {
+		t.Fatalf("expected follows to be deleted with the feed, got %+v", follows)
+	}
+
+	if code := deleteFeed(owner.APIKey, feed.ID.String()); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a deleted feed, got %d", code)
+	}
+	if code := deleteFeed(owner.APIKey, "not-a-uuid"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a malformed ID, got %d", code)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"gator/internal/database"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// outputFeedDefaultLimit and outputFeedMaxLimit bound the items in /api/feed.xml
+const (
+	outputFeedDefaultLimit = 50
+	outputFeedMaxLimit     = 100
+)
+
+type outputRSS struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	Channel outputChannel `xml:"channel"`
+}
+
+type outputChannel struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	Items       []outputItem `xml:"item"`
+}
+
+type outputItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description,omitempty"`
+	PubDate     string `xml:"pubDate,omitempty"`
+	GUID        string `xml:"guid"`
+}
+
+// handleGetOutputFeed serves the user's followed posts as an RSS 2.0 document.
+// The response carries an ETag and Content-Length, and conditional
+// (If-None-Match) and range requests are honoured.
+func (s *Server) handleGetOutputFeed(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserFromContext(r)
+	if err != nil {
+		s.respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	limit := int32(outputFeedDefaultLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= outputFeedMaxLimit {
+			limit = int32(l)
+		}
+	}
+
+	posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+		UserID: user.ID,
+		Limit:  limit,
+		Offset: 0,
+	})
+	if err != nil {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to get posts")
+		return
+	}
+
+	body, modified, err := renderOutputFeed(user.Name, posts)
+	if err != nil {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	http.ServeContent(w, r, "feed.xml", modified, bytes.NewReader(body))
+}
+
+// renderOutputFeed encodes posts as an RSS document and returns it along with
+// the time the newest post was stored
+func renderOutputFeed(userName string, posts []database.GetPostsForUserRow) ([]byte, time.Time, error) {
+	var modified time.Time
+	items := make([]outputItem, len(posts))
+	for i, post := range posts {
+		items[i] = outputItem{
+			Title:       post.Title,
+			Link:        post.Url,
+			Description: post.Description.String,
+			GUID:        post.Url,
+		}
+		if post.PublishedAt.Valid {
+			items[i].PubDate = post.PublishedAt.Time.UTC().Format(time.RFC1123Z)
+		}
+		if post.UpdatedAt.After(modified) {
+			modified = post.UpdatedAt
+		}
+	}
+
+	feed := outputRSS{
+		Version: "2.0",
+		Channel: outputChannel{
+			Title:       fmt.Sprintf("Gator: %s", userName),
+			Link:        "/api/feed.xml",
+			Description: fmt.Sprintf("Posts from feeds followed by %s", userName),
+			Items:       items,
+		},
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return append([]byte(xml.Header), data...), modified, nil
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+func TestHandleGetOutputFeed_LimitCapsItems(t *testing.T) {
+	queries := database.New(dbtest.Open(t))
+	s := NewServer(queries, "0")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "reader"}`))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register returned status %d", rec.Code)
+	}
+	var registered registerResponse
+	if err := json.NewDecoder(rec.Body).Decode(&registered); err != nil {
+		t.Fatalf("couldn't decode register response: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	feed, err := queries.CreateFeed(ctx, database.CreateFeedParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now,
+		Name: "Blog", Url: "https://blog.example.com/feed.xml", UserID: registered.User.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed: %v", err)
+	}
+	if _, err := queries.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID: uuid.New(), CreatedAt: now, UpdatedAt: now, UserID: registered.User.ID, FeedID: feed.ID,
+	}); err != nil {
+		t.Fatalf("couldn't follow feed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := queries.CreatePost(ctx, database.CreatePostParams{
+			ID: uuid.New(), CreatedAt: now, UpdatedAt: now,
+			Title:       fmt.Sprintf("post %d", i),
+			Url:         fmt.Sprintf("https://blog.example.com/%d", i),
+			PublishedAt: sql.NullTime{Time: now.Add(-time.Duration(i) * time.Hour), Valid: true},
+			FeedID:      feed.ID,
+		}); err != nil {
+			t.Fatalf("couldn't create post: %v", err)
+		}
+	}
+
+	get := func(url, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "ApiKey "+registered.APIKey)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec = get("/api/feed.xml?limit=2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("feed.xml returned status %d", rec.Code)
+	}
+	if n := strings.Count(rec.Body.String(), "<item>"); n != 2 {
+		t.Fatalf("expected 2 <item> elements, got %d", n)
+	}
+	if rec.Header().Get("Content-Length") != fmt.Sprint(rec.Body.Len()) {
+		t.Fatalf("Content-Length = %q; want %d", rec.Header().Get("Content-Length"), rec.Body.Len())
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected an ETag header")
+	}
+	if rec = get("/api/feed.xml?limit=2", etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching If-None-Match, got %d", rec.Code)
+	}
+
+	if rec = get("/api/feed.xml", ""); strings.Count(rec.Body.String(), "<item>") != 5 {
+		t.Fatalf("expected all 5 items without a limit")
+	}
+}
@@ -3,10 +3,10 @@ package api
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"gator/internal/database"
 	"gator/internal/rss"
+	"gator/internal/search"
 	"net/http"
 	"strconv"
 	"strings"
@@ -55,7 +55,10 @@ func (s *Server) handleGetUsers(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Users []userResponse `json:"users"`
+	}{Users: response})
 }
 
 func (s *Server) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
@@ -65,7 +68,10 @@ func (s *Server) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.respondWithJSON(w, http.StatusOK, user)
+	s.respondWithJSON(w, http.StatusOK, &struct {
+		Versioned
+		AuthenticatedUser
+	}{AuthenticatedUser: user})
 }
 
 // Feed handlers
@@ -97,7 +103,10 @@ func (s *Server) handleGetFeeds(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Feeds []feedResponse `json:"feeds"`
+	}{Feeds: response})
 }
 
 type createFeedRequest struct {
@@ -113,23 +122,42 @@ func (s *Server) handleCreateFeed(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req createFeedRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	if req.Name == "" || req.URL == "" {
-		s.respondWithError(w, http.StatusBadRequest, "Name and URL are required")
+	errs := fieldErrors{}
+	errs.required("url", req.URL)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
 
+	// A feed already stored under another spelling of the URL is followed
+	// rather than added again
+	feedURL := rss.NormalizeURL(req.URL)
+	if existing, err := rss.LookupFeed(r.Context(), s.db, req.URL); err == nil {
+		s.followExistingFeed(w, user.ID, existing)
+		return
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to look up feed")
+		return
+	}
+
+	// Without a name the feed is named after its URL until its title is known
+	named := req.Name != ""
+	name := req.Name
+	if !named {
+		name = feedURL
+	}
+
 	// Create feed
 	feed, err := s.db.CreateFeed(context.Background(), database.CreateFeedParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
-		Name:      req.Name,
-		Url:       req.URL,
+		Name:      name,
+		Url:       feedURL,
 		UserID:    user.ID,
 	})
 	if err != nil {
@@ -156,22 +184,28 @@ func (s *Server) handleCreateFeed(w http.ResponseWriter, r *http.Request) {
 		defer cancel()
 
 		client := rss.NewHTTPClient()
-		rssFeed, err := rss.FetchFeed(ctx, client, req.URL)
+		rssFeed, err := rss.FetchFeedWithOptions(ctx, client, feedURL, rss.FetchOptions{UserAgent: s.userAgent})
 		if err == nil {
+			rss.SaveFeedMetadata(ctx, s.db, feed, rssFeed, named)
 			rss.SavePostsToDatabase(ctx, s.db, rssFeed, feed.ID)
 		}
 	}()
 
-	type feedResponse struct {
-		ID        uuid.UUID `json:"id"`
-		Name      string    `json:"name"`
-		URL       string    `json:"url"`
-		UserID    uuid.UUID `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
-		UpdatedAt time.Time `json:"updated_at"`
-	}
+	s.respondWithJSON(w, http.StatusCreated, newCreateFeedResponse(feed))
+}
+
+type createFeedResponse struct {
+	Versioned
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
 
-	response := feedResponse{
+func newCreateFeedResponse(feed database.Feed) *createFeedResponse {
+	return &createFeedResponse{
 		ID:        feed.ID,
 		Name:      feed.Name,
 		URL:       feed.Url,
@@ -179,8 +213,71 @@ func (s *Server) handleCreateFeed(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: feed.CreatedAt,
 		UpdatedAt: feed.UpdatedAt,
 	}
+}
 
-	s.respondWithJSON(w, http.StatusCreated, response)
+// followExistingFeed answers a create request for a feed that is already
+// stored by following it, responding 200 with the stored feed
+func (s *Server) followExistingFeed(w http.ResponseWriter, userID uuid.UUID, feed database.Feed) {
+	_, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    userID,
+		FeedID:    feed.ID,
+	})
+	if err != nil && !isUniqueViolation(err) {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to follow feed")
+		return
+	}
+	s.respondWithJSON(w, http.StatusOK, newCreateFeedResponse(feed))
+}
+
+// handleDeleteFeed deletes the feed named by the {feedId} path parameter.
+// Only the feed's owner may delete it; its posts, follows, bookmarks, and
+// likes go with it.
+func (s *Server) handleDeleteFeed(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserFromContext(r)
+	if err != nil {
+		s.respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	feedID, err := uuid.Parse(r.PathValue("feedId"))
+	if err != nil {
+		s.respondWithError(w, http.StatusBadRequest, "Invalid feed ID format")
+		return
+	}
+
+	feed, err := s.db.GetFeedByID(context.Background(), feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.respondWithError(w, http.StatusNotFound, "Feed not found")
+			return
+		}
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to look up feed")
+		return
+	}
+	if feed.UserID != user.ID {
+		s.respondWithError(w, http.StatusForbidden, "Only the feed's owner can delete it")
+		return
+	}
+
+	rowsAffected, err := s.db.DeleteFeedByID(context.Background(), database.DeleteFeedByIDParams{
+		ID:     feed.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to delete feed")
+		return
+	}
+
+	// Deleted concurrently since the lookup
+	if rowsAffected == 0 {
+		s.respondWithError(w, http.StatusNotFound, "Feed not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // Feed follow handlers
@@ -212,7 +309,10 @@ func (s *Server) handleGetFeedFollows(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		FeedFollows []feedFollowResponse `json:"feed_follows"`
+	}{FeedFollows: response})
 }
 
 type createFeedFollowRequest struct {
@@ -227,18 +327,19 @@ func (s *Server) handleCreateFeedFollow(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req createFeedFollowRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	if req.FeedURL == "" {
-		s.respondWithError(w, http.StatusBadRequest, "Feed URL is required")
+	errs := fieldErrors{}
+	errs.required("feed_url", req.FeedURL)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
 
 	// Get feed by URL
-	feed, err := s.db.GetFeedByURL(context.Background(), req.FeedURL)
+	feed, err := rss.LookupFeed(context.Background(), s.db, req.FeedURL)
 	if err != nil {
 		s.respondWithError(w, http.StatusNotFound, "Feed not found")
 		return
@@ -258,6 +359,7 @@ func (s *Server) handleCreateFeedFollow(w http.ResponseWriter, r *http.Request)
 	}
 
 	type feedFollowResponse struct {
+		Versioned
 		ID        uuid.UUID `json:"id"`
 		FeedName  string    `json:"feed_name"`
 		CreatedAt time.Time `json:"created_at"`
@@ -269,7 +371,7 @@ func (s *Server) handleCreateFeedFollow(w http.ResponseWriter, r *http.Request)
 		CreatedAt: follow.CreatedAt,
 	}
 
-	s.respondWithJSON(w, http.StatusCreated, response)
+	s.respondWithJSON(w, http.StatusCreated, &response)
 }
 
 type deleteFeedFollowRequest struct {
@@ -284,13 +386,14 @@ func (s *Server) handleDeleteFeedFollow(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req deleteFeedFollowRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	if req.FeedURL == "" {
-		s.respondWithError(w, http.StatusBadRequest, "Feed URL is required")
+	errs := fieldErrors{}
+	errs.required("feed_url", req.FeedURL)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
 
@@ -311,6 +414,37 @@ func (s *Server) handleDeleteFeedFollow(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleDeleteFeedFollowByID unfollows the feed named by the {feedId} path parameter
+func (s *Server) handleDeleteFeedFollowByID(w http.ResponseWriter, r *http.Request) {
+	user, err := getUserFromContext(r)
+	if err != nil {
+		s.respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	feedID, err := uuid.Parse(r.PathValue("feedId"))
+	if err != nil {
+		s.respondWithError(w, http.StatusBadRequest, "Invalid feed ID format")
+		return
+	}
+
+	rowsAffected, err := s.db.DeleteFeedFollowByUserAndFeedID(context.Background(), database.DeleteFeedFollowByUserAndFeedIDParams{
+		UserID: user.ID,
+		FeedID: feedID,
+	})
+	if err != nil {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to unfollow feed")
+		return
+	}
+
+	if rowsAffected == 0 {
+		s.respondWithError(w, http.StatusNotFound, "Not following this feed")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Post handlers
 func (s *Server) handleGetPosts(w http.ResponseWriter, r *http.Request) {
 	user, err := getUserFromContext(r)
@@ -380,7 +514,10 @@ func (s *Server) handleGetPosts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Posts []postResponse `json:"posts"`
+	}{Posts: response})
 }
 
 func (s *Server) handleSearchPosts(w http.ResponseWriter, r *http.Request) {
@@ -414,12 +551,7 @@ func (s *Server) handleSearchPosts(w http.ResponseWriter, r *http.Request) {
 
 	offset := (page - 1) * limit
 
-	posts, err := s.db.SearchPostsForUser(context.Background(), database.SearchPostsForUserParams{
-		UserID:  user.ID,
-		Column2: sql.NullString{String: query, Valid: true},
-		Limit:   limit,
-		Offset:  offset,
-	})
+	posts, err := search.Posts(context.Background(), s.db, user.ID, query, limit, offset)
 	if err != nil {
 		s.respondWithError(w, http.StatusInternalServerError, "Failed to search posts")
 		return
@@ -458,7 +590,63 @@ func (s *Server) handleSearchPosts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Posts []postResponse `json:"posts"`
+	}{Posts: response})
+}
+
+func (s *Server) handleGetRecentPosts(w http.ResponseWriter, r *http.Request) {
+	limit := int32(10)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = int32(l)
+		}
+	}
+
+	posts, err := s.db.GetRecentPosts(context.Background(), limit)
+	if err != nil {
+		s.respondWithError(w, http.StatusInternalServerError, "Failed to get recent posts")
+		return
+	}
+
+	type postResponse struct {
+		ID          uuid.UUID  `json:"id"`
+		Title       string     `json:"title"`
+		URL         string     `json:"url"`
+		Description *string    `json:"description"`
+		PublishedAt *time.Time `json:"published_at"`
+		FeedName    string     `json:"feed_name"`
+		CreatedAt   time.Time  `json:"created_at"`
+	}
+
+	response := make([]postResponse, len(posts))
+	for i, post := range posts {
+		var description *string
+		if post.Description.Valid {
+			description = &post.Description.String
+		}
+
+		var publishedAt *time.Time
+		if post.PublishedAt.Valid {
+			publishedAt = &post.PublishedAt.Time
+		}
+
+		response[i] = postResponse{
+			ID:          post.ID,
+			Title:       post.Title,
+			URL:         post.Url,
+			Description: description,
+			PublishedAt: publishedAt,
+			FeedName:    post.FeedName,
+			CreatedAt:   post.CreatedAt,
+		}
+	}
+
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Posts []postResponse `json:"posts"`
+	}{Posts: response})
 }
 
 // Bookmark handlers
@@ -530,7 +718,10 @@ func (s *Server) handleGetBookmarks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Bookmarks []bookmarkResponse `json:"bookmarks"`
+	}{Bookmarks: response})
 }
 
 type createBookmarkRequest struct {
@@ -545,16 +736,17 @@ func (s *Server) handleCreateBookmark(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req createBookmarkRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	postID, err := uuid.Parse(req.PostID)
-	if err != nil {
-		s.respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+	errs := fieldErrors{}
+	errs.uuid("post_id", req.PostID)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
+	postID := uuid.MustParse(req.PostID)
 
 	// Check if post exists
 	_, err = s.db.GetPostByID(context.Background(), postID)
@@ -584,6 +776,7 @@ func (s *Server) handleCreateBookmark(w http.ResponseWriter, r *http.Request) {
 	// Successful creation - bookmark should have a valid ID
 
 	type bookmarkResponse struct {
+		Versioned
 		ID        uuid.UUID `json:"id"`
 		PostID    uuid.UUID `json:"post_id"`
 		UserID    uuid.UUID `json:"user_id"`
@@ -597,7 +790,7 @@ func (s *Server) handleCreateBookmark(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: bookmark.CreatedAt,
 	}
 
-	s.respondWithJSON(w, http.StatusCreated, response)
+	s.respondWithJSON(w, http.StatusCreated, &response)
 }
 
 func (s *Server) handleDeleteBookmark(w http.ResponseWriter, r *http.Request) {
@@ -700,7 +893,10 @@ func (s *Server) handleGetLikes(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.respondWithJSON(w, http.StatusOK, response)
+	s.respondWithList(w, r, http.StatusOK, response, &struct {
+		Versioned
+		Likes []likeResponse `json:"likes"`
+	}{Likes: response})
 }
 
 type createLikeRequest struct {
@@ -715,16 +911,17 @@ func (s *Server) handleCreateLike(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req createLikeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONBody(r, &req); err != nil {
 		s.respondWithError(w, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
-	postID, err := uuid.Parse(req.PostID)
-	if err != nil {
-		s.respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+	errs := fieldErrors{}
+	errs.uuid("post_id", req.PostID)
+	if s.respondWithValidationErrors(w, errs) {
 		return
 	}
+	postID := uuid.MustParse(req.PostID)
 
 	// Check if post exists
 	_, err = s.db.GetPostByID(context.Background(), postID)
@@ -753,6 +950,7 @@ func (s *Server) handleCreateLike(w http.ResponseWriter, r *http.Request) {
 
 	// Successful creation - like should have a valid ID
 	type likeResponse struct {
+		Versioned
 		ID        uuid.UUID `json:"id"`
 		PostID    uuid.UUID `json:"post_id"`
 		UserID    uuid.UUID `json:"user_id"`
@@ -766,7 +964,7 @@ func (s *Server) handleCreateLike(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: like.CreatedAt,
 	}
 
-	s.respondWithJSON(w, http.StatusCreated, response)
+	s.respondWithJSON(w, http.StatusCreated, &response)
 }
 
 func (s *Server) handleDeleteLike(w http.ResponseWriter, r *http.Request) {
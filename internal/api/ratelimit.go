@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiterIdle is how long a client's bucket is kept after its last request;
+// cleanup runs at most once per rateLimiterIdle
+const rateLimiterIdle = 10 * time.Minute
+
+// tokenBucket holds one client's available requests
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // when tokens was last refilled
+}
+
+// rateLimiter is a set of token buckets keyed by client. Each bucket refills
+// at rate tokens per second up to burst.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rps,
+		burst:   float64(max(burst, 1)),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from key's bucket. When none is left it reports how long
+// until the next one is available.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, l.wait(b)
+}
+
+// available is like allow, but leaves the token in key's bucket
+func (l *rateLimiter) available(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		return true, 0
+	}
+	return false, l.wait(b)
+}
+
+// refill returns key's bucket topped up for the time since it was last
+// used. Callers must hold l.mu.
+func (l *rateLimiter) refill(key string) *tokenBucket {
+	now := l.now()
+	if now.Sub(l.lastCleanup) >= rateLimiterIdle {
+		l.cleanup(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	return b
+}
+
+// wait is how long until b has a token again
+func (l *rateLimiter) wait(b *tokenBucket) time.Duration {
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// cleanup drops buckets that have been idle for rateLimiterIdle. Callers
+// must hold l.mu.
+func (l *rateLimiter) cleanup(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= rateLimiterIdle {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// SetRateLimit limits each client to rps requests per second with bursts of
+// up to burst requests. Clients are identified by user when the request
+// carries a valid API key, and by remote IP otherwise; an IP over its limit
+// is rejected even with a valid key, so that keys aren't looked up for it.
+// A non-positive rps disables the limit.
+func (s *Server) SetRateLimit(rps float64, burst int) {
+	if rps <= 0 {
+		s.limiter = nil
+		return
+	}
+	s.limiter = newRateLimiter(rps, burst)
+}
+
+// verifiedUser returns the user whose API key r carries, if the key is valid
+func (s *Server) verifiedUser(r *http.Request) (AuthenticatedUser, bool) {
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "ApiKey ")
+	if !ok || key == "" || s.db == nil {
+		return AuthenticatedUser{}, false
+	}
+	user, err := s.db.GetUserByAPIKeyHash(r.Context(), sql.NullString{String: HashAPIKey(key), Valid: true})
+	if err != nil {
+		return AuthenticatedUser{}, false
+	}
+	return AuthenticatedUser{ID: user.ID, Name: user.Name}, true
+}
+
+// remoteIPKey identifies an unverified client by its remote IP. Unverified
+// API keys aren't used, since a client could send a new one with each
+// request to get a fresh bucket.
+func remoteIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// rateLimit rejects requests over the client's limit with 429 and a
+// Retry-After header
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := remoteIPKey(r)
+		// An IP that has used up its bucket, e.g. by sending bogus API keys,
+		// is turned away before its key costs a database lookup
+		if ok, wait := s.limiter.available(key); !ok {
+			s.rejectOverLimit(w, wait)
+			return
+		}
+		if user, ok := s.verifiedUser(r); ok {
+			key = "user:" + user.ID.String()
+			// requireAuth reuses the user instead of looking the key up again
+			r = r.WithContext(context.WithValue(r.Context(), userContextKey, user))
+		}
+		if ok, wait := s.limiter.allow(key); !ok {
+			s.rejectOverLimit(w, wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectOverLimit answers 429, asking the client to retry after wait
+func (s *Server) rejectOverLimit(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	s.respondWithError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+}
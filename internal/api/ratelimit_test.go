@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+func TestRateLimit_RejectsPastBurst(t *testing.T) {
+	s := NewServer(nil, "0")
+	s.SetRateLimit(1, 3)
+
+	get := func(remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
+		req.RemoteAddr = remoteAddr
+		if apiKey != "" {
+			req.Header.Set("Authorization", "ApiKey "+apiKey)
+		}
+		rec := httptest.NewRecorder()
+		s.handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		if rec := get("192.0.2.1:1234", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 within the burst, got %d", i+1, rec.Code)
+		}
+	}
+	rec := get("192.0.2.1:5678", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 past the burst, got %d", rec.Code)
+	}
+	if retry, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || retry < 1 {
+		t.Fatalf("expected a Retry-After of at least 1 second, got %q", rec.Header().Get("Retry-After"))
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON error, got Content-Type %q", ct)
+	}
+
+	// Other IPs have their own buckets
+	if rec := get("192.0.2.2:1234", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected another IP to be allowed, got %d", rec.Code)
+	}
+	// An API key that doesn't belong to a user doesn't get a fresh bucket
+	if rec := get("192.0.2.1:1234", "made-up-key"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected an unverified API key to share its IP's limit, got %d", rec.Code)
+	}
+}
+
+// countingDB counts the queries run through it
+type countingDB struct {
+	*sql.DB
+	queries int
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries++
+	return c.DB.QueryRowContext(ctx, query, args...)
+}
+
+func TestRateLimit_VerifiedUsersHaveTheirOwnBucket(t *testing.T) {
+	db := &countingDB{DB: dbtest.Open(t)}
+	queries := database.New(db)
+	s := NewServer(queries, "0")
+	apiKey := "rate-limited-key"
+	_, err := queries.CreateUser(context.Background(), database.CreateUserParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+		Name:       "limited",
+		ApiKeyHash: sql.NullString{String: HashAPIKey(apiKey), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateUser returned error: %v", err)
+	}
+	s.SetRateLimit(1, 1)
+
+	me := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/me", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("Authorization", "ApiKey "+apiKey)
+		rec := httptest.NewRecorder()
+		s.handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := me(apiKey); code != http.StatusOK {
+		t.Fatalf("expected a verified user to be allowed, got %d", code)
+	}
+	if code := me(apiKey); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the user's own bucket to run out, got %d", code)
+	}
+	if code := me("made-up-key"); code != http.StatusUnauthorized {
+		t.Fatalf("expected the IP's bucket to be separate from the user's, got %d", code)
+	}
+	if code := me("another-made-up-key"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected invalid keys to share the IP's bucket, got %d", code)
+	}
+
+	// Once the IP is over its limit, keys it sends aren't looked up
+	before := db.queries
+	for _, key := range []string{"yet-another-made-up-key", apiKey} {
+		if code := me(key); code != http.StatusTooManyRequests {
+			t.Fatalf("expected requests from an IP over its limit to be rejected, got %d", code)
+		}
+	}
+	if db.queries != before {
+		t.Fatalf("expected no API key lookups for an IP over its limit, got %d", db.queries-before)
+	}
+}
+
+func TestRateLimiter_RefillsAndCleansUp(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2, 1)
+	l.now = func() time.Time { return now }
+
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatalf("expected the first request to be allowed")
+	}
+	ok, wait := l.allow("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected a 500ms wait at 2 rps, got ok=%v wait=%v", ok, wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.available("a"); !ok {
+		t.Fatalf("expected a token to be available after refilling")
+	}
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatalf("expected a token after refilling")
+	}
+
+	now = now.Add(rateLimiterIdle)
+	l.allow("b")
+	if _, ok := l.buckets["a"]; ok {
+		t.Fatalf("expected the idle bucket to be cleaned up")
+	}
+}
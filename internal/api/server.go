@@ -1,10 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"gator/internal/database"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -13,6 +18,14 @@ type Server struct {
 	db     *database.Queries
 	router *http.ServeMux
 	port   string
+	// allowedOrigins lists browser origins that get CORS headers
+	allowedOrigins []string
+	// limiter throttles each client; nil means no limit
+	limiter *rateLimiter
+	// logger records each request as JSON
+	logger *slog.Logger
+	// userAgent is sent when fetching feeds; empty means rss.DefaultUserAgent
+	userAgent string
 }
 
 // NewServer creates a new HTTP server instance
@@ -21,21 +34,64 @@ func NewServer(db *database.Queries, port string) *Server {
 		db:     db,
 		router: http.NewServeMux(),
 		port:   port,
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
 	}
 	s.setupRoutes()
 	return s
 }
 
+// SetUserAgent sets the User-Agent header sent when fetching feeds
+func (s *Server) SetUserAgent(userAgent string) {
+	s.userAgent = userAgent
+}
+
+// shutdownTimeout is how long Run waits for in-flight requests on shutdown
+const shutdownTimeout = 10 * time.Second
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
 	log.Printf("Starting HTTP server on port %s", s.port)
-	return http.ListenAndServe(":"+s.port, s.router)
+	return http.ListenAndServe(":"+s.port, s.handler())
+}
+
+// handler is the router wrapped in the server's middleware
+func (s *Server) handler() http.Handler {
+	return s.logRequests(s.cors(s.rateLimit(s.router)))
+}
+
+// Run serves until ctx is cancelled, then stops accepting connections and
+// waits for in-flight requests to finish. A clean shutdown returns nil.
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{Addr: ":" + s.port, Handler: s.handler()}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 // setupRoutes configures all the API endpoints
 func (s *Server) setupRoutes() {
 	// Health check and docs
-	s.router.HandleFunc("GET /health", s.handleHealth)
+	s.router.HandleFunc("GET /health", s.handleHealthReady)
+	s.router.HandleFunc("GET /health/live", s.handleHealthLive)
+	s.router.HandleFunc("GET /health/ready", s.handleHealthReady)
 	s.router.HandleFunc("GET /api/docs", s.handleDocs)
 
 	// Authentication
@@ -49,15 +105,19 @@ func (s *Server) setupRoutes() {
 	// Feed endpoints
 	s.router.HandleFunc("GET /api/feeds", s.handleGetFeeds)
 	s.router.HandleFunc("POST /api/feeds", s.requireAuth(s.handleCreateFeed))
+	s.router.HandleFunc("DELETE /api/feeds/{feedId}", s.requireAuth(s.handleDeleteFeed))
 
 	// Feed follow endpoints
 	s.router.HandleFunc("GET /api/feed-follows", s.requireAuth(s.handleGetFeedFollows))
 	s.router.HandleFunc("POST /api/feed-follows", s.requireAuth(s.handleCreateFeedFollow))
 	s.router.HandleFunc("DELETE /api/feed-follows", s.requireAuth(s.handleDeleteFeedFollow))
+	s.router.HandleFunc("DELETE /api/feed-follows/{feedId}", s.requireAuth(s.handleDeleteFeedFollowByID))
 
 	// Post endpoints
 	s.router.HandleFunc("GET /api/posts", s.requireAuth(s.handleGetPosts))
 	s.router.HandleFunc("GET /api/posts/search", s.requireAuth(s.handleSearchPosts))
+	s.router.HandleFunc("GET /api/posts/recent", s.requireAuth(s.handleGetRecentPosts))
+	s.router.HandleFunc("GET /api/feed.xml", s.requireAuth(s.handleGetOutputFeed))
 
 	// Bookmark endpoints
 	s.router.HandleFunc("GET /api/bookmarks", s.requireAuth(s.handleGetBookmarks))
@@ -70,28 +130,109 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("DELETE /api/likes/{postId}", s.requireAuth(s.handleDeleteLike))
 }
 
+// SchemaVersion is included as "schema_version" in every JSON object the API
+// responds with, and in gator's --json CLI output. Bump it only when an existing field is
+// removed, renamed, or changes type; adding new fields is not a breaking
+// change.
+const SchemaVersion = 1
+
+// SchemaVersionHeader is the request header with which a client asks for
+// list endpoints as versioned objects instead of bare arrays. Its value is
+// the schema version the client understands; the response's
+// "schema_version" tells it which one it got.
+const SchemaVersionHeader = "Gator-Schema-Version"
+
+// Versioned is embedded first in every response type so that its JSON
+// carries "schema_version"
+type Versioned struct {
+	SchemaVersion int `json:"schema_version"`
+}
+
+func (v *Versioned) setSchemaVersion() {
+	v.SchemaVersion = SchemaVersion
+}
+
+// versionedResponse is a pointer to a response type embedding Versioned
+type versionedResponse interface {
+	setSchemaVersion()
+}
+
 // Response helpers
-func (s *Server) respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+
+// respondWithJSON writes payload with its "schema_version" set
+func (s *Server) respondWithJSON(w http.ResponseWriter, code int, payload versionedResponse) {
+	payload.setSchemaVersion()
+	s.writeJSON(w, code, payload)
+}
+
+// respondWithList writes the items of a list endpoint. Clients that send
+// SchemaVersionHeader get list, a versioned object holding the items under a
+// key named after them; other clients get the bare items array, as before
+// responses were versioned.
+func (s *Server) respondWithList(w http.ResponseWriter, r *http.Request, code int, items interface{}, list versionedResponse) {
+	if r.Header.Get(SchemaVersionHeader) == "" {
+		s.writeJSON(w, code, items)
+		return
+	}
+	s.respondWithJSON(w, code, list)
+}
+
+// writeJSON writes payload as the JSON response body
+func (s *Server) writeJSON(w http.ResponseWriter, code int, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error encoding response: %v", err)
+		code = http.StatusInternalServerError
+		data = []byte(fmt.Sprintf(`{"schema_version":%d,"error":"Couldn't encode response"}`, SchemaVersion))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(payload)
+	w.Write(append(data, '\n'))
 }
 
 func (s *Server) respondWithError(w http.ResponseWriter, code int, message string) {
 	type errorResponse struct {
+		Versioned
 		Error string `json:"error"`
 	}
-	s.respondWithJSON(w, code, errorResponse{Error: message})
+	s.respondWithJSON(w, code, &errorResponse{Error: message})
 }
 
-// Health check endpoint
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	type healthResponse struct {
-		Status string `json:"status"`
-		Time   string `json:"time"`
-	}
-	s.respondWithJSON(w, http.StatusOK, healthResponse{
+// readyTimeout bounds the database ping behind the readiness probe
+const readyTimeout = 2 * time.Second
+
+type healthResponse struct {
+	Versioned
+	Status string `json:"status"`
+	Time   string `json:"time"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleHealthLive is the liveness probe: it succeeds whenever the process
+// can serve requests, regardless of the database
+func (s *Server) handleHealthLive(w http.ResponseWriter, r *http.Request) {
+	s.respondWithJSON(w, http.StatusOK, &healthResponse{
 		Status: "ok",
 		Time:   time.Now().UTC().Format(time.RFC3339),
 	})
 }
+
+// handleHealthReady is the readiness probe: it succeeds only when the
+// database answers a ping. /health is an alias for it.
+func (s *Server) handleHealthReady(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if s.db == nil {
+		s.respondWithJSON(w, http.StatusServiceUnavailable, &healthResponse{Status: "unavailable", Time: now, Error: "no database configured"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+	if err := s.db.Ping(ctx); err != nil {
+		log.Printf("Readiness check failed: %v", err)
+		s.respondWithJSON(w, http.StatusServiceUnavailable, &healthResponse{Status: "unavailable", Time: now, Error: "database unreachable"})
+		return
+	}
+	s.respondWithJSON(w, http.StatusOK, &healthResponse{Status: "ok", Time: now})
+}
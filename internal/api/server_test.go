@@ -0,0 +1,240 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+)
+
+// stubDB is a database.DBTX whose Exec calls return err; nil means the
+// database is reachable
+type stubDB struct {
+	err error
+}
+
+func (d stubDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, d.err
+}
+
+func (d stubDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, d.err
+}
+
+func (d stubDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, d.err
+}
+
+func (d stubDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	panic("stubDB does not support QueryRowContext")
+}
+
+func TestHealthProbes(t *testing.T) {
+	dead := NewServer(database.New(stubDB{err: errors.New("connection refused")}), "0")
+	alive := NewServer(database.New(stubDB{}), "0")
+
+	cases := []struct {
+		name   string
+		server *Server
+		path   string
+		want   int
+	}{
+		{"live with dead db", dead, "/health/live", http.StatusOK},
+		{"ready with dead db", dead, "/health/ready", http.StatusServiceUnavailable},
+		{"health alias with dead db", dead, "/health", http.StatusServiceUnavailable},
+		{"live with no db", NewServer(nil, "0"), "/health/live", http.StatusOK},
+		{"ready with no db", NewServer(nil, "0"), "/health/ready", http.StatusServiceUnavailable},
+		{"ready with live db", alive, "/health/ready", http.StatusOK},
+		{"health alias with live db", alive, "/health", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+		tc.server.router.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tc.name, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestServerRun_StopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(nil, "0").Run(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run didn't return after the context was cancelled")
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	s := NewServer(nil, "0")
+	s.SetAllowedOrigins([]string{"https://app.example.com/", " https://other.example.com"})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/feeds", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": corsAllowMethods,
+		"Access-Control-Allow-Headers": corsAllowHeaders,
+		"Access-Control-Max-Age":       corsMaxAge,
+		"Vary":                         "Origin",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q; want %q", header, got, value)
+		}
+	}
+	if !strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("expected Authorization to be an allowed header")
+	}
+}
+
+func TestCORS_Origins(t *testing.T) {
+	cases := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    string
+	}{
+		{"allowlisted origin", []string{"https://app.example.com"}, "https://app.example.com", "https://app.example.com"},
+		{"unlisted origin", []string{"https://app.example.com"}, "https://evil.example.com", ""},
+		{"wildcard", []string{"*"}, "https://any.example.com", "*"},
+		{"not configured", nil, "https://app.example.com", ""},
+		{"same-origin request", []string{"*"}, "", ""},
+	}
+	for _, tc := range cases {
+		s := NewServer(nil, "0")
+		s.SetAllowedOrigins(tc.allowed)
+
+		req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		rec := httptest.NewRecorder()
+		s.handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected the request to reach the router, got %d", tc.name, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.want {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLogRequests_RecordsHealthRequest(t *testing.T) {
+	var logs bytes.Buffer
+	s := NewServer(nil, "0")
+	s.SetLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, req)
+
+	var entry struct {
+		Msg     string `json:"msg"`
+		Method  string `json:"method"`
+		Path    string `json:"path"`
+		Status  int    `json:"status"`
+		Bytes   int    `json:"bytes"`
+		Latency int64  `json:"latency"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", logs.String(), err)
+	}
+	// No database, so the readiness check behind /health fails
+	if entry.Msg != "request" || entry.Method != http.MethodGet || entry.Path != "/health" || entry.Status != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected log entry: %+v", entry)
+	}
+	if entry.Bytes != rec.Body.Len() || entry.Bytes == 0 {
+		t.Fatalf("logged %d bytes; response had %d", entry.Bytes, rec.Body.Len())
+	}
+	if entry.Latency < 0 {
+		t.Fatalf("expected a latency, got %d", entry.Latency)
+	}
+}
+
+func TestRespondWithJSON_IncludesSchemaVersion(t *testing.T) {
+	s := NewServer(nil, "0")
+	respond := func(write func(w http.ResponseWriter)) map[string]json.RawMessage {
+		rec := httptest.NewRecorder()
+		write(rec)
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("couldn't decode %q: %v", rec.Body.String(), err)
+		}
+		if got := string(body["schema_version"]); got != fmt.Sprint(SchemaVersion) {
+			t.Fatalf("schema_version = %s in %q; want %d", got, rec.Body.String(), SchemaVersion)
+		}
+		return body
+	}
+
+	body := respond(func(w http.ResponseWriter) { s.respondWithError(w, http.StatusNotFound, "Not found") })
+	if string(body["error"]) != `"Not found"` {
+		t.Errorf("expected the error to be kept, got %s", body["error"])
+	}
+	respond(func(w http.ResponseWriter) { s.respondWithJSON(w, http.StatusOK, &Versioned{}) })
+
+	// A payload's own schema_version can't be sent next to the current one
+	rec := httptest.NewRecorder()
+	s.respondWithJSON(rec, http.StatusOK, &struct {
+		Versioned
+		Name string `json:"name"`
+	}{Versioned: Versioned{SchemaVersion: 99}, Name: "feed"})
+	if got := rec.Body.String(); strings.Count(got, "schema_version") != 1 || !strings.Contains(got, fmt.Sprintf(`"schema_version":%d`, SchemaVersion)) {
+		t.Errorf("expected a single current schema_version, got %s", got)
+	}
+}
+
+func TestRespondWithList_VersionedOnlyWhenAsked(t *testing.T) {
+	s := NewServer(nil, "0")
+	items := []string{"a", "b"}
+	list := &struct {
+		Versioned
+		Feeds []string `json:"feeds"`
+	}{Feeds: items}
+
+	// Clients that predate versioning keep getting a bare array
+	rec := httptest.NewRecorder()
+	s.respondWithList(rec, httptest.NewRequest(http.MethodGet, "/api/feeds", nil), http.StatusOK, items, list)
+	if got := strings.TrimSpace(rec.Body.String()); got != `["a","b"]` {
+		t.Errorf("expected a bare array, got %s", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/feeds", nil)
+	req.Header.Set(SchemaVersionHeader, "1")
+	rec = httptest.NewRecorder()
+	s.respondWithList(rec, req, http.StatusOK, items, list)
+	want := fmt.Sprintf(`{"schema_version":%d,"feeds":["a","b"]}`, SchemaVersion)
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+}
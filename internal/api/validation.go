@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// fieldErrors maps request field names to what is wrong with them
+type fieldErrors map[string]string
+
+// required records field as "required" if value is blank
+func (e fieldErrors) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e[field] = "required"
+	}
+}
+
+// uuid records field as "required" if value is blank or "invalid" if it isn't a UUID
+func (e fieldErrors) uuid(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e[field] = "required"
+		return
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		e[field] = "invalid"
+	}
+}
+
+// username records field as "required" if value is blank, or with the reason
+// it isn't an acceptable username
+func (e fieldErrors) username(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e[field] = "required"
+		return
+	}
+	if err := ValidateUsername(value); err != nil {
+		e[field] = err.Error()
+	}
+}
+
+const (
+	minUsernameLength = 2
+	maxUsernameLength = 32
+)
+
+// reservedUsernames can't be registered because they read as CLI commands or
+// keywords (e.g. `gator login reset` looks like a typo of `gator reset`)
+var reservedUsernames = map[string]bool{
+	"login": true, "register": true, "reset": true, "users": true, "reset-apikey": true,
+	"config": true, "profile": true, "agg": true, "doctor": true, "stats": true, "export": true,
+	"serve": true, "tui": true, "addfeed": true, "deletefeed": true, "renamefeed": true, "feeds": true, "follow": true,
+	"following": true, "unfollow": true, "browse": true, "search": true, "posts": true,
+	"bookmark": true, "unbookmark": true, "bookmarks": true, "like": true, "unlike": true,
+	"likes": true, "mark-read": true, "mark-unread": true, "all": true, "help": true,
+	"export-posts": true, "import-url": true, "logout": true,
+}
+
+// ValidateUsername reports why name can't be used as a username. Usernames
+// are 2-32 characters of letters, digits, '-', '_' or '.', start with a letter
+// or digit, and aren't a reserved command name.
+func ValidateUsername(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("username can't be empty")
+	}
+	if len(name) < minUsernameLength || len(name) > maxUsernameLength {
+		return fmt.Errorf("username must be %d-%d characters long", minUsernameLength, maxUsernameLength)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case (r == '-' || r == '_' || r == '.') && i > 0:
+		default:
+			return errors.New("username may only contain letters, digits, '-', '_' and '.', and must start with a letter or digit")
+		}
+	}
+	if reservedUsernames[strings.ToLower(name)] {
+		return fmt.Errorf("username %q is reserved", name)
+	}
+	return nil
+}
+
+// decodeJSONBody decodes the request body into v. An empty body is treated
+// as an empty object so that missing fields are reported by validation.
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// respondWithValidationErrors writes a 422 response listing every invalid field,
+// e.g. {"error": "Validation failed", "errors": {"name": "required"}}.
+// It reports whether there were any errors to write.
+func (s *Server) respondWithValidationErrors(w http.ResponseWriter, errs fieldErrors) bool {
+	if len(errs) == 0 {
+		return false
+	}
+	type validationResponse struct {
+		Versioned
+		Error  string            `json:"error"`
+		Errors map[string]string `json:"errors"`
+	}
+	s.respondWithJSON(w, http.StatusUnprocessableEntity, &validationResponse{
+		Error:  "Validation failed",
+		Errors: errs,
+	})
+	return true
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// decodeValidationErrors decodes a 422 response body into its field error map
+func decodeValidationErrors(t *testing.T, rec *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Error  string            `json:"error"`
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("couldn't decode response: %v", err)
+	}
+	if body.Error == "" {
+		t.Fatalf("expected a top-level error message")
+	}
+	return body.Errors
+}
+
+func TestHandleCreateFeed_EmptyBodyReturnsFieldErrors(t *testing.T) {
+	s := NewServer(nil, "0")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(""))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey, AuthenticatedUser{ID: uuid.New(), Name: "alice"}))
+	rec := httptest.NewRecorder()
+	s.handleCreateFeed(rec, req)
+
+	errs := decodeValidationErrors(t, rec)
+	// The name is optional; the feed's own title is used without one
+	if len(errs) != 1 || errs["url"] != "required" {
+		t.Fatalf("unexpected field errors: %v", errs)
+	}
+}
+
+func TestHandleRegister_BlankNameReturnsFieldErrors(t *testing.T) {
+	s := NewServer(nil, "0")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "  "}`))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	errs := decodeValidationErrors(t, rec)
+	if len(errs) != 1 || errs["name"] != "required" {
+		t.Fatalf("unexpected field errors: %v", errs)
+	}
+}
+
+func TestFieldErrors_UUID(t *testing.T) {
+	errs := fieldErrors{}
+	errs.uuid("a", "")
+	errs.uuid("b", "not-a-uuid")
+	errs.uuid("c", uuid.New().String())
+	if errs["a"] != "required" || errs["b"] != "invalid" {
+		t.Fatalf("unexpected field errors: %v", errs)
+	}
+	if _, ok := errs["c"]; ok {
+		t.Fatalf("expected valid UUID to pass")
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	valid := []string{"alice", "bob_smith", "jane.doe", "r2-d2", "Al", strings.Repeat("a", maxUsernameLength)}
+	for _, name := range valid {
+		if err := ValidateUsername(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"empty":             "",
+		"blank":             "   ",
+		"too short":         "a",
+		"too long":          strings.Repeat("a", maxUsernameLength+1),
+		"inner whitespace":  "alice smith",
+		"padded":            " alice ",
+		"bad character":     "alice@home",
+		"leading dash":      "-alice",
+		"reserved":          "reset",
+		"reserved any case": "Browse",
+	}
+	for category, name := range invalid {
+		if err := ValidateUsername(name); err == nil {
+			t.Errorf("%s: expected %q to be rejected", category, name)
+		}
+	}
+}
+
+func TestHandleRegister_ReservedNameReturnsFieldErrors(t *testing.T) {
+	s := NewServer(nil, "0")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(`{"name": "reset"}`))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	errs := decodeValidationErrors(t, rec)
+	if len(errs) != 1 || !strings.Contains(errs["name"], "reserved") {
+		t.Fatalf("unexpected field errors: %v", errs)
+	}
+}
@@ -2,48 +2,349 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 const configFileName = ".gatorconfig.json"
 
-// Config represents the JSON file structure
-type Config struct {
+// defaultProfileName names the profile a flat, single-database config is
+// migrated into
+const defaultProfileName = "default"
+
+// Profile is a named database and the user logged in to it
+type Profile struct {
 	DbURL           string `json:"db_url"`
 	CurrentUserName string `json:"current_user_name,omitempty"`
 }
 
-// Read reads the JSON file found at ~/.gatorconfig.json and returns a Config struct
-func Read() (Config, error) {
-	var cfg Config
+// Config represents the JSON file structure.
+// A Config is safe for concurrent use through its methods; it must not be
+// copied after first use.
+type Config struct {
+	// DbURL and CurrentUserName are the active profile's settings
+	DbURL           string `json:"-"`
+	CurrentUserName string `json:"-"`
+
+	ActiveProfile string             `json:"active_profile"`
+	Profiles      map[string]Profile `json:"profiles"`
+
+	// OnNewPostCmd is a shell command run by `agg all` for every new post
+	OnNewPostCmd string `json:"on_new_post_cmd,omitempty"`
+	// AggWorkers is the default worker count for `agg all`
+	AggWorkers int `json:"agg_workers,omitempty"`
+	// PageSize is the default number of posts per page for browse, search,
+	// and bookmarks
+	PageSize int `json:"page_size,omitempty"`
+	// UserAgent is the User-Agent header sent when fetching feeds
+	UserAgent string `json:"user_agent,omitempty"`
+	// MaxOpenConns, MaxIdleConns, and ConnMaxLifetime tune the database
+	// connection pool; see DBPool for the defaults
+	MaxOpenConns    int    `json:"max_open_conns,omitempty"`
+	MaxIdleConns    int    `json:"max_idle_conns,omitempty"`
+	ConnMaxLifetime string `json:"conn_max_lifetime,omitempty"`
+
+	mu sync.RWMutex
+}
 
+// Read reads the config file (see getConfigFilePath) and returns a Config
+// struct set to the active profile. A config in the older flat format is
+// rewritten with its settings as the "default" profile. The settings aren't
+// validated, so a broken config can still be read and repaired; call Validate
+// before using them.
+func Read() (*Config, error) {
 	configPath, err := getConfigFilePath()
 	if err != nil {
-		return cfg, err
+		return nil, err
 	}
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return cfg, err
+		return nil, err
 	}
 
-	err = json.Unmarshal(data, &cfg)
+	cfg, migrated, err := parse(data)
 	if err != nil {
-		return cfg, err
+		return nil, err
+	}
+	if migrated {
+		// A config that can't be rewritten (e.g. a read-only mount) still
+		// works; the migration is retried on the next read
+		_ = write(cfg)
 	}
 
 	return cfg, nil
 }
 
+// parse decodes a config file and selects its active profile. migrated is set
+// when the file used the flat format, which has no profiles.
+func parse(data []byte) (cfg *Config, migrated bool, err error) {
+	cfg = &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, false, err
+	}
+
+	if len(cfg.Profiles) == 0 {
+		var flat Profile
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return nil, false, err
+		}
+		cfg.Profiles = map[string]Profile{defaultProfileName: flat}
+		cfg.ActiveProfile = defaultProfileName
+		migrated = true
+	}
+
+	profile, ok := cfg.Profiles[cfg.ActiveProfile]
+	if !ok {
+		return nil, false, fmt.Errorf("active_profile %q is not one of the profiles: %s", cfg.ActiveProfile, strings.Join(cfg.profileNames(), ", "))
+	}
+	cfg.DbURL = profile.DbURL
+	cfg.CurrentUserName = profile.CurrentUserName
+	return cfg, migrated, nil
+}
+
+// Validate checks that the config has the settings required to run
+func (cfg *Config) Validate() error {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+
+	if strings.TrimSpace(cfg.DbURL) == "" {
+		return fmt.Errorf("db_url is required")
+	}
+	// The URL isn't echoed back since it usually holds a password
+	u, err := url.Parse(cfg.DbURL)
+	if err != nil {
+		return fmt.Errorf("db_url isn't a valid URL: %w", errors.Unwrap(err))
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("db_url must start with postgres:// or postgresql://")
+	}
+
+	if cfg.MaxOpenConns < 0 {
+		return fmt.Errorf("max_open_conns can't be negative")
+	}
+	if cfg.MaxIdleConns < 0 {
+		return fmt.Errorf("max_idle_conns can't be negative")
+	}
+	if cfg.ConnMaxLifetime != "" {
+		lifetime, err := time.ParseDuration(cfg.ConnMaxLifetime)
+		if err != nil || lifetime <= 0 {
+			return fmt.Errorf("conn_max_lifetime must be a positive duration such as \"30m\", got %q", cfg.ConnMaxLifetime)
+		}
+	}
+	return nil
+}
+
+// Path returns the full path to the config file
+func Path() (string, error) {
+	return getConfigFilePath()
+}
+
+// Edit runs editor on the config file, then re-reads and validates it.
+// If the edited file is not valid, the previous contents are restored and
+// the validation error is returned.
+func Edit(editor func(path string) error) error {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	previous, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := editor(configPath); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	if err := validateFile(configPath); err != nil {
+		if restoreErr := os.WriteFile(configPath, previous, 0644); restoreErr != nil {
+			return fmt.Errorf("invalid config (%v) and couldn't restore previous version: %w", err, restoreErr)
+		}
+		return fmt.Errorf("invalid config, previous version restored: %w", err)
+	}
+
+	return nil
+}
+
+// validateFile parses and validates the config file at path
+func validateFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var syntax *json.SyntaxError
+	cfg, _, err := parse(data)
+	if errors.As(err, &syntax) {
+		return fmt.Errorf("malformed JSON: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// CurrentUser returns the current_user_name field
+func (cfg *Config) CurrentUser() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.CurrentUserName
+}
+
+// DefaultAggWorkers returns the agg_workers field
+func (cfg *Config) DefaultAggWorkers() int {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.AggWorkers
+}
+
+// DefaultPageSize returns the page_size field
+func (cfg *Config) DefaultPageSize() int {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.PageSize
+}
+
+// Database connection pool defaults, used for settings left out of the config
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 30 * time.Minute
+)
+
+// DBPool holds the database connection pool settings
+type DBPool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DBPool returns the pool settings from max_open_conns, max_idle_conns, and
+// conn_max_lifetime, with defaults for any left out. The idle connections are
+// capped at the open ones.
+func (cfg *Config) DBPool() DBPool {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+
+	pool := DBPool{
+		MaxOpenConns:    DefaultMaxOpenConns,
+		MaxIdleConns:    DefaultMaxIdleConns,
+		ConnMaxLifetime: DefaultConnMaxLifetime,
+	}
+	if cfg.MaxOpenConns > 0 {
+		pool.MaxOpenConns = cfg.MaxOpenConns
+	}
+	if cfg.MaxIdleConns > 0 {
+		pool.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if lifetime, err := time.ParseDuration(cfg.ConnMaxLifetime); err == nil && lifetime > 0 {
+		pool.ConnMaxLifetime = lifetime
+	}
+	pool.MaxIdleConns = min(pool.MaxIdleConns, pool.MaxOpenConns)
+	return pool
+}
+
+// FetchUserAgent returns the user_agent field
+func (cfg *Config) FetchUserAgent() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.UserAgent
+}
+
+// NewPostCommand returns the on_new_post_cmd field
+func (cfg *Config) NewPostCommand() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.OnNewPostCmd
+}
+
 // SetUser sets the current_user_name field and writes the config struct to the JSON file
 func (cfg *Config) SetUser(username string) error {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
 	cfg.CurrentUserName = username
-	return write(*cfg)
+	return write(cfg)
+}
+
+// ActiveProfileName returns the active_profile field
+func (cfg *Config) ActiveProfileName() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.ActiveProfile
+}
+
+// ProfileNames returns the names of all profiles, sorted
+func (cfg *Config) ProfileNames() []string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.profileNames()
 }
 
-// getConfigFilePath returns the full path to the config file
+func (cfg *Config) profileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetActiveProfile switches to the named profile and writes the config struct
+// to the JSON file
+func (cfg *Config) SetActiveProfile(name string) error {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q; available profiles: %s", name, strings.Join(cfg.profileNames(), ", "))
+	}
+	cfg.saveActiveProfile()
+	cfg.ActiveProfile = name
+	cfg.DbURL = profile.DbURL
+	cfg.CurrentUserName = profile.CurrentUserName
+	return write(cfg)
+}
+
+// saveActiveProfile copies DbURL and CurrentUserName back into the active
+// profile. The caller must hold cfg.mu.
+func (cfg *Config) saveActiveProfile() {
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]Profile)
+	}
+	if cfg.ActiveProfile == "" {
+		cfg.ActiveProfile = defaultProfileName
+	}
+	cfg.Profiles[cfg.ActiveProfile] = Profile{
+		DbURL:           cfg.DbURL,
+		CurrentUserName: cfg.CurrentUserName,
+	}
+}
+
+// getConfigFilePath returns the full path to the config file: $GATOR_CONFIG
+// when set, then $XDG_CONFIG_HOME/gator/config.json if that file exists,
+// then ~/.gatorconfig.json
 func getConfigFilePath() (string, error) {
+	if path := os.Getenv("GATOR_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	if xdgHome := os.Getenv("XDG_CONFIG_HOME"); xdgHome != "" {
+		path := filepath.Join(xdgHome, "gator", "config.json")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -52,13 +353,15 @@ func getConfigFilePath() (string, error) {
 	return filepath.Join(homeDir, configFileName), nil
 }
 
-// write writes the config struct to the JSON file
-func write(cfg Config) error {
+// write writes the config struct to the JSON file. The caller must hold cfg.mu.
+func write(cfg *Config) error {
 	configPath, err := getConfigFilePath()
 	if err != nil {
 		return err
 	}
 
+	cfg.saveActiveProfile()
+
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
@@ -64,6 +64,33 @@ func (q *Queries) DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams)
 	return result.RowsAffected()
 }
 
+const getBookmarkedPostIDs = `-- name: GetBookmarkedPostIDs :many
+SELECT post_id FROM bookmarks WHERE user_id = $1
+`
+
+func (q *Queries) GetBookmarkedPostIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getBookmarkedPostIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var post_id uuid.UUID
+		if err := rows.Scan(&post_id); err != nil {
+			return nil, err
+		}
+		items = append(items, post_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBookmarksForUser = `-- name: GetBookmarksForUser :many
 SELECT 
     b.id as bookmark_id,
@@ -76,17 +103,22 @@ SELECT
     p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM bookmarks b
 JOIN posts p ON b.post_id = p.id
 JOIN feeds f ON p.feed_id = f.id
 WHERE b.user_id = $1
+  AND ($2::uuid IS NULL OR p.feed_id = $2)
 ORDER BY b.created_at DESC
-LIMIT $2 OFFSET $3
+LIMIT $3 OFFSET $4
 `
 
 type GetBookmarksForUserParams struct {
 	UserID uuid.UUID
+	FeedID uuid.NullUUID
 	Limit  int32
 	Offset int32
 }
@@ -102,11 +134,20 @@ type GetBookmarksForUserRow struct {
 	Description  sql.NullString
 	PublishedAt  sql.NullTime
 	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
 	FeedName     string
 }
 
+// Optionally scoped to a single feed when feed_id is non-null.
 func (q *Queries) GetBookmarksForUser(ctx context.Context, arg GetBookmarksForUserParams) ([]GetBookmarksForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBookmarksForUser, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getBookmarksForUser,
+		arg.UserID,
+		arg.FeedID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +166,9 @@ func (q *Queries) GetBookmarksForUser(ctx context.Context, arg GetBookmarksForUs
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
 			&i.FeedName,
 		); err != nil {
 			return nil, err
@@ -141,7 +185,7 @@ func (q *Queries) GetBookmarksForUser(ctx context.Context, arg GetBookmarksForUs
 }
 
 const getPostByID = `-- name: GetPostByID :one
-SELECT id, created_at, updated_at, title, url, description, published_at, feed_id FROM posts WHERE id = $1
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, search_vector, author, enclosure_url, enclosure_type, enclosure_length, date_source FROM posts WHERE id = $1
 `
 
 func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
@@ -156,6 +200,38 @@ func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.SearchVector,
+		&i.Author,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.EnclosureLength,
+		&i.DateSource,
+	)
+	return i, err
+}
+
+const getPostByURL = `-- name: GetPostByURL :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, search_vector, author, enclosure_url, enclosure_type, enclosure_length, date_source FROM posts WHERE url = $1
+`
+
+func (q *Queries) GetPostByURL(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByURL, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.SearchVector,
+		&i.Author,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.EnclosureLength,
+		&i.DateSource,
 	)
 	return i, err
 }
@@ -13,6 +13,111 @@ import (
 	"github.com/google/uuid"
 )
 
+const countPostsForUser = `-- name: CountPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = $1
+`
+
+func (q *Queries) CountPostsForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsForUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPostsForUserByFeed = `-- name: CountPostsForUserByFeed :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+  AND (f.url = $2 OR f.name = $2)
+`
+
+type CountPostsForUserByFeedParams struct {
+	UserID uuid.UUID
+	Feed   string
+}
+
+// Count of posts matched by GetPostsForUserByFeed, for pagination.
+func (q *Queries) CountPostsForUserByFeed(ctx context.Context, arg CountPostsForUserByFeedParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsForUserByFeed, arg.UserID, arg.Feed)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPostsFTS = `-- name: CountSearchPostsFTS :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = $1
+    AND p.search_vector @@ plainto_tsquery('english', $2)
+`
+
+type CountSearchPostsFTSParams struct {
+	UserID uuid.UUID
+	Query  string
+}
+
+// Count of posts matched by SearchPostsFTS, for pagination.
+func (q *Queries) CountSearchPostsFTS(ctx context.Context, arg CountSearchPostsFTSParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchPostsFTS, arg.UserID, arg.Query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPostsForUser = `-- name: CountSearchPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = $1
+    AND (
+        p.title ILIKE ('%' || $2 || '%')
+        OR p.description ILIKE ('%' || $2 || '%')
+    )
+`
+
+type CountSearchPostsForUserParams struct {
+	UserID  uuid.UUID
+	Column2 sql.NullString
+}
+
+// Count of posts matched by SearchPostsForUser, for pagination.
+func (q *Queries) CountSearchPostsForUser(ctx context.Context, arg CountSearchPostsForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchPostsForUser, arg.UserID, arg.Column2)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeeds = `-- name: CountFeeds :one
+SELECT COUNT(*) FROM feeds
+`
+
+func (q *Queries) CountFeeds(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeeds)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeedsDue = `-- name: CountFeedsDue :one
+SELECT COUNT(*) FROM feeds
+WHERE next_retry_at IS NULL OR next_retry_at <= NOW()
+`
+
+// Feeds not currently backing off after repeated failures.
+func (q *Queries) CountFeedsDue(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedsDue)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
 VALUES (
@@ -23,7 +128,7 @@ VALUES (
     $5,
     $6
 )
-RETURNING id, created_at, updated_at, name, url, user_id
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description
 `
 
 type CreateFeedParams struct {
@@ -52,6 +157,69 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+	)
+	return i, err
+}
+
+const createOrGetFeed = `-- name: CreateOrGetFeed :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (url) DO UPDATE SET url = EXCLUDED.url
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description, (xmax = 0) AS inserted
+`
+
+type CreateOrGetFeedParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Url       string
+	UserID    uuid.UUID
+}
+
+type CreateOrGetFeedRow struct {
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Name                string
+	Url                 string
+	UserID              uuid.UUID
+	LastFetchedAt       sql.NullTime
+	ConsecutiveFailures int32
+	NextRetryAt         sql.NullTime
+	Description         sql.NullString
+	Inserted            bool
+}
+
+// Inserts a feed, or returns the feed already stored under the URL. The no-op
+// update makes RETURNING yield the existing row on conflict; inserted is true
+// only when this call created the feed.
+func (q *Queries) CreateOrGetFeed(ctx context.Context, arg CreateOrGetFeedParams) (CreateOrGetFeedRow, error) {
+	row := q.db.QueryRowContext(ctx, createOrGetFeed,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Url,
+		arg.UserID,
+	)
+	var i CreateOrGetFeedRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+		&i.Inserted,
 	)
 	return i, err
 }
@@ -115,21 +283,26 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 }
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author, enclosure_url, enclosure_type, enclosure_length, date_source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 ON CONFLICT (url) DO NOTHING
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, search_vector, author, enclosure_url, enclosure_type, enclosure_length, date_source
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Title           string
+	Url             string
+	Description     sql.NullString
+	PublishedAt     sql.NullTime
+	FeedID          uuid.UUID
+	Author          sql.NullString
+	EnclosureUrl    sql.NullString
+	EnclosureType   sql.NullString
+	EnclosureLength sql.NullInt64
+	DateSource      sql.NullString
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -142,6 +315,11 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Author,
+		arg.EnclosureUrl,
+		arg.EnclosureType,
+		arg.EnclosureLength,
+		arg.DateSource,
 	)
 	var i Post
 	err := row.Scan(
@@ -153,10 +331,53 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.SearchVector,
+		&i.Author,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.EnclosureLength,
+		&i.DateSource,
 	)
 	return i, err
 }
 
+const deleteFeedByID = `-- name: DeleteFeedByID :execrows
+DELETE FROM feeds WHERE id = $1 AND user_id = $2
+`
+
+type DeleteFeedByIDParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// Deletes a feed owned by $2, cascading like DeleteFeedByURL.
+func (q *Queries) DeleteFeedByID(ctx context.Context, arg DeleteFeedByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeedByID, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFeedByURL = `-- name: DeleteFeedByURL :execrows
+DELETE FROM feeds WHERE url = $1 AND user_id = $2
+`
+
+type DeleteFeedByURLParams struct {
+	Url    string
+	UserID uuid.UUID
+}
+
+// Deletes a feed owned by $2. Posts, follows, and everything hanging off
+// posts (bookmarks, likes, tags) go with it via ON DELETE CASCADE.
+func (q *Queries) DeleteFeedByURL(ctx context.Context, arg DeleteFeedByURLParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeedByURL, arg.Url, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteFeedFollowByUserAndFeedURL = `-- name: DeleteFeedFollowByUserAndFeedURL :execrows
 DELETE FROM feed_follows 
 WHERE feed_follows.user_id = $1 
@@ -176,8 +397,176 @@ func (q *Queries) DeleteFeedFollowByUserAndFeedURL(ctx context.Context, arg Dele
 	return result.RowsAffected()
 }
 
+const followFeedIfNotFollowing = `-- name: FollowFeedIfNotFollowing :execrows
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, feed_id) DO NOTHING
+`
+
+type FollowFeedIfNotFollowingParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+func (q *Queries) FollowFeedIfNotFollowing(ctx context.Context, arg FollowFeedIfNotFollowingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, followFeedIfNotFollowing,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFeedFollowByUserAndFeedID = `-- name: DeleteFeedFollowByUserAndFeedID :execrows
+DELETE FROM feed_follows
+WHERE user_id = $1 AND feed_id = $2
+`
+
+type DeleteFeedFollowByUserAndFeedIDParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) DeleteFeedFollowByUserAndFeedID(ctx context.Context, arg DeleteFeedFollowByUserAndFeedIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeedFollowByUserAndFeedID, arg.UserID, arg.FeedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getUnfollowedFeeds = `-- name: GetUnfollowedFeeds :many
+SELECT
+    f.id,
+    f.name,
+    f.url,
+    u.name as owner_name,
+    (SELECT COUNT(*) FROM feed_follows c WHERE c.feed_id = f.id) as follower_count
+FROM feeds f
+JOIN users u ON f.user_id = u.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id AND ff.user_id = $1
+)
+ORDER BY follower_count DESC, f.name
+`
+
+type GetUnfollowedFeedsRow struct {
+	ID            uuid.UUID
+	Name          string
+	Url           string
+	OwnerName     string
+	FollowerCount int64
+}
+
+// Feeds the user doesn't follow, most-followed first.
+func (q *Queries) GetUnfollowedFeeds(ctx context.Context, userID uuid.UUID) ([]GetUnfollowedFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnfollowedFeeds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnfollowedFeedsRow
+	for rows.Next() {
+		var i GetUnfollowedFeedsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.OwnerName,
+			&i.FollowerCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFailingFeeds = `-- name: GetFailingFeeds :many
+SELECT id, name, url, consecutive_failures, next_retry_at, last_fetched_at
+FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name
+`
+
+type GetFailingFeedsRow struct {
+	ID                  uuid.UUID
+	Name                string
+	Url                 string
+	ConsecutiveFailures int32
+	NextRetryAt         sql.NullTime
+	LastFetchedAt       sql.NullTime
+}
+
+// Feeds whose most recent fetches failed, worst first.
+func (q *Queries) GetFailingFeeds(ctx context.Context) ([]GetFailingFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFailingFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFailingFeedsRow
+	for rows.Next() {
+		var i GetFailingFeedsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.ConsecutiveFailures,
+			&i.NextRetryAt,
+			&i.LastFetchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedByID = `-- name: GetFeedByID :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description FROM feeds WHERE id = $1
+`
+
+func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByID, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+	)
+	return i, err
+}
+
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, name, url, user_id FROM feeds WHERE url = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description FROM feeds WHERE url = $1
 `
 
 func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
@@ -190,6 +579,10 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
 	)
 	return i, err
 }
@@ -202,7 +595,9 @@ SELECT
     ff.user_id,
     ff.feed_id,
     u.name as user_name,
-    f.name as feed_name
+    f.name as feed_name,
+    f.url as feed_url,
+    f.description as feed_description
 FROM feed_follows ff
 JOIN users u ON ff.user_id = u.id
 JOIN feeds f ON ff.feed_id = f.id
@@ -211,13 +606,15 @@ ORDER BY ff.created_at DESC
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	UserName  string
-	FeedName  string
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          uuid.UUID
+	FeedID          uuid.UUID
+	UserName        string
+	FeedName        string
+	FeedUrl         string
+	FeedDescription sql.NullString
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -237,6 +634,8 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.FeedID,
 			&i.UserName,
 			&i.FeedName,
+			&i.FeedUrl,
+			&i.FeedDescription,
 		); err != nil {
 			return nil, err
 		}
@@ -251,39 +650,68 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
-const getFeedsWithUsers = `-- name: GetFeedsWithUsers :many
-SELECT 
+const getFeedsBatch = `-- name: GetFeedsBatch :many
+SELECT
     f.id,
     f.created_at,
     f.updated_at,
     f.name,
     f.url,
     f.user_id,
-    u.name as user_name
+    u.name as user_name,
+    f.description,
+    f.last_fetched_at
 FROM feeds f
 JOIN users u ON f.user_id = u.id
-ORDER BY f.created_at DESC
+WHERE (f.next_retry_at IS NULL OR f.next_retry_at <= NOW())
+  AND (f.last_fetched_at IS NULL OR f.last_fetched_at < $1::timestamptz)
+  AND (
+    ($2::timestamp IS NULL AND (f.last_fetched_at IS NOT NULL OR f.id > $3))
+    OR f.last_fetched_at > $2
+    OR (f.last_fetched_at = $2 AND f.id > $3)
+  )
+ORDER BY f.last_fetched_at ASC NULLS FIRST, f.id
+LIMIT $4
 `
 
-type GetFeedsWithUsersRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
-	UserName  string
+type GetFeedsBatchParams struct {
+	StartedAt      time.Time
+	AfterFetchedAt sql.NullTime
+	AfterID        uuid.UUID
+	Limit          int32
 }
 
-func (q *Queries) GetFeedsWithUsers(ctx context.Context) ([]GetFeedsWithUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFeedsWithUsers)
+type GetFeedsBatchRow struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Name          string
+	Url           string
+	UserID        uuid.UUID
+	UserName      string
+	Description   sql.NullString
+	LastFetchedAt sql.NullTime
+}
+
+// Keyset-paginated feeds, least recently fetched first with never-fetched
+// feeds ahead of all others: the next limit feeds after the one with
+// after_fetched_at and after_id. A NULL after_fetched_at starts among the
+// never-fetched feeds. Feeds fetched since started_at, i.e. earlier in the
+// same run, and feeds backing off after repeated failures are left out.
+func (q *Queries) GetFeedsBatch(ctx context.Context, arg GetFeedsBatchParams) ([]GetFeedsBatchRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsBatch,
+		arg.StartedAt,
+		arg.AfterFetchedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFeedsWithUsersRow
+	var items []GetFeedsBatchRow
 	for rows.Next() {
-		var i GetFeedsWithUsersRow
+		var i GetFeedsBatchRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -292,6 +720,8 @@ func (q *Queries) GetFeedsWithUsers(ctx context.Context) ([]GetFeedsWithUsersRow
 			&i.Url,
 			&i.UserID,
 			&i.UserName,
+			&i.Description,
+			&i.LastFetchedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -306,52 +736,798 @@ func (q *Queries) GetFeedsWithUsers(ctx context.Context) ([]GetFeedsWithUsersRow
 	return items, nil
 }
 
-const getPostsForUser = `-- name: GetPostsForUser :many
+const getFeedsWithUsers = `-- name: GetFeedsWithUsers :many
 SELECT 
-    p.id,
-    p.created_at,
-    p.updated_at,
-    p.title,
-    p.url,
-    p.description,
+    f.id,
+    f.created_at,
+    f.updated_at,
+    f.name,
+    f.url,
+    f.user_id,
+    u.name as user_name,
+    f.description
+FROM feeds f
+JOIN users u ON f.user_id = u.id
+ORDER BY f.created_at DESC
+`
+
+type GetFeedsWithUsersRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Url         string
+	UserID      uuid.UUID
+	UserName    string
+	Description sql.NullString
+}
+
+func (q *Queries) GetFeedsWithUsers(ctx context.Context) ([]GetFeedsWithUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsWithUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedsWithUsersRow
+	for rows.Next() {
+		var i GetFeedsWithUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.UserName,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForFeed = `-- name: GetPostsForFeed :many
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, search_vector, author, enclosure_url, enclosure_type, enclosure_length, date_source FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC NULLS LAST, created_at DESC
+LIMIT $2
+`
+
+type GetPostsForFeedParams struct {
+	FeedID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) GetPostsForFeed(ctx context.Context, arg GetPostsForFeedParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForFeed, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.SearchVector,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.EnclosureLength,
+			&i.DateSource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT 
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetPostsForUserParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type GetPostsForUserRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserAfter = `-- name: GetPostsForUserAfter :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+  AND ($2::text = '' OR f.url = $2 OR f.name = $2)
+  AND (
+    $3::boolean
+    OR ($4::timestamp IS NULL AND p.published_at IS NULL AND p.id < $5)
+    OR ($4::timestamp IS NOT NULL AND p.published_at IS NULL)
+    OR p.published_at < $4
+    OR (p.published_at = $4 AND p.id < $5)
+  )
+ORDER BY p.published_at DESC NULLS LAST, p.id DESC
+LIMIT $6
+`
+
+type GetPostsForUserAfterParams struct {
+	UserID           uuid.UUID
+	Feed             string
+	First            bool
+	AfterPublishedAt sql.NullTime
+	AfterID          uuid.UUID
+	Limit            int32
+}
+
+type GetPostsForUserAfterRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Keyset-paginated posts from the user's followed feeds, newest first with
+// undated posts last and ties broken by id: the next limit posts after the
+// one with after_published_at and after_id, or the newest limit posts when
+// first is set. A non-empty feed keeps only the followed feed with that URL or
+// exact name.
+func (q *Queries) GetPostsForUserAfter(ctx context.Context, arg GetPostsForUserAfterParams) ([]GetPostsForUserAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserAfter,
+		arg.UserID,
+		arg.Feed,
+		arg.First,
+		arg.AfterPublishedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserAfterRow
+	for rows.Next() {
+		var i GetPostsForUserAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserByFeed = `-- name: GetPostsForUserByFeed :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+  AND (f.url = $2 OR f.name = $2)
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type GetPostsForUserByFeedParams struct {
+	UserID uuid.UUID
+	Feed   string
+	Limit  int32
+	Offset int32
+}
+
+type GetPostsForUserByFeedRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Posts from one followed feed, matched by URL or exact name, newest first.
+func (q *Queries) GetPostsForUserByFeed(ctx context.Context, arg GetPostsForUserByFeedParams) ([]GetPostsForUserByFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserByFeed,
+		arg.UserID,
+		arg.Feed,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserByFeedRow
+	for rows.Next() {
+		var i GetPostsForUserByFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserOldest = `-- name: GetPostsForUserOldest :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.published_at ASC NULLS LAST, p.created_at ASC
+LIMIT $2 OFFSET $3
+`
+
+type GetPostsForUserOldestParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type GetPostsForUserOldestRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Posts from followed feeds, oldest publication date first. Posts without a
+// publication date come last.
+func (q *Queries) GetPostsForUserOldest(ctx context.Context, arg GetPostsForUserOldestParams) ([]GetPostsForUserOldestRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserOldest, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserOldestRow
+	for rows.Next() {
+		var i GetPostsForUserOldestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserRecentlyAdded = `-- name: GetPostsForUserRecentlyAdded :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetPostsForUserRecentlyAddedParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type GetPostsForUserRecentlyAddedRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Posts from followed feeds in the order gator stored them, most recent first.
+func (q *Queries) GetPostsForUserRecentlyAdded(ctx context.Context, arg GetPostsForUserRecentlyAddedParams) ([]GetPostsForUserRecentlyAddedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserRecentlyAdded, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRecentlyAddedRow
+	for rows.Next() {
+		var i GetPostsForUserRecentlyAddedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserSince = `-- name: GetPostsForUserSince :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1 AND p.created_at > $2
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $3
+`
+
+type GetPostsForUserSinceParams struct {
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	Limit     int32
+}
+
+type GetPostsForUserSinceRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Posts from followed feeds that were stored after $2, newest first.
+func (q *Queries) GetPostsForUserSince(ctx context.Context, arg GetPostsForUserSinceParams) ([]GetPostsForUserSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserSince, arg.UserID, arg.CreatedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserSinceRow
+	for rows.Next() {
+		var i GetPostsForUserSinceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentPosts = `-- name: GetRecentPosts :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
-JOIN feed_follows ff ON f.id = ff.feed_id
-WHERE ff.user_id = $1
 ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
-LIMIT $2 OFFSET $3
+LIMIT $1
 `
 
-type GetPostsForUserParams struct {
+type GetRecentPostsRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Newest posts across every feed, regardless of who follows them.
+func (q *Queries) GetRecentPosts(ctx context.Context, limit int32) ([]GetRecentPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentPosts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentPostsRow
+	for rows.Next() {
+		var i GetRecentPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFeedFetched = `-- name: MarkFeedFetched :exec
+UPDATE feeds
+SET last_fetched_at = NOW(), consecutive_failures = 0, next_retry_at = NULL, updated_at = NOW()
+WHERE id = $1
+`
+
+// Records a successful fetch, clearing any failure backoff.
+func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+	return err
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :one
+UPDATE feeds SET consecutive_failures = consecutive_failures + 1, updated_at = NOW()
+WHERE id = $1
+RETURNING consecutive_failures
+`
+
+// Counts a failed fetch and returns the new number of failures in a row.
+func (q *Queries) RecordFeedFailure(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedFailure, id)
+	var consecutive_failures int32
+	err := row.Scan(&consecutive_failures)
+	return consecutive_failures, err
+}
+
+const scheduleFeedRetry = `-- name: ScheduleFeedRetry :exec
+UPDATE feeds SET next_retry_at = $2 WHERE id = $1
+`
+
+type ScheduleFeedRetryParams struct {
+	ID          uuid.UUID
+	NextRetryAt sql.NullTime
+}
+
+func (q *Queries) ScheduleFeedRetry(ctx context.Context, arg ScheduleFeedRetryParams) error {
+	_, err := q.db.ExecContext(ctx, scheduleFeedRetry, arg.ID, arg.NextRetryAt)
+	return err
+}
+
+const searchPostsFTS = `-- name: SearchPostsFTS :many
+SELECT
+        p.id,
+        p.created_at,
+        p.updated_at,
+        p.title,
+        p.url,
+        p.description,
+        p.published_at,
+        p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
+        f.name as feed_name,
+        ts_rank(p.search_vector, plainto_tsquery('english', $1)) AS rank
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $2
+    AND p.search_vector @@ plainto_tsquery('english', $1)
+ORDER BY rank DESC, p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type SearchPostsFTSParams struct {
+	Query  string
 	UserID uuid.UUID
 	Limit  int32
 	Offset int32
 }
 
-type GetPostsForUserRow struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
-	FeedName    string
+type SearchPostsFTSRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+	Rank         float32
 }
 
-func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit, arg.Offset)
+// Full-text search of a user's posts against title and description, most
+// relevant first.
+func (q *Queries) SearchPostsFTS(ctx context.Context, arg SearchPostsFTSParams) ([]SearchPostsFTSRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPostsFTS,
+		arg.Query,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetPostsForUserRow
+	var items []SearchPostsFTSRow
 	for rows.Next() {
-		var i GetPostsForUserRow
+		var i SearchPostsFTSRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -361,7 +1537,11 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
 			&i.FeedName,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -386,6 +1566,9 @@ SELECT
         p.description,
         p.published_at,
         p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
         f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
@@ -407,15 +1590,18 @@ type SearchPostsForUserParams struct {
 }
 
 type SearchPostsForUserRow struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
-	FeedName    string
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
 }
 
 // Search posts for a user by fuzzy match against title or description.
@@ -443,6 +1629,103 @@ func (q *Queries) SearchPostsForUser(ctx context.Context, arg SearchPostsForUser
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPostsForUserSince = `-- name: SearchPostsForUserSince :many
+SELECT
+        p.id,
+        p.created_at,
+        p.updated_at,
+        p.title,
+        p.url,
+        p.description,
+        p.published_at,
+        p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
+        f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+    AND (
+        p.title ILIKE ('%' || $2 || '%')
+        OR p.description ILIKE ('%' || $2 || '%')
+    )
+    AND (p.created_at, p.id) > ($3::timestamp, $4::uuid)
+ORDER BY p.created_at, p.id
+LIMIT $5
+`
+
+type SearchPostsForUserSinceParams struct {
+	UserID    uuid.UUID
+	Column2   sql.NullString
+	CreatedAt time.Time
+	ID        uuid.UUID
+	Limit     int32
+}
+
+type SearchPostsForUserSinceRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Search matches stored after the cursor post (by created_at, then id),
+// oldest first, so a poller can resume from the last post it saw.
+func (q *Queries) SearchPostsForUserSince(ctx context.Context, arg SearchPostsForUserSinceParams) ([]SearchPostsForUserSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPostsForUserSince,
+		arg.UserID,
+		arg.Column2,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsForUserSinceRow
+	for rows.Next() {
+		var i SearchPostsForUserSinceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
 			&i.FeedName,
 		); err != nil {
 			return nil, err
@@ -457,3 +1740,110 @@ func (q *Queries) SearchPostsForUser(ctx context.Context, arg SearchPostsForUser
 	}
 	return items, nil
 }
+
+const updateFeedMetadata = `-- name: UpdateFeedMetadata :one
+UPDATE feeds SET name = $2, description = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description
+`
+
+type UpdateFeedMetadataParams struct {
+	ID          uuid.UUID
+	Name        string
+	Description sql.NullString
+}
+
+// Stores the name and description a feed advertises about itself.
+func (q *Queries) UpdateFeedMetadata(ctx context.Context, arg UpdateFeedMetadataParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedMetadata, arg.ID, arg.Name, arg.Description)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+	)
+	return i, err
+}
+
+const updateFeedName = `-- name: UpdateFeedName :one
+UPDATE feeds SET name = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description
+`
+
+type UpdateFeedNameParams struct {
+	ID        uuid.UUID
+	Name      string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpdateFeedName(ctx context.Context, arg UpdateFeedNameParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedName, arg.ID, arg.Name, arg.UpdatedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+	)
+	return i, err
+}
+
+const updateFeedOwner = `-- name: UpdateFeedOwner :one
+UPDATE feeds SET user_id = $2, updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, consecutive_failures, next_retry_at, description
+`
+
+type UpdateFeedOwnerParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpdateFeedOwner(ctx context.Context, arg UpdateFeedOwnerParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedOwner, arg.ID, arg.UserID, arg.UpdatedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.ConsecutiveFailures,
+		&i.NextRetryAt,
+		&i.Description,
+	)
+	return i, err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feeds SET url = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateFeedURLParams struct {
+	ID  uuid.UUID
+	Url string
+}
+
+// Points a feed at the URL it permanently moved to.
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.ID, arg.Url)
+	return err
+}
@@ -97,6 +97,86 @@ func (q *Queries) GetLikeCountForPost(ctx context.Context, postID uuid.UUID) (in
 	return count, err
 }
 
+const getLikedPostsForUser = `-- name: GetLikedPostsForUser :many
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM likes l
+JOIN posts p ON l.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+WHERE l.user_id = $1
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetLikedPostsForUserParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type GetLikedPostsForUserRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
+}
+
+// Posts the user has liked, ordered like browse (by publication date).
+func (q *Queries) GetLikedPostsForUser(ctx context.Context, arg GetLikedPostsForUserParams) ([]GetLikedPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLikedPostsForUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLikedPostsForUserRow
+	for rows.Next() {
+		var i GetLikedPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLikesForUser = `-- name: GetLikesForUser :many
 SELECT 
     l.id as like_id,
@@ -109,6 +189,9 @@ SELECT
     p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM likes l
 JOIN posts p ON l.post_id = p.id
@@ -125,17 +208,20 @@ type GetLikesForUserParams struct {
 }
 
 type GetLikesForUserRow struct {
-	LikeID      uuid.UUID
-	LikedAt     time.Time
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
-	FeedName    string
+	LikeID       uuid.UUID
+	LikedAt      time.Time
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	EnclosureUrl sql.NullString
+	DateSource   sql.NullString
+	FeedName     string
 }
 
 func (q *Queries) GetLikesForUser(ctx context.Context, arg GetLikesForUserParams) ([]GetLikesForUserRow, error) {
@@ -158,6 +244,9 @@ func (q *Queries) GetLikesForUser(ctx context.Context, arg GetLikesForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Author,
+			&i.EnclosureUrl,
+			&i.DateSource,
 			&i.FeedName,
 		); err != nil {
 			return nil, err
@@ -19,13 +19,36 @@ type Bookmark struct {
 	PostID    uuid.UUID
 }
 
+type BrowseMark struct {
+	UserID        uuid.UUID
+	LastBrowsedAt time.Time
+}
+
 type Feed struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Name                string
+	Url                 string
+	UserID              uuid.UUID
+	LastFetchedAt       sql.NullTime
+	ConsecutiveFailures int32
+	NextRetryAt         sql.NullTime
+	Description         sql.NullString
+}
+
+type FeedCredential struct {
+	FeedID      uuid.UUID
+	Username    string
+	Password    string
+	PasswordEnv string
+	UpdatedAt   time.Time
+}
+
+type FeedHealth struct {
+	FeedID       uuid.UUID
+	FlaggedAt    time.Time
+	SuggestedUrl sql.NullString
 }
 
 type FeedFollow struct {
@@ -45,20 +68,45 @@ type Like struct {
 }
 
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.UUID
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Title           string
+	Url             string
+	Description     sql.NullString
+	PublishedAt     sql.NullTime
+	FeedID          uuid.UUID
+	SearchVector    interface{}
+	Author          sql.NullString
+	EnclosureUrl    sql.NullString
+	EnclosureType   sql.NullString
+	EnclosureLength sql.NullInt64
+	DateSource      sql.NullString
 }
 
-type User struct {
+type PostCategory struct {
+	PostID   uuid.UUID
+	Category string
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostUserTag struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	ApiKey    sql.NullString
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Tag       string
+}
+
+type User struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	ApiKeyHash sql.NullString
 }
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingDriver is a database/sql driver that accepts any statement and
+// counts how many times one is prepared
+type countingDriver struct {
+	prepares atomic.Int64
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return countingConn{d}, nil }
+
+type countingConn struct{ d *countingDriver }
+
+func (c countingConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.prepares.Add(1)
+	return countingStmt{}, nil
+}
+func (c countingConn) Close() error              { return nil }
+func (c countingConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type countingStmt struct{}
+
+func (countingStmt) Close() error  { return nil }
+func (countingStmt) NumInput() int { return -1 }
+func (countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (countingStmt) Query([]driver.Value) (driver.Rows, error) { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"exists"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+var (
+	registerOnce sync.Once
+	testDriver   = &countingDriver{}
+)
+
+// openCountingDB opens a database on the counting driver and returns it with
+// a function reporting the prepares since the call
+func openCountingDB(t testing.TB) (*sql.DB, func() int64) {
+	t.Helper()
+	registerOnce.Do(func() { sql.Register("counting", testDriver) })
+	db, err := sql.Open("counting", "")
+	if err != nil {
+		t.Fatalf("couldn't open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	start := testDriver.prepares.Load()
+	return db, func() int64 { return testDriver.prepares.Load() - start }
+}
+
+// runQueries runs the same exec and row query n times each, as saving a
+// batch of posts does. It may run on several goroutines, so it reports
+// failures with Errorf.
+func runQueries(t testing.TB, q *Queries, n int) {
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		if err := q.AddPostCategory(ctx, AddPostCategoryParams{Category: "go"}); err != nil {
+			t.Errorf("AddPostCategory returned error: %v", err)
+			return
+		}
+		if _, err := q.FeedHasPosts(ctx, [16]byte{}); err != sql.ErrNoRows {
+			t.Errorf("FeedHasPosts returned %v; want sql.ErrNoRows from the empty fake", err)
+			return
+		}
+	}
+}
+
+func TestStmtCache_PreparesEachQueryOnce(t *testing.T) {
+	const runs = 50
+
+	db, prepares := openCountingDB(t)
+	runQueries(t, New(db), runs)
+	unprepared := prepares()
+	if unprepared != 2*runs {
+		t.Fatalf("plain Queries prepared %d statements; want one per call (%d)", unprepared, 2*runs)
+	}
+
+	db, prepares = openCountingDB(t)
+	cache := NewStmtCache(db)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runQueries(t, New(cache), runs)
+		}()
+	}
+	wg.Wait()
+	if got := prepares(); got != 2 || cache.Prepares() != 2 {
+		t.Fatalf("StmtCache prepared %d statements (reported %d) across 4 workers; want 2", got, cache.Prepares())
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	runQueries(t, New(cache), 1)
+	if got := prepares(); got != 4 {
+		t.Fatalf("expected statements to be prepared again after Close, got %d prepares", got)
+	}
+}
+
+func BenchmarkQueries_Unprepared(b *testing.B) {
+	db, prepares := openCountingDB(b)
+	q := New(db)
+	b.ResetTimer()
+	runQueries(b, q, b.N)
+	b.ReportMetric(float64(prepares())/float64(b.N), "prepares/op")
+}
+
+func BenchmarkQueries_StmtCache(b *testing.B) {
+	db, prepares := openCountingDB(b)
+	cache := NewStmtCache(db)
+	defer cache.Close()
+	q := New(cache)
+	b.ResetTimer()
+	runQueries(b, q, b.N)
+	b.ReportMetric(float64(prepares())/float64(b.N), "prepares/op")
+}
@@ -14,7 +14,7 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name, api_key)
+INSERT INTO users (id, created_at, updated_at, name, api_key_hash)
 VALUES (
     $1,
     $2,
@@ -22,15 +22,15 @@ VALUES (
     $4,
     $5
 )
-RETURNING id, created_at, updated_at, name, api_key
+RETURNING id, created_at, updated_at, name, api_key_hash
 `
 
 type CreateUserParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	ApiKey    sql.NullString
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	ApiKeyHash sql.NullString
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -39,7 +39,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.Name,
-		arg.ApiKey,
+		arg.ApiKeyHash,
 	)
 	var i User
 	err := row.Scan(
@@ -47,7 +47,49 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		&i.ApiKey,
+		&i.ApiKeyHash,
+	)
+	return i, err
+}
+
+const createUserIfNotExists = `-- name: CreateUserIfNotExists :one
+INSERT INTO users (id, created_at, updated_at, name, api_key_hash)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (name) DO NOTHING
+RETURNING id, created_at, updated_at, name, api_key_hash
+`
+
+type CreateUserIfNotExistsParams struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	ApiKeyHash sql.NullString
+}
+
+// Inserts a user unless the name is already taken. Returns no row on conflict,
+// so concurrent registrations of the same name resolve to exactly one winner.
+func (q *Queries) CreateUserIfNotExists(ctx context.Context, arg CreateUserIfNotExistsParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUserIfNotExists,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.ApiKeyHash,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.ApiKeyHash,
 	)
 	return i, err
 }
@@ -62,7 +104,7 @@ func (q *Queries) DeleteAllUsers(ctx context.Context) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, created_at, updated_at, name, api_key FROM users WHERE name = $1
+SELECT id, created_at, updated_at, name, api_key_hash FROM users WHERE name = $1
 `
 
 func (q *Queries) GetUser(ctx context.Context, name string) (User, error) {
@@ -73,30 +115,30 @@ func (q *Queries) GetUser(ctx context.Context, name string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		&i.ApiKey,
+		&i.ApiKeyHash,
 	)
 	return i, err
 }
 
-const getUserByAPIKey = `-- name: GetUserByAPIKey :one
-SELECT id, created_at, updated_at, name, api_key FROM users WHERE api_key = $1
+const getUserByAPIKeyHash = `-- name: GetUserByAPIKeyHash :one
+SELECT id, created_at, updated_at, name, api_key_hash FROM users WHERE api_key_hash = $1
 `
 
-func (q *Queries) GetUserByAPIKey(ctx context.Context, apiKey sql.NullString) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByAPIKey, apiKey)
+func (q *Queries) GetUserByAPIKeyHash(ctx context.Context, apiKeyHash sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByAPIKeyHash, apiKeyHash)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
-		&i.ApiKey,
+		&i.ApiKeyHash,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, created_at, updated_at, name, api_key FROM users
+SELECT id, created_at, updated_at, name, api_key_hash FROM users
 `
 
 func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
@@ -113,7 +155,7 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Name,
-			&i.ApiKey,
+			&i.ApiKeyHash,
 		); err != nil {
 			return nil, err
 		}
@@ -129,15 +171,15 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 }
 
 const updateUserAPIKey = `-- name: UpdateUserAPIKey :exec
-UPDATE users SET api_key = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+UPDATE users SET api_key_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
 `
 
 type UpdateUserAPIKeyParams struct {
-	ApiKey sql.NullString
-	ID     uuid.UUID
+	ApiKeyHash sql.NullString
+	ID         uuid.UUID
 }
 
 func (q *Queries) UpdateUserAPIKey(ctx context.Context, arg UpdateUserAPIKeyParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserAPIKey, arg.ApiKey, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateUserAPIKey, arg.ApiKeyHash, arg.ID)
 	return err
 }
@@ -0,0 +1,125 @@
+// Package dbtest provides helpers for tests that need a real PostgreSQL database.
+package dbtest
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// EnvVar names the environment variable holding the test database URL
+const EnvVar = "GATOR_TEST_DB_URL"
+
+// Open connects to the database named by GATOR_TEST_DB_URL, creates a fresh
+// schema with all migrations applied, and drops it when the test finishes.
+// The test is skipped when the variable is not set.
+func Open(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dbURL := os.Getenv(EnvVar)
+	if dbURL == "" {
+		t.Skipf("%s not set; skipping database test", EnvVar)
+	}
+
+	admin, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("couldn't open test database: %v", err)
+	}
+	defer admin.Close()
+
+	schema := "gator_test_" + randomSuffix(t)
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("couldn't create test schema: %v", err)
+	}
+
+	// Every pooled connection must resolve tables in the test schema
+	scopedURL, err := withSearchPath(dbURL, schema)
+	if err != nil {
+		t.Fatalf("couldn't build test database URL: %v", err)
+	}
+	db, err := sql.Open("postgres", scopedURL)
+	if err != nil {
+		t.Fatalf("couldn't open test schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		cleanup, err := sql.Open("postgres", dbURL)
+		if err != nil {
+			return
+		}
+		defer cleanup.Close()
+		cleanup.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	if err := migrate(db); err != nil {
+		t.Fatalf("couldn't apply migrations: %v", err)
+	}
+	return db
+}
+
+// migrate applies the "Up" section of every goose migration in sql/schema
+func migrate(db *sql.DB) error {
+	_, file, _, _ := runtime.Caller(0)
+	schemaDir := filepath.Join(filepath.Dir(file), "..", "..", "sql", "schema")
+
+	files, err := filepath.Glob(filepath.Join(schemaDir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(upSection(string(data))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upSection returns the statements between "-- +goose Up" and "-- +goose Down"
+func upSection(migration string) string {
+	up := migration
+	if i := strings.Index(up, "-- +goose Up"); i >= 0 {
+		up = up[i+len("-- +goose Up"):]
+	}
+	if i := strings.Index(up, "-- +goose Down"); i >= 0 {
+		up = up[:i]
+	}
+	up = strings.ReplaceAll(up, "-- +goose StatementBegin", "")
+	up = strings.ReplaceAll(up, "-- +goose StatementEnd", "")
+	return up
+}
+
+// withSearchPath adds a search_path runtime parameter to a postgres URL
+func withSearchPath(dbURL, schema string) (string, error) {
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func randomSuffix(t *testing.T) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("couldn't generate schema name: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,135 @@
+package rss
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// atomNamespace is the XML namespace of Atom 1.0 documents
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// atomFeed represents the parts of an Atom <feed> gator uses
+type atomFeed struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+// atomEntry represents a single <entry> in an Atom feed
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary"`
+	Content    string         `xml:"content"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	ID         string         `xml:"id"`
+	Author     atomPerson     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// atomCategory is an Atom <category>; its name is in the term attribute
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// atomPerson is an Atom person construct, such as an entry's <author>
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// atomLink is an Atom <link>; the URL is in its href attribute
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// isAtom reports whether body's root element is an Atom <feed>
+func isAtom(body []byte) bool {
+	decoder := newXMLDecoder(body)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "feed" && (start.Name.Space == atomNamespace || start.Name.Space == "")
+		}
+	}
+}
+
+// decodeAtom unmarshals an Atom document into the same RSSFeed shape used for
+// RSS 2.0, so the rest of the pipeline doesn't need to know the difference
+func decodeAtom(body []byte) (*RSSFeed, error) {
+	var atom atomFeed
+	if err := unmarshalXML(body, &atom); err != nil {
+		return nil, err
+	}
+
+	feed := &RSSFeed{Channel: RSSChannel{
+		Title:         atom.Title,
+		Link:          alternateLink(atom.Links),
+		Description:   atom.Subtitle,
+		LastBuildDate: atom.Updated,
+	}}
+	for _, entry := range atom.Entries {
+		description := entry.Summary
+		if strings.TrimSpace(description) == "" {
+			description = entry.Content
+		}
+		pubDate := entry.Published
+		if strings.TrimSpace(pubDate) == "" {
+			pubDate = entry.Updated
+		}
+		feed.Channel.Items = append(feed.Channel.Items, RSSItem{
+			Title:       entry.Title,
+			Link:        alternateLink(entry.Links),
+			Description: description,
+			PubDate:     pubDate,
+			GUID:        entry.ID,
+			Author:      entry.Author.Name,
+			Categories:  categoryTerms(entry.Categories),
+			Enclosure:   enclosureLink(entry.Links),
+		})
+	}
+	return feed, nil
+}
+
+// categoryTerms returns the names of an entry's categories
+func categoryTerms(categories []atomCategory) []string {
+	var terms []string
+	for _, category := range categories {
+		terms = append(terms, category.Term)
+	}
+	return terms
+}
+
+// enclosureLink returns the first link with rel="enclosure" as an Enclosure
+func enclosureLink(links []atomLink) *Enclosure {
+	for _, link := range links {
+		if link.Rel == "enclosure" && link.Href != "" {
+			length, _ := strconv.ParseInt(strings.TrimSpace(link.Length), 10, 64)
+			return &Enclosure{URL: link.Href, Type: link.Type, Length: length}
+		}
+	}
+	return nil
+}
+
+// alternateLink picks the link pointing at the page itself: the one with
+// rel="alternate" (the default when rel is missing), else the first link
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
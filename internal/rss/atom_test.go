@@ -0,0 +1,79 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const sampleAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Blog</title>
+  <subtitle>Notes &amp; essays</subtitle>
+  <link rel="self" href="https://example.com/atom.xml"/>
+  <link href="https://example.com/"/>
+  <updated>2024-03-02T10:00:00Z</updated>
+  <id>urn:uuid:feed</id>
+  <entry>
+    <title>First post</title>
+    <link rel="alternate" href="https://example.com/first"/>
+    <id>urn:uuid:1</id>
+    <published>2024-03-01T09:00:00Z</published>
+    <updated>2024-03-02T09:00:00Z</updated>
+    <summary>A short summary</summary>
+    <content type="html">&lt;p&gt;Full text&lt;/p&gt;</content>
+    <author><name>Jane Doe</name></author>
+    <category term="go"/>
+    <category term="testing"/>
+  </entry>
+  <entry>
+    <title>Second post</title>
+    <link href="https://example.com/second"/>
+    <id>urn:uuid:2</id>
+    <updated>2024-03-02T10:00:00Z</updated>
+    <content>Only content here</content>
+  </entry>
+</feed>`
+
+func TestIsAtom(t *testing.T) {
+	if !isAtom([]byte(sampleAtom)) {
+		t.Fatalf("expected sample Atom document to be detected")
+	}
+	if isAtom([]byte(`<?xml version="1.0"?><rss version="2.0"><channel/></rss>`)) {
+		t.Fatalf("expected RSS document not to be detected as Atom")
+	}
+}
+
+func TestFetchFeed_Atom(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(sampleAtom))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.Channel.Title != "Example Blog" || feed.Channel.Description != "Notes & essays" || feed.Channel.Link != "https://example.com/" {
+		t.Fatalf("unexpected channel: %+v", feed.Channel)
+	}
+
+	want := []RSSItem{
+		{Title: "First post", Link: "https://example.com/first", Description: "A short summary", PubDate: "2024-03-01T09:00:00Z", GUID: "urn:uuid:1", Author: "Jane Doe", Categories: []string{"go", "testing"}},
+		{Title: "Second post", Link: "https://example.com/second", Description: "Only content here", PubDate: "2024-03-02T10:00:00Z", GUID: "urn:uuid:2"},
+	}
+	if len(feed.Channel.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d: %+v", len(want), len(feed.Channel.Items), feed.Channel.Items)
+	}
+	for i, item := range want {
+		if !reflect.DeepEqual(feed.Channel.Items[i], item) {
+			t.Errorf("item %d = %+v; want %+v", i, feed.Channel.Items[i], item)
+		}
+		if _, err := parsePubDate(feed.Channel.Items[i].PubDate); err != nil {
+			t.Errorf("item %d date %q isn't parseable for saving: %v", i, feed.Channel.Items[i].PubDate, err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+)
+
+// BasicAuth holds the HTTP Basic Auth credentials for a private feed
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+type basicAuthKey struct{}
+
+// WithBasicAuth returns a copy of ctx under which FetchFeed and
+// DiscoverFeedURL send auth with their requests. Credentials travel in the
+// context so any FetchFunc, retried or not, picks them up.
+func WithBasicAuth(ctx context.Context, auth BasicAuth) context.Context {
+	return context.WithValue(ctx, basicAuthKey{}, auth)
+}
+
+// setBasicAuth adds the credentials from req's context, if any, to req
+func setBasicAuth(req *http.Request) {
+	if auth, ok := req.Context().Value(basicAuthKey{}).(BasicAuth); ok && auth.Username != "" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+}
@@ -0,0 +1,141 @@
+package rss
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CircuitBreaker tracks consecutive fetch failures per host and short-circuits
+// requests to hosts that appear to be down.
+//
+// A host's circuit opens after Threshold consecutive failures within Window.
+// While open, Allow reports false until Cooldown has elapsed, after which a
+// single probe request is let through (half-open). A success closes the
+// circuit again; a failed probe re-opens it for another cooldown.
+type CircuitBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+	now   func() time.Time
+}
+
+// hostCircuit holds the breaker state for a single host
+type hostCircuit struct {
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	open         bool
+	probing      bool
+}
+
+// NewCircuitBreaker creates a circuit breaker with the given settings
+func NewCircuitBreaker(threshold int, window, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Window:    window,
+		Cooldown:  cooldown,
+		hosts:     make(map[string]*hostCircuit),
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a request to the given host should be attempted
+func (b *CircuitBreaker) Allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hc, ok := b.hosts[host]
+	if !ok || !hc.open {
+		return true
+	}
+
+	// Only one probe at a time is allowed through once the cooldown expires
+	if hc.probing || b.now().Sub(hc.openedAt) < b.Cooldown {
+		return false
+	}
+	hc.probing = true
+	return true
+}
+
+// RecordSuccess closes the circuit for the given host
+func (b *CircuitBreaker) RecordSuccess(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.hosts, host)
+}
+
+// RecordFailure counts a failure for the given host, opening its circuit once
+// the threshold is reached within the window
+func (b *CircuitBreaker) RecordFailure(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	hc, ok := b.hosts[host]
+	if !ok {
+		hc = &hostCircuit{}
+		b.hosts[host] = hc
+	}
+
+	// A failed half-open probe re-opens the circuit for another cooldown
+	if hc.open {
+		hc.openedAt = now
+		hc.probing = false
+		return
+	}
+
+	// Start a new failure window if the previous one has expired
+	if hc.failures == 0 || now.Sub(hc.firstFailure) > b.Window {
+		hc.failures = 0
+		hc.firstFailure = now
+	}
+	hc.failures++
+
+	if hc.failures >= b.Threshold {
+		hc.open = true
+		hc.openedAt = now
+	}
+}
+
+// Abandon ends a half-open probe to the given host that finished without an
+// outcome, such as one cut off by the run's deadline, so that the next request
+// after the cooldown can probe the host again. The circuit stays open.
+func (b *CircuitBreaker) Abandon(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if hc, ok := b.hosts[host]; ok {
+		hc.probing = false
+	}
+}
+
+// IsHostFailure reports whether a fetch error suggests the feed's host is
+// down: a network error or a 5xx response. Errors that belong to one feed,
+// such as a 404 or a malformed document, don't count against its host.
+func IsHostFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// HostKey returns the lowercased host of a feed URL, used to group feeds by
+// the server they are fetched from. Unparseable URLs are returned unchanged.
+func HostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.ToLower(u.Host)
+}
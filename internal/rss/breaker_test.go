@@ -0,0 +1,134 @@
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAndHalfOpens(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(3, time.Minute, 5*time.Minute)
+	b.now = func() time.Time { return now }
+
+	host := "dead.example.com"
+	for i := 0; i < 3; i++ {
+		if !b.Allow(host) {
+			t.Fatalf("expected request %d to be allowed before threshold", i+1)
+		}
+		b.RecordFailure(host)
+	}
+
+	// Circuit is now open: requests are skipped until the cooldown expires
+	if b.Allow(host) {
+		t.Fatalf("expected circuit to be open after 3 failures")
+	}
+	now = now.Add(4 * time.Minute)
+	if b.Allow(host) {
+		t.Fatalf("expected circuit to stay open before cooldown")
+	}
+
+	// Other hosts are unaffected
+	if !b.Allow("alive.example.com") {
+		t.Fatalf("expected unrelated host to be allowed")
+	}
+
+	// After the cooldown a single probe is let through
+	now = now.Add(2 * time.Minute)
+	if !b.Allow(host) {
+		t.Fatalf("expected half-open probe after cooldown")
+	}
+	if b.Allow(host) {
+		t.Fatalf("expected only one probe while half-open")
+	}
+
+	// A failed probe re-opens the circuit
+	b.RecordFailure(host)
+	if b.Allow(host) {
+		t.Fatalf("expected circuit to re-open after failed probe")
+	}
+
+	// A successful probe closes it
+	now = now.Add(6 * time.Minute)
+	if !b.Allow(host) {
+		t.Fatalf("expected probe after second cooldown")
+	}
+	b.RecordSuccess(host)
+	if !b.Allow(host) || !b.Allow(host) {
+		t.Fatalf("expected circuit to be closed after success")
+	}
+}
+
+func TestCircuitBreaker_AbandonedProbeAllowsAnother(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(1, time.Minute, 5*time.Minute)
+	b.now = func() time.Time { return now }
+
+	host := "slow.example.com"
+	b.RecordFailure(host)
+	now = now.Add(6 * time.Minute)
+	if !b.Allow(host) {
+		t.Fatalf("expected half-open probe after cooldown")
+	}
+
+	// A probe cancelled before it finished leaves no outcome behind
+	b.Abandon(host)
+	if !b.Allow(host) {
+		t.Fatalf("expected the host to be probed again after an abandoned probe")
+	}
+	if b.Allow(host) {
+		t.Fatalf("expected only one probe while half-open")
+	}
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowReset(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(2, time.Minute, time.Hour)
+	b.now = func() time.Time { return now }
+
+	host := "flaky.example.com"
+	b.RecordFailure(host)
+	now = now.Add(2 * time.Minute)
+	b.RecordFailure(host)
+
+	if !b.Allow(host) {
+		t.Fatalf("expected failures outside the window not to open the circuit")
+	}
+}
+
+func TestHostKey(t *testing.T) {
+	cases := map[string]string{
+		"https://Example.com/feed.xml":   "example.com",
+		"http://example.com:8080/rss":    "example.com:8080",
+		"https://blog.example.com/atom/": "blog.example.com",
+		"not a url":                      "not a url",
+	}
+	for input, want := range cases {
+		if got := HostKey(input); got != want {
+			t.Errorf("HostKey(%q) = %q; want %q", input, got, want)
+		}
+	}
+}
+
+func TestIsHostFailure(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{fmt.Errorf("fetch: %w", io.ErrUnexpectedEOF), true},
+		{&StatusError{StatusCode: http.StatusBadGateway}, true},
+		{&StatusError{StatusCode: http.StatusNotFound}, false},
+		{&StatusError{StatusCode: http.StatusGone}, false},
+		{errors.New("couldn't parse feed"), false},
+	}
+	for _, c := range cases {
+		if got := IsHostFailure(c.err); got != c.want {
+			t.Errorf("IsHostFailure(%v) = %v; want %v", c.err, got, c.want)
+		}
+	}
+}
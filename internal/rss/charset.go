@@ -0,0 +1,72 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// windows1252 maps bytes 0x80-0x9F to the characters Windows-1252 puts there;
+// every other byte means the same code point as in ISO-8859-1. Unassigned
+// positions map to the replacement character.
+var windows1252 = [32]rune{
+	'€', '�', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '�', 'Ž', '�',
+	'�', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '�', 'ž', 'Ÿ',
+}
+
+// charsetReader is an xml.Decoder CharsetReader that transcodes the
+// single-byte encodings feeds commonly declare to UTF-8
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	var table *[32]rune
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1":
+	case "windows-1252", "cp1252", "x-cp1252":
+		table = &windows1252
+	default:
+		return nil, fmt.Errorf("unsupported feed encoding %q", label)
+	}
+
+	raw, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make([]byte, 0, len(raw)+len(raw)/4)
+	for _, b := range raw {
+		r := rune(b)
+		if table != nil && b >= 0x80 && b <= 0x9F {
+			r = table[b-0x80]
+		}
+		decoded = utf8.AppendRune(decoded, r)
+	}
+	return bytes.NewReader(decoded), nil
+}
+
+// newXMLDecoder returns a decoder for body that honours the encoding named in
+// its XML declaration
+func newXMLDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charsetReader
+	return decoder
+}
+
+// unmarshalXML is xml.Unmarshal with support for non-UTF-8 documents
+func unmarshalXML(body []byte, v any) error {
+	return newXMLDecoder(body).Decode(v)
+}
+
+// xmlDeclaration returns the <?xml ...?> declaration body starts with, or nil
+func xmlDeclaration(body []byte) []byte {
+	if !isXMLDeclaration(body) {
+		return nil
+	}
+	end := bytes.Index(body, []byte("?>"))
+	if end < 0 {
+		return nil
+	}
+	return body[:end+len("?>")]
+}
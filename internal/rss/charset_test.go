@@ -0,0 +1,53 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchFeed_Windows1252(t *testing.T) {
+	// "Café \x93naïve\x94 \x80" in Windows-1252: é=0xE9, ï=0xEF, curly quotes=0x93/0x94, €=0x80
+	body := []byte("<?xml version=\"1.0\" encoding=\"windows-1252\"?>\n" +
+		"<rss version=\"2.0\"><channel><title>Caf\xe9 \x93na\xefve\x94 \x80</title>" +
+		"<item><title>R\xe9sum\xe9</title><link>https://example.com/1</link></item>" +
+		"</channel></rss>")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if want := "Café “naïve” €"; feed.Channel.Title != want {
+		t.Fatalf("channel title = %q; want %q", feed.Channel.Title, want)
+	}
+	if len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Title != "Résumé" {
+		t.Fatalf("unexpected items: %+v", feed.Channel.Items)
+	}
+}
+
+func TestDecodeFeed_Latin1MalformedItemFallback(t *testing.T) {
+	body := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+		"<rss version=\"2.0\"><channel><title>Fran\xe7ais</title>" +
+		"<item><title>\xc9t\xe9</title><link>https://example.com/1</link></item>" +
+		"<item><title>Broken &bogus;</title><description><b></description></item>" +
+		"</channel></rss>")
+
+	feed, skipped, err := decodeFeed(body)
+	if err != nil {
+		t.Fatalf("decodeFeed returned error: %v", err)
+	}
+	if skipped != 1 || feed.Channel.Title != "Français" || len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Title != "Été" {
+		t.Fatalf("unexpected result: skipped=%d feed=%+v", skipped, feed.Channel)
+	}
+}
+
+func TestCharsetReader_Unsupported(t *testing.T) {
+	if _, err := charsetReader("shift_jis", nil); err == nil {
+		t.Fatalf("expected an error for an unsupported encoding")
+	}
+}
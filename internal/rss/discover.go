@@ -0,0 +1,109 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxDiscoveryBytes caps how much of an HTML page is scanned for feed links
+const maxDiscoveryBytes = 1 << 20
+
+var (
+	linkTagPattern   = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// ErrNoFeedFound is returned when an HTML page doesn't advertise a feed
+var ErrNoFeedFound = errors.New("no feed link found")
+
+// feedLinkTypes are the <link type="..."> values that advertise a feed
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// DiscoverFeedURL resolves the feed URL for pageURL. If pageURL serves an HTML
+// page, the first <link rel="alternate"> advertising a feed is returned as an
+// absolute URL; otherwise pageURL is assumed to be the feed itself and returned
+// unchanged. An error is returned if an HTML page advertises no feed. Of
+// opts, only UserAgent is used.
+func DiscoverFeedURL(ctx context.Context, client *http.Client, pageURL string, opts FetchOptions) (string, error) {
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	setBasicAuth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return pageURL, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoveryBytes))
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve relative links against the final URL after any redirects
+	base := resp.Request.URL
+	for _, href := range findFeedLinks(string(body)) {
+		ref, err := url.Parse(href)
+		if err != nil {
+			continue
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+
+	return "", fmt.Errorf("%w on HTML page %s", ErrNoFeedFound, pageURL)
+}
+
+// findFeedLinks returns the href of every <link rel="alternate"> tag with a feed
+// media type, in document order
+func findFeedLinks(page string) []string {
+	var links []string
+	for _, tag := range linkTagPattern.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, m := range attributePattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+		}
+
+		if !hasToken(attrs["rel"], "alternate") || !feedLinkTypes[strings.ToLower(strings.TrimSpace(attrs["type"]))] {
+			continue
+		}
+		if href := strings.TrimSpace(html.UnescapeString(attrs["href"])); href != "" {
+			links = append(links, href)
+		}
+	}
+	return links
+}
+
+// hasToken reports whether a space-separated attribute value contains token
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(strings.ToLower(value)) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
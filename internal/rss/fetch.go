@@ -1,14 +1,20 @@
 package rss
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"database/sql"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"html"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,15 +23,154 @@ import (
 	"github.com/google/uuid"
 )
 
+// Default transport settings for NewHTTPClient. Idle connections are kept
+// briefly so bursts of fetches reuse them, then closed so connections to many
+// different hosts don't pile up.
+const (
+	defaultClientTimeout       = 30 * time.Second
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 2
+	defaultIdleConnTimeout     = 30 * time.Second
+)
+
+// ClientOption configures the client returned by NewHTTPClient
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	timeout             time.Duration
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+}
+
+// WithTimeout sets the overall timeout for each request
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.timeout = d }
+}
+
+// WithMaxIdleConns caps idle connections kept across all hosts
+func WithMaxIdleConns(n int) ClientOption {
+	return func(o *clientOptions) { o.maxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost caps idle connections kept to a single host
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(o *clientOptions) { o.maxIdleConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before closing
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.idleConnTimeout = d }
+}
+
+// WithWorkers sizes the idle pool for the given number of concurrent fetchers:
+// every worker can keep a connection to the same host, and the total pool has
+// room for a couple of hosts per worker.
+func WithWorkers(workers int) ClientOption {
+	return func(o *clientOptions) {
+		if workers <= 0 {
+			return
+		}
+		o.maxIdleConnsPerHost = workers
+		o.maxIdleConns = max(defaultMaxIdleConns, 2*workers)
+	}
+}
+
 // NewHTTPClient creates a new HTTP client with proper timeout configuration
-func NewHTTPClient() *http.Client {
+// and a transport tuned for bursty concurrent fetches
+func NewHTTPClient(opts ...ClientOption) *http.Client {
+	o := clientOptions{
+		timeout:             defaultClientTimeout,
+		maxIdleConns:        defaultMaxIdleConns,
+		maxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+		idleConnTimeout:     defaultIdleConnTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = o.maxIdleConns
+	transport.MaxIdleConnsPerHost = o.maxIdleConnsPerHost
+	transport.IdleConnTimeout = o.idleConnTimeout
+
 	return &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout:   o.timeout,
+		Transport: transport,
 	}
 }
 
-// FetchFeed fetches a feed from the given URL and returns a filled-out RSSFeed struct
+// DefaultMaxFeedSize is the largest feed body FetchFeed reads, in bytes
+const DefaultMaxFeedSize = 10 << 20
+
+// ErrFeedTooLarge is returned when a feed's body is over the size limit
+var ErrFeedTooLarge = errors.New("feed too large")
+
+// DefaultMaxRedirects is how many redirects FetchFeed follows before giving up
+const DefaultMaxRedirects = 5
+
+// DefaultUserAgent is the User-Agent header FetchFeed sends. It names gator
+// and links to the project so feed owners know who is fetching.
+const DefaultUserAgent = "gator/1.0 (+https://github.com/see-why/Gator)"
+
+// FetchOptions tunes FetchFeedWithOptions. Its zero value gives FetchFeed's
+// defaults.
+type FetchOptions struct {
+	// MaxBodySize caps the feed body in bytes, both as sent and once
+	// decompressed; zero means DefaultMaxFeedSize
+	MaxBodySize int64
+	// MaxRedirects caps the redirects followed; zero means DefaultMaxRedirects
+	MaxRedirects int
+	// UserAgent is sent as the User-Agent header; empty means DefaultUserAgent
+	UserAgent string
+}
+
+// limitRedirects returns a CheckRedirect policy that stops after max redirects
+func limitRedirects(max int) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return fmt.Errorf("stopped after %d redirects", max)
+		}
+		return nil
+	}
+}
+
+// movedPermanently reports whether resp was reached through at least one
+// redirect and every redirect on the way was permanent (301 or 308)
+func movedPermanently(resp *http.Response) bool {
+	redirected := false
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		switch req.Response.StatusCode {
+		case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+			redirected = true
+		default:
+			return false
+		}
+	}
+	return redirected
+}
+
+// FetchFeed fetches an RSS 2.0, Atom, or JSON Feed from the given URL and
+// returns a filled-out RSSFeed struct
 func FetchFeed(ctx context.Context, client *http.Client, feedURL string) (*RSSFeed, error) {
+	return FetchFeedWithOptions(ctx, client, feedURL, FetchOptions{})
+}
+
+// FetchFeedWithOptions is FetchFeed with its limits set by opts
+func FetchFeedWithOptions(ctx context.Context, client *http.Client, feedURL string, opts FetchOptions) (*RSSFeed, error) {
+	maxSize := opts.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFeedSize
+	}
+	maxRedirects := opts.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
 	// Validate that the client has a reasonable timeout
 	if client.Timeout == 0 {
 		return nil, fmt.Errorf("HTTP client must have a timeout configured")
@@ -37,27 +182,68 @@ func FetchFeed(ctx context.Context, client *http.Client, feedURL string) (*RSSFe
 	}
 
 	// Set User-Agent header to identify our program
-	req.Header.Set("User-Agent", "gator")
+	req.Header.Set("User-Agent", userAgent)
+	setBasicAuth(req)
+	// Asking for compression ourselves turns off the transport's automatic
+	// decompression, so the body is decompressed below
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
 
-	// Make the request using the provided client
-	resp, err := client.Do(req)
+	// Make the request using a copy of the provided client, so its own
+	// redirect policy is left alone
+	capped := *client
+	capped.CheckRedirect = limitRedirects(maxRedirects)
+	resp, err := capped.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newStatusError(resp, time.Now())
+	}
+
+	// Read the response body, giving up if the server stalls past the deadline
+	body, err := readBody(ctx, resp.Body, maxSize)
 	if err != nil {
 		return nil, err
 	}
+	body, err = decodeContent(resp.Header.Get("Content-Encoding"), body, maxSize)
+	if errors.Is(err, ErrFeedTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decompress feed: %w", err)
+	}
 
-	// Unmarshal the XML into RSSFeed struct
-	var feed RSSFeed
-	err = xml.Unmarshal(body, &feed)
+	contentType := resp.Header.Get("Content-Type")
+	feedType := isFeedContentType(contentType)
+	if !feedType {
+		log.Printf("Warning: feed %s was served as %q, not XML or JSON", feedURL, contentType)
+	}
+
+	// Unmarshal the XML into RSSFeed struct, skipping any broken items.
+	// Atom and JSON Feed documents are mapped onto the same shape.
+	body = trimFeedPrologue(body)
+	var feed *RSSFeed
+	skipped := 0
+	switch {
+	case isJSONFeed(contentType, body):
+		feed, err = decodeJSONFeed(body)
+	case isAtom(body):
+		feed, err = decodeAtom(body)
+	default:
+		feed, skipped, err = decodeFeed(body)
+	}
+	if err != nil && !feedType {
+		// Most likely an HTML page, e.g. a login wall or a "moved" notice
+		return nil, fmt.Errorf("not a feed: HTTP %d with Content-Type %q: %w", resp.StatusCode, contentType, err)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if skipped > 0 {
+		log.Printf("Warning: Skipped %d malformed items in feed %s", skipped, feedURL)
+	}
 
 	// Decode HTML entities in channel title and description
 	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
@@ -67,36 +253,339 @@ func FetchFeed(ctx context.Context, client *http.Client, feedURL string) (*RSSFe
 	for i := range feed.Channel.Items {
 		feed.Channel.Items[i].Title = html.UnescapeString(feed.Channel.Items[i].Title)
 		feed.Channel.Items[i].Description = html.UnescapeString(feed.Channel.Items[i].Description)
+		feed.Channel.Items[i].Author = html.UnescapeString(itemAuthor(feed.Channel.Items[i]))
+		feed.Channel.Items[i].Categories = cleanCategories(feed.Channel.Items[i].Categories)
+		if enclosure := feed.Channel.Items[i].Enclosure; enclosure != nil && enclosure.URL == "" {
+			feed.Channel.Items[i].Enclosure = nil
+		}
+	}
+
+	// Some malformed feeds repeat items within a single response
+	feed.Channel.Items = dedupeItems(feed.Channel.Items)
+
+	if finalURL := resp.Request.URL.String(); finalURL != feedURL {
+		feed.FinalURL = finalURL
+		feed.MovedPermanently = movedPermanently(resp)
 	}
 
-	return &feed, nil
+	return feed, nil
 }
 
-// SavePostsToDatabase saves the posts from an RSS feed to the database
-func SavePostsToDatabase(ctx context.Context, db *database.Queries, feed *RSSFeed, feedID uuid.UUID) error {
-	for _, item := range feed.Channel.Items {
-		// Parse the published date
-		publishedAt, err := parsePubDate(item.PubDate)
+// itemAuthor picks the name to show for item's author: dc:creator, which is
+// usually a plain name, else <author>, which in RSS is an email address often
+// followed by the name in parentheses
+func itemAuthor(item RSSItem) string {
+	if creator := strings.TrimSpace(item.Creator); creator != "" {
+		return creator
+	}
+	author := strings.TrimSpace(item.Author)
+	if open := strings.Index(author, "("); open >= 0 && strings.Contains(author[:open], "@") && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[open+1 : len(author)-1]); name != "" {
+			return name
+		}
+	}
+	return author
+}
+
+// cleanCategories trims and unescapes categories, dropping empty ones and
+// repeats that differ only in case
+func cleanCategories(categories []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		category = strings.TrimSpace(html.UnescapeString(category))
+		key := strings.ToLower(category)
+		if category == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, category)
+	}
+	return cleaned
+}
+
+// isFeedContentType reports whether contentType is one a feed could be
+// served as: any XML or JSON type. A missing Content-Type gets the benefit of
+// the doubt.
+func isFeedContentType(contentType string) bool {
+	if strings.TrimSpace(contentType) == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, suffix := range []string{"/xml", "+xml", "/json", "+json"} {
+		if strings.HasSuffix(mediaType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody reads body to the end, or returns ctx.Err() as soon as ctx is done
+// even if the server has stopped sending without closing the connection.
+// Bodies over maxSize bytes fail with ErrFeedTooLarge.
+func readBody(ctx context.Context, body io.ReadCloser, maxSize int64) ([]byte, error) {
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		data, err := readAtMost(body, maxSize)
+		done <- readResult{data, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return res.data, res.err
+	case <-ctx.Done():
+		// Closing the body unblocks the read so the goroutine can exit
+		body.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// decodeFeed unmarshals an RSS document. If the document as a whole doesn't
+// parse, each <item> is cut out and decoded on its own, so one item with
+// broken markup only loses that item. It returns the feed and the number of
+// items that were skipped.
+func decodeFeed(body []byte) (*RSSFeed, int, error) {
+	var feed RSSFeed
+	err := unmarshalXML(body, &feed)
+	if err == nil {
+		return &feed, 0, nil
+	}
+
+	spans := itemSpans(body)
+	if len(spans) == 0 {
+		return nil, 0, err
+	}
+
+	// Decode the channel without its items, then each item separately
+	var rest []byte
+	prev := 0
+	for _, span := range spans {
+		rest = append(rest, body[prev:span[0]]...)
+		prev = span[1]
+	}
+	rest = append(rest, body[prev:]...)
+	var channelOnly RSSFeed
+	if unmarshalXML(rest, &channelOnly) != nil {
+		return nil, 0, err
+	}
+
+	// Each item keeps the document's declaration so its encoding still applies
+	decl := xmlDeclaration(body)
+	skipped := 0
+	for _, span := range spans {
+		var item RSSItem
+		if unmarshalXML(append(decl[:len(decl):len(decl)], body[span[0]:span[1]]...), &item) != nil {
+			skipped++
+			continue
+		}
+		channelOnly.Channel.Items = append(channelOnly.Channel.Items, item)
+	}
+	if len(channelOnly.Channel.Items) == 0 {
+		return nil, 0, err
+	}
+	return &channelOnly, skipped, nil
+}
+
+// itemSpans returns the [start, end) byte offsets of each <item>...</item>
+// element in body. Items are found textually, so they can be located even
+// when their contents are not well-formed.
+func itemSpans(body []byte) [][2]int {
+	var spans [][2]int
+	openTag, closeTag := []byte("<item"), []byte("</item>")
+	for pos := 0; ; {
+		start := bytes.Index(body[pos:], openTag)
+		if start < 0 {
+			return spans
+		}
+		start += pos
+		// Skip elements that merely start with "item", e.g. <itemCount>
+		if next := start + len(openTag); next < len(body) && body[next] != '>' && body[next] != ' ' && body[next] != '\t' && body[next] != '\r' && body[next] != '\n' {
+			pos = next
+			continue
+		}
+		end := bytes.Index(body[start:], closeTag)
+		if end < 0 {
+			return spans
+		}
+		end += start + len(closeTag)
+		spans = append(spans, [2]int{start, end})
+		pos = end
+	}
+}
+
+// readAtMost reads r to the end, failing with ErrFeedTooLarge rather than
+// reading more than maxSize bytes
+func readAtMost(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrFeedTooLarge, maxSize)
+	}
+	return data, nil
+}
+
+// decodeContent undoes the Content-Encoding of a response body. gzip and
+// deflate are supported; deflate is accepted both zlib-wrapped (as the spec
+// says) and raw (as some servers send it). A body that decompresses to more
+// than maxSize bytes fails with ErrFeedTooLarge.
+func decodeContent(encoding string, body []byte, maxSize int64) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(bytes.NewReader(body))
 		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return readAtMost(reader, maxSize)
+	case "deflate":
+		if reader, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+			defer reader.Close()
+			return readAtMost(reader, maxSize)
+		}
+		reader := flate.NewReader(bytes.NewReader(body))
+		defer reader.Close()
+		return readAtMost(reader, maxSize)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// dedupeItems removes items whose GUID or link was already seen earlier in the
+// slice, keeping the first occurrence and preserving order
+func dedupeItems(items []RSSItem) []RSSItem {
+	seenGUIDs := make(map[string]bool, len(items))
+	seenLinks := make(map[string]bool, len(items))
+	deduped := items[:0]
+	for _, item := range items {
+		guid := strings.TrimSpace(item.GUID)
+		link := strings.TrimSpace(item.Link)
+		if (guid != "" && seenGUIDs[guid]) || (link != "" && seenLinks[link]) {
+			continue
+		}
+		if guid != "" {
+			seenGUIDs[guid] = true
+		}
+		if link != "" {
+			seenLinks[link] = true
+		}
+		deduped = append(deduped, item)
+	}
+	return deduped
+}
+
+// utf8BOM is the UTF-8 encoded byte order mark some feeds prefix their body with
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// trimFeedPrologue strips a leading byte order mark, whitespace, comments, and
+// processing instructions (other than the XML declaration) from a feed body,
+// so it starts at the XML declaration or the root element.
+func trimFeedPrologue(body []byte) []byte {
+	body = bytes.TrimPrefix(body, utf8BOM)
+	for {
+		body = bytes.TrimLeft(body, " \t\r\n")
+		switch {
+		case bytes.HasPrefix(body, []byte("<!--")):
+			end := bytes.Index(body, []byte("-->"))
+			if end < 0 {
+				return body
+			}
+			body = body[end+len("-->"):]
+		case bytes.HasPrefix(body, []byte("<?")) && !isXMLDeclaration(body):
+			end := bytes.Index(body, []byte("?>"))
+			if end < 0 {
+				return body
+			}
+			body = body[end+len("?>"):]
+		default:
+			return body
+		}
+	}
+}
+
+// isXMLDeclaration reports whether body starts with an <?xml ...?> declaration
+// (as opposed to a processing instruction such as <?xml-stylesheet ...?>)
+func isXMLDeclaration(body []byte) bool {
+	const decl = "<?xml"
+	if !bytes.HasPrefix(body, []byte(decl)) || len(body) == len(decl) {
+		return false
+	}
+	switch body[len(decl)] {
+	case ' ', '\t', '\r', '\n', '?':
+		return true
+	}
+	return false
+}
+
+// SaveFeedMetadata stores the channel description of a freshly fetched feed.
+// Unless keepName is set, a non-empty channel title also replaces the feed's
+// name. It returns the updated feed.
+func SaveFeedMetadata(ctx context.Context, db *database.Queries, feed database.Feed, rssFeed *RSSFeed, keepName bool) (database.Feed, error) {
+	name := feed.Name
+	if title := strings.TrimSpace(rssFeed.Channel.Title); title != "" && !keepName {
+		name = title
+	}
+	description := strings.TrimSpace(rssFeed.Channel.Description)
+	return db.UpdateFeedMetadata(ctx, database.UpdateFeedMetadataParams{
+		ID:          feed.ID,
+		Name:        name,
+		Description: sql.NullString{String: description, Valid: description != ""},
+	})
+}
+
+// SavePostsToDatabase saves the posts from an RSS feed to the database and
+// returns the posts that were newly created (posts already stored are skipped)
+func SavePostsToDatabase(ctx context.Context, db *database.Queries, feed *RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+	var created []database.Post
+	fetchedAt := time.Now().UTC()
+	for _, item := range feed.Channel.Items {
+		// Posts without a usable date of their own get an inferred one
+		if _, err := parsePubDate(item.PubDate); err != nil && strings.TrimSpace(item.PubDate) != "" {
 			log.Printf("Warning: Could not parse published date for post '%s': %v", item.Title, err)
-			// Continue with other posts even if one has a bad date
+		}
+		publishedAt, dateSource := postDate(item, feed.Channel, fetchedAt)
+
+		// Podcast episodes sometimes have no page of their own, only the media
+		link := item.Link
+		if strings.TrimSpace(link) == "" && item.Enclosure != nil {
+			link = item.Enclosure.URL
 		}
 
 		// Create the post
-		_, err = db.CreatePost(ctx, database.CreatePostParams{
+		params := database.CreatePostParams{
 			ID:          uuid.New(),
 			CreatedAt:   time.Now().UTC(),
 			UpdatedAt:   time.Now().UTC(),
 			Title:       item.Title,
-			Url:         item.Link,
+			Url:         link,
 			Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
 			PublishedAt: sql.NullTime{Time: publishedAt, Valid: !publishedAt.IsZero()},
+			DateSource:  sql.NullString{String: dateSource, Valid: dateSource != ""},
 			FeedID:      feedID,
-		})
-
+			Author:      sql.NullString{String: item.Author, Valid: item.Author != ""},
+		}
+		if item.Enclosure != nil {
+			params.EnclosureUrl = sql.NullString{String: item.Enclosure.URL, Valid: true}
+			params.EnclosureType = sql.NullString{String: item.Enclosure.Type, Valid: item.Enclosure.Type != ""}
+			params.EnclosureLength = sql.NullInt64{Int64: item.Enclosure.Length, Valid: item.Enclosure.Length > 0}
+		}
+		post, err := db.CreatePost(ctx, params)
 		if err != nil {
-			// Check if it's a unique constraint violation (post already exists)
-			if strings.Contains(err.Error(), "unique constraint") || strings.Contains(err.Error(), "duplicate key") {
+			// ON CONFLICT DO NOTHING returns no row when the post already exists
+			if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "unique constraint") || strings.Contains(err.Error(), "duplicate key") {
 				// Ignore duplicate posts - this is expected
 				continue
 			}
@@ -104,29 +593,74 @@ func SavePostsToDatabase(ctx context.Context, db *database.Queries, feed *RSSFee
 			log.Printf("Error saving post '%s': %v", item.Title, err)
 			continue
 		}
+		for _, category := range item.Categories {
+			if err := db.AddPostCategory(ctx, database.AddPostCategoryParams{PostID: post.ID, Category: category}); err != nil {
+				log.Printf("Error saving category %q of post '%s': %v", category, item.Title, err)
+			}
+		}
+		created = append(created, post)
 	}
 
-	return nil
+	return created, nil
+}
+
+// Where a post's published_at came from, as stored in posts.date_source
+const (
+	// DateSourcePublished is the item's own publication date
+	DateSourcePublished = "published"
+	// DateSourceFeed is the channel's lastBuildDate, for items without a date
+	DateSourceFeed = "feed"
+	// DateSourceFetched is when gator fetched the item, when nothing better exists
+	DateSourceFetched = "fetched"
+)
+
+// postDate picks the date to store for item: its own publication date, else
+// the channel's lastBuildDate, else fetchedAt. It returns the zero time and no
+// source only when none of them is available.
+func postDate(item RSSItem, channel RSSChannel, fetchedAt time.Time) (time.Time, string) {
+	if published, err := parsePubDate(item.PubDate); err == nil {
+		return published, DateSourcePublished
+	}
+	if built, err := parsePubDate(channel.LastBuildDate); err == nil {
+		return built, DateSourceFeed
+	}
+	if !fetchedAt.IsZero() {
+		return fetchedAt, DateSourceFetched
+	}
+	return time.Time{}, ""
+}
+
+// pubDateFormats are the date layouts tried by parsePubDate, in order
+var pubDateFormats = []string{
+	time.RFC1123Z,                    // "Mon, 02 Jan 2006 15:04:05 -0700"
+	time.RFC1123,                     // "Mon, 02 Jan 2006 15:04:05 MST"
+	"Mon, 2 Jan 2006 15:04:05 -0700", // single-digit day
+	"Mon, 2 Jan 2006 15:04:05 MST",   // single-digit day, named zone
+	"2 Jan 2006 15:04:05 -0700",      // no weekday
+	"2 Jan 2006 15:04:05 MST",        // no weekday, named zone
+	time.RFC822Z,                     // "02 Jan 06 15:04 -0700"
+	time.RFC822,                      // "02 Jan 06 15:04 MST"
+	time.RFC3339,                     // "2006-01-02T15:04:05Z07:00"
+	"2006-01-02 15:04:05",            // "2006-01-02 15:04:05"
+	"2006-01-02T15:04:05",            // "2006-01-02T15:04:05"
+	"2006-01-02",                     // "2006-01-02"
 }
 
-// parsePubDate attempts to parse various date formats commonly found in RSS feeds
+// parsePubDate attempts to parse various date formats commonly found in RSS
+// feeds. Runs of whitespace are collapsed first, and a bare number is read as
+// Unix seconds.
 func parsePubDate(pubDate string) (time.Time, error) {
+	pubDate = strings.Join(strings.Fields(pubDate), " ")
 	if pubDate == "" {
 		return time.Time{}, fmt.Errorf("empty published date")
 	}
 
-	// Common RSS date formats
-	formats := []string{
-		time.RFC1123Z,         // "Mon, 02 Jan 2006 15:04:05 -0700"
-		time.RFC1123,          // "Mon, 02 Jan 2006 15:04:05 MST"
-		time.RFC3339,          // "2006-01-02T15:04:05Z07:00"
-		"2006-01-02 15:04:05", // "2006-01-02 15:04:05"
-		"2006-01-02T15:04:05", // "2006-01-02T15:04:05"
-		"2006-01-02",          // "2006-01-02"
+	if seconds, err := strconv.ParseInt(pubDate, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0).UTC(), nil
 	}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, strings.TrimSpace(pubDate)); err == nil {
+	for _, format := range pubDateFormats {
+		if t, err := time.Parse(format, pubDate); err == nil {
 			return t, nil
 		}
 	}
@@ -1,7 +1,18 @@
 package rss
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParsePubDate_CommonFormats(t *testing.T) {
@@ -12,6 +23,14 @@ func TestParsePubDate_CommonFormats(t *testing.T) {
 		"2006-01-02 15:04:05",
 		"2006-01-02T15:04:05",
 		"2006-01-02",
+		"Tue, 5 Mar 2024 09:30:00 +0000",
+		"Tue, 5 Mar 2024 09:30:00 GMT",
+		"05 Mar 2024 09:30:00 -0500",
+		"5 Mar 2024 09:30:00 EST",
+		"05 Mar 24 09:30 -0500",
+		"05 Mar 24 09:30 UTC",
+		"1709631000",
+		"  Tue,  05 Mar 2024\n 09:30:00 +0000 ",
 	}
 
 	for _, s := range samples {
@@ -29,6 +48,44 @@ func TestParsePubDate_Empty(t *testing.T) {
 	}
 }
 
+func TestParsePubDate_UnixSeconds(t *testing.T) {
+	got, err := parsePubDate("1709631000")
+	if err != nil {
+		t.Fatalf("parsePubDate returned error: %v", err)
+	}
+	if want := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parsePubDate = %v; want %v", got, want)
+	}
+}
+
+func TestPostDate_FallsBackForUndatedItems(t *testing.T) {
+	published := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	built := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+	fetched := time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC)
+	channel := RSSChannel{LastBuildDate: built.Format(time.RFC1123Z)}
+
+	tests := []struct {
+		name       string
+		item       RSSItem
+		channel    RSSChannel
+		fetchedAt  time.Time
+		wantTime   time.Time
+		wantSource string
+	}{
+		{"own date", RSSItem{PubDate: published.Format(time.RFC1123Z)}, channel, fetched, published, DateSourcePublished},
+		{"unparseable date uses build date", RSSItem{PubDate: "someday"}, channel, fetched, built, DateSourceFeed},
+		{"no date uses build date", RSSItem{}, channel, fetched, built, DateSourceFeed},
+		{"no build date uses fetch time", RSSItem{}, RSSChannel{}, fetched, fetched, DateSourceFetched},
+		{"nothing to go on", RSSItem{}, RSSChannel{}, time.Time{}, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		got, source := postDate(tt.item, tt.channel, tt.fetchedAt)
+		if !got.Equal(tt.wantTime) || source != tt.wantSource {
+			t.Errorf("%s: postDate = %v, %q; want %v, %q", tt.name, got, source, tt.wantTime, tt.wantSource)
+		}
+	}
+}
+
 func TestNewHTTPClient_HasTimeout(t *testing.T) {
 	c := NewHTTPClient()
 	if c == nil {
@@ -38,3 +95,567 @@ func TestNewHTTPClient_HasTimeout(t *testing.T) {
 		t.Fatalf("expected non-zero Timeout, got 0")
 	}
 }
+
+func TestNewHTTPClient_TransportSettings(t *testing.T) {
+	transport, ok := NewHTTPClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected an *http.Transport")
+	}
+	if transport.MaxIdleConns != defaultMaxIdleConns || transport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost || transport.IdleConnTimeout != defaultIdleConnTimeout {
+		t.Fatalf("unexpected default transport settings: %d %d %v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+
+	c := NewHTTPClient(WithWorkers(200), WithIdleConnTimeout(5*time.Second), WithTimeout(10*time.Second))
+	transport = c.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 200 {
+		t.Fatalf("MaxIdleConnsPerHost = %d; want 200", transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxIdleConns != 400 {
+		t.Fatalf("MaxIdleConns = %d; want 400", transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != 5*time.Second {
+		t.Fatalf("IdleConnTimeout = %v; want 5s", transport.IdleConnTimeout)
+	}
+	if c.Timeout != 10*time.Second {
+		t.Fatalf("Timeout = %v; want 10s", c.Timeout)
+	}
+}
+
+func TestTrimFeedPrologue(t *testing.T) {
+	cases := map[string]string{
+		"\ufeff<?xml version=\"1.0\"?><rss/>":                             "<?xml version=\"1.0\"?><rss/>",
+		"  \n\t<rss/>":                                                    "<rss/>",
+		"<!-- generated --><?xml version=\"1.0\"?><rss/>":                 "<?xml version=\"1.0\"?><rss/>",
+		"<?xml-stylesheet href=\"s.xsl\"?>\n<!-- x -->\n<rss/>":           "<rss/>",
+		"\ufeff <!-- a --> <?php echo 1 ?> <?xml version=\"1.0\"?><rss/>": "<?xml version=\"1.0\"?><rss/>",
+		"<!-- unterminated":                                               "<!-- unterminated",
+	}
+	for input, want := range cases {
+		if got := string(trimFeedPrologue([]byte(input))); got != want {
+			t.Errorf("trimFeedPrologue(%q) = %q; want %q", input, got, want)
+		}
+	}
+}
+
+func TestFetchFeed_BOMPrefixedBody(t *testing.T) {
+	body := "\ufeff\n<!-- feed generated by a CMS -->\n" + `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>BOM Feed</title>
+    <item><title>First</title><link>https://example.com/1</link></item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.Channel.Title != "BOM Feed" {
+		t.Fatalf("expected channel title %q, got %q", "BOM Feed", feed.Channel.Title)
+	}
+	if len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Link != "https://example.com/1" {
+		t.Fatalf("unexpected items: %+v", feed.Channel.Items)
+	}
+}
+
+func TestFetchFeed_StalledBodyStopsAtDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel>`))
+		w.(http.Flusher).Flush()
+		// Keep the connection open without sending the rest of the feed
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := FetchFeed(ctx, NewHTTPClient(WithTimeout(time.Minute)), server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("FetchFeed took %s; want it to stop at the context deadline", elapsed)
+	}
+}
+
+func TestReadBody_ReturnsWhenContextIsCancelled(t *testing.T) {
+	// A pipe that is never closed stands in for a stalled connection
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte("partial"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := readBody(ctx, pr, DefaultMaxFeedSize); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDedupeItems(t *testing.T) {
+	items := []RSSItem{
+		{Title: "a", Link: "https://example.com/a", GUID: "1"},
+		{Title: "b", Link: "https://example.com/b", GUID: "2"},
+		{Title: "a again", Link: "https://example.com/a", GUID: "1"},
+		{Title: "same guid", Link: "https://example.com/c", GUID: "2"},
+		{Title: "same link", Link: " https://example.com/b ", GUID: "3"},
+		{Title: "no guid", Link: "https://example.com/d"},
+	}
+
+	got := dedupeItems(items)
+	want := []string{"a", "b", "no guid"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d items, got %d: %+v", len(want), len(got), got)
+	}
+	for i, title := range want {
+		if got[i].Title != title {
+			t.Fatalf("item %d = %q; want %q", i, got[i].Title, title)
+		}
+	}
+}
+
+func TestFetchFeed_CollapsesRepeatedItems(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Repeats</title>
+    <item><title>Once</title><link>https://example.com/once</link><guid>once</guid></item>
+    <item><title>Once</title><link>https://example.com/once</link><guid>once</guid></item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("expected repeated item to collapse to 1, got %d", len(feed.Channel.Items))
+	}
+}
+
+func TestDecodeFeed_SkipsMalformedItem(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Mostly Fine</title>
+    <item><title>Good one</title><link>https://example.com/1</link></item>
+    <item><title>Broken &bogus; entity</title><description><b>unclosed</description><link>https://example.com/2</link></item>
+    <item><title>Good two</title><link>https://example.com/3</link></item>
+  </channel>
+</rss>`)
+
+	feed, skipped, err := decodeFeed(body)
+	if err != nil {
+		t.Fatalf("decodeFeed returned error: %v", err)
+	}
+	if skipped != 1 {
+		t.Fatalf("expected 1 skipped item, got %d", skipped)
+	}
+	if feed.Channel.Title != "Mostly Fine" {
+		t.Fatalf("expected channel title to survive, got %q", feed.Channel.Title)
+	}
+	if len(feed.Channel.Items) != 2 || feed.Channel.Items[0].Title != "Good one" || feed.Channel.Items[1].Title != "Good two" {
+		t.Fatalf("expected the two good items, got %+v", feed.Channel.Items)
+	}
+}
+
+func TestDecodeFeed_MalformedChannelStillFails(t *testing.T) {
+	if _, _, err := decodeFeed([]byte(`<rss><channel><title>oops</channel></rss>`)); err == nil {
+		t.Fatalf("expected an error for a feed with no items and broken markup")
+	}
+}
+
+func TestFetchFeed_CompressedBody(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Compressed</title>
+    <item><title>Zipped</title><link>https://example.com/z</link></item>
+  </channel>
+</rss>`
+
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+	}
+	for encoding, newWriter := range encoders {
+		var compressed bytes.Buffer
+		zw := newWriter(&compressed)
+		zw.Write([]byte(body))
+		zw.Close()
+
+		var acceptEncoding string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", encoding)
+			w.Write(compressed.Bytes())
+		}))
+
+		feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: FetchFeed returned error: %v", encoding, err)
+		}
+		if !strings.Contains(acceptEncoding, encoding) {
+			t.Fatalf("%s: expected Accept-Encoding to offer it, got %q", encoding, acceptEncoding)
+		}
+		if feed.Channel.Title != "Compressed" || len(feed.Channel.Items) != 1 || feed.Channel.Items[0].Title != "Zipped" {
+			t.Fatalf("%s: unexpected feed: %+v", encoding, feed.Channel)
+		}
+	}
+}
+
+func TestFetchFeedWithOptions_SendsUserAgent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>UA</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	if _, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL); err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if _, err := FetchFeedWithOptions(context.Background(), NewHTTPClient(), server.URL, FetchOptions{UserAgent: "custom-agent/2.0"}); err != nil {
+		t.Fatalf("FetchFeedWithOptions returned error: %v", err)
+	}
+	if len(got) != 2 || got[0] != DefaultUserAgent || got[1] != "custom-agent/2.0" {
+		t.Fatalf("User-Agent headers = %q; want %q then the custom one", got, DefaultUserAgent)
+	}
+}
+
+func TestDiscoverFeedURL_SendsUserAgent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head></html>`))
+	}))
+	defer server.Close()
+
+	for _, opts := range []FetchOptions{{}, {UserAgent: "custom-agent/2.0"}} {
+		if _, err := DiscoverFeedURL(context.Background(), NewHTTPClient(), server.URL, opts); err != nil {
+			t.Fatalf("DiscoverFeedURL returned error: %v", err)
+		}
+	}
+	if len(got) != 2 || got[0] != DefaultUserAgent || got[1] != "custom-agent/2.0" {
+		t.Fatalf("User-Agent headers = %q; want %q then the custom one", got, DefaultUserAgent)
+	}
+}
+
+func TestFetchFeedWithOptions_RejectsOversizedBody(t *testing.T) {
+	body := `<?xml version="1.0"?><rss version="2.0"><channel><title>Big</title>` +
+		strings.Repeat(`<item><title>Padding</title></item>`, 100) + `</channel></rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	_, err := FetchFeedWithOptions(context.Background(), NewHTTPClient(), server.URL, FetchOptions{MaxBodySize: 1024})
+	if !errors.Is(err, ErrFeedTooLarge) || !strings.Contains(err.Error(), "feed too large") {
+		t.Fatalf("expected a feed too large error, got %v", err)
+	}
+
+	// Raising the limit lets the same feed through
+	feed, err := FetchFeedWithOptions(context.Background(), NewHTTPClient(), server.URL, FetchOptions{MaxBodySize: int64(len(body))})
+	if err != nil {
+		t.Fatalf("FetchFeedWithOptions returned error: %v", err)
+	}
+	if feed.Channel.Title != "Big" {
+		t.Fatalf("unexpected feed: %+v", feed.Channel)
+	}
+}
+
+func TestFetchFeedWithOptions_RejectsOversizedDecompressedBody(t *testing.T) {
+	// Compresses to far less than the limit but expands well past it
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>`))
+	zw.Write(bytes.Repeat([]byte("a"), 64*1024))
+	zw.Write([]byte(`</title></channel></rss>`))
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	if compressed.Len() >= 4096 {
+		t.Fatalf("test body compressed to %d bytes; want it under the limit", compressed.Len())
+	}
+	_, err := FetchFeedWithOptions(context.Background(), NewHTTPClient(), server.URL, FetchOptions{MaxBodySize: 4096})
+	if !errors.Is(err, ErrFeedTooLarge) {
+		t.Fatalf("expected ErrFeedTooLarge, got %v", err)
+	}
+}
+
+func TestIsFeedContentType(t *testing.T) {
+	feedTypes := []string{"", "application/rss+xml", "application/atom+xml; charset=utf-8", "text/xml", "application/xml", "application/feed+json", "application/json"}
+	for _, contentType := range feedTypes {
+		if !isFeedContentType(contentType) {
+			t.Errorf("isFeedContentType(%q) = false; want true", contentType)
+		}
+	}
+	for _, contentType := range []string{"text/html; charset=utf-8", "text/plain", "image/png", "not a type"} {
+		if isFeedContentType(contentType) {
+			t.Errorf("isFeedContentType(%q) = true; want false", contentType)
+		}
+	}
+}
+
+func TestFetchFeed_NotFoundReportsStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html><body><h1>Not Found</h1></body></html>"))
+	}))
+	defer server.Close()
+
+	_, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 StatusError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "404 Not Found") {
+		t.Fatalf("expected the status in the error, got %q", err)
+	}
+}
+
+func TestFetchFeed_HTMLPageIsNotAFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!DOCTYPE html><html><head><title>Sign in</title></head><body><form><input name="user"></form></body></html>`))
+	}))
+	defer server.Close()
+
+	_, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err == nil {
+		t.Fatalf("expected an error for an HTML page")
+	}
+	if !strings.Contains(err.Error(), "not a feed") || !strings.Contains(err.Error(), "HTTP 200") || !strings.Contains(err.Error(), "text/html") {
+		t.Fatalf("expected the error to name the status and content type, got %q", err)
+	}
+}
+
+func TestFetchFeed_FollowsRedirectAndReportsFinalURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new.xml", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temp.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/old.xml", http.StatusFound)
+	})
+	mux.HandleFunc("/new.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<rss version="2.0"><channel><title>Moved</title></channel></rss>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL+"/old.xml")
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.Channel.Title != "Moved" {
+		t.Fatalf("expected the redirect target's feed, got title %q", feed.Channel.Title)
+	}
+	if feed.FinalURL != server.URL+"/new.xml" || !feed.MovedPermanently {
+		t.Fatalf("FinalURL = %q, MovedPermanently = %v; want the new URL, moved permanently", feed.FinalURL, feed.MovedPermanently)
+	}
+
+	// A temporary redirect anywhere in the chain means the old URL stays
+	feed, err = FetchFeed(context.Background(), NewHTTPClient(), server.URL+"/temp.xml")
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.FinalURL != server.URL+"/new.xml" || feed.MovedPermanently {
+		t.Fatalf("FinalURL = %q, MovedPermanently = %v; want the new URL, not moved permanently", feed.FinalURL, feed.MovedPermanently)
+	}
+
+	// No redirect, no final URL
+	feed, err = FetchFeed(context.Background(), NewHTTPClient(), server.URL+"/new.xml")
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.FinalURL != "" || feed.MovedPermanently {
+		t.Fatalf("FinalURL = %q, MovedPermanently = %v; want neither set", feed.FinalURL, feed.MovedPermanently)
+	}
+}
+
+func TestFetchFeed_StopsAfterTooManyRedirects(t *testing.T) {
+	hops := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", hops), http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	_, err := FetchFeedWithOptions(context.Background(), NewHTTPClient(), server.URL, FetchOptions{MaxRedirects: 3})
+	if err == nil || !strings.Contains(err.Error(), "stopped after 3 redirects") {
+		t.Fatalf("expected the redirect limit error, got %v", err)
+	}
+	if hops != 4 {
+		t.Fatalf("expected the original request plus 3 redirects, got %d requests", hops)
+	}
+}
+
+func TestFetchFeed_ItemAuthors(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Authors</title>
+    <item><title>Creator</title><link>https://example.com/1</link><dc:creator>Ada Lovelace</dc:creator></item>
+    <item><title>Email with name</title><link>https://example.com/2</link><author>grace@example.com (Grace Hopper)</author></item>
+    <item><title>Plain email</title><link>https://example.com/3</link><author>alan@example.com</author></item>
+    <item><title>Both</title><link>https://example.com/4</link><author>ken@example.com</author><dc:creator>Ken Thompson</dc:creator></item>
+    <item><title>Nobody</title><link>https://example.com/5</link></item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	want := []string{"Ada Lovelace", "Grace Hopper", "alan@example.com", "Ken Thompson", ""}
+	if len(feed.Channel.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(feed.Channel.Items))
+	}
+	for i, item := range feed.Channel.Items {
+		if item.Author != want[i] {
+			t.Errorf("item %q: Author = %q; want %q", item.Title, item.Author, want[i])
+		}
+	}
+}
+
+func TestFetchFeed_ItemCategories(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Categories</title>
+    <item>
+      <title>Tagged</title>
+      <link>https://example.com/1</link>
+      <category>Go</category>
+      <category domain="https://example.com/tags">Databases &amp; SQL</category>
+      <category> go </category>
+      <category></category>
+    </item>
+    <item><title>Untagged</title><link>https://example.com/2</link></item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(feed.Channel.Items))
+	}
+	got := feed.Channel.Items[0].Categories
+	if len(got) != 2 || got[0] != "Go" || got[1] != "Databases & SQL" {
+		t.Fatalf("Categories = %q; want Go and Databases & SQL, without repeats or blanks", got)
+	}
+	if got := feed.Channel.Items[1].Categories; len(got) != 0 {
+		t.Fatalf("expected no categories on the second item, got %q", got)
+	}
+}
+
+func TestFetchFeed_PodcastEnclosure(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Example Podcast</title>
+    <item>
+      <title>Episode 12: Channels</title>
+      <guid isPermaLink="false">ep-12</guid>
+      <pubDate>Mon, 02 Sep 2024 08:00:00 +0000</pubDate>
+      <enclosure url="https://cdn.example.com/ep12.mp3" type="audio/mpeg" length="34216300"/>
+      <itunes:duration>00:35:12</itunes:duration>
+    </item>
+    <item>
+      <title>Episode 11: Bad length</title>
+      <enclosure url="https://cdn.example.com/ep11.mp3" type="audio/mpeg" length="unknown"/>
+    </item>
+    <item><title>Show notes only</title><link>https://example.com/notes</link></item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if len(feed.Channel.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(feed.Channel.Items))
+	}
+	want := Enclosure{URL: "https://cdn.example.com/ep12.mp3", Type: "audio/mpeg", Length: 34216300}
+	if got := feed.Channel.Items[0].Enclosure; got == nil || *got != want {
+		t.Fatalf("Enclosure = %+v; want %+v", got, want)
+	}
+	// A malformed length is dropped without losing the episode
+	want = Enclosure{URL: "https://cdn.example.com/ep11.mp3", Type: "audio/mpeg"}
+	if got := feed.Channel.Items[1].Enclosure; got == nil || *got != want {
+		t.Fatalf("Enclosure = %+v; want %+v", got, want)
+	}
+	if got := feed.Channel.Items[2].Enclosure; got != nil {
+		t.Fatalf("expected no enclosure on a plain item, got %+v", got)
+	}
+}
+
+func TestFetchFeed_SendsBasicAuthFromContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Private</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	if _, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL); err == nil {
+		t.Fatalf("expected an error fetching without credentials")
+	}
+
+	ctx := WithBasicAuth(context.Background(), BasicAuth{Username: "alice", Password: "s3cret"})
+	feed, err := FetchFeed(ctx, NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("FetchFeed returned error: %v", err)
+	}
+	if feed.Channel.Title != "Private" {
+		t.Fatalf("unexpected feed: %+v", feed.Channel)
+	}
+}
@@ -0,0 +1,54 @@
+package rss
+
+import (
+	"context"
+	"sync"
+)
+
+// HostLimiter caps how many requests run against a single host at once, so
+// many feeds on one host (e.g. a blogging platform) don't hit it all together
+// while feeds on other hosts carry on.
+type HostLimiter struct {
+	limit int
+
+	mu    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// NewHostLimiter creates a limiter allowing limit concurrent requests per
+// host. A limit below 1 is treated as 1.
+func NewHostLimiter(limit int) *HostLimiter {
+	return &HostLimiter{
+		limit: max(limit, 1),
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+// slots returns the semaphore for host, creating it on first use
+func (l *HostLimiter) slots(host string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.hosts[host] = slots
+	}
+	return slots
+}
+
+// Acquire waits until a request to host may start, or until ctx is done.
+// Every successful Acquire must be followed by a Release for the same host.
+func (l *HostLimiter) Acquire(ctx context.Context, host string) error {
+	select {
+	case l.slots(host) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees the slot taken by Acquire
+func (l *HostLimiter) Release(host string) {
+	<-l.slots(host)
+}
@@ -0,0 +1,36 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter_LimitsEachHostSeparately(t *testing.T) {
+	l := NewHostLimiter(2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.Acquire(ctx, "busy.example.com"); err != nil {
+			t.Fatalf("Acquire %d returned error: %v", i+1, err)
+		}
+	}
+
+	// The third request to the same host has to wait
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := l.Acquire(waitCtx, "busy.example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a third Acquire to wait until the deadline, got %v", err)
+	}
+
+	// Other hosts are unaffected
+	if err := l.Acquire(ctx, "quiet.example.com"); err != nil {
+		t.Fatalf("Acquire for another host returned error: %v", err)
+	}
+
+	l.Release("busy.example.com")
+	if err := l.Acquire(ctx, "busy.example.com"); err != nil {
+		t.Fatalf("expected a released slot to be reusable, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+)
+
+// jsonFeed represents the parts of a JSON Feed (https://jsonfeed.org) document
+// gator uses
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+// jsonFeedItem represents a single entry in a JSON Feed's items array
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentHTML   string `json:"content_html"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+	// Authors replaced the single Author in JSON Feed 1.1
+	Authors []jsonFeedAuthor `json:"authors"`
+	Author  *jsonFeedAuthor  `json:"author"`
+	Tags    []string         `json:"tags"`
+	// Attachments are media files; the first becomes the item's enclosure
+	Attachments []jsonFeedAttachment `json:"attachments"`
+}
+
+// jsonFeedAttachment is a media file attached to a JSON Feed item
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// jsonFeedAuthor is an item author in a JSON Feed
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// isJSONFeed reports whether a response is a JSON Feed, going by its
+// Content-Type header or, when that's missing or generic, by the body starting
+// with a JSON object
+func isJSONFeed(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/feed+json", "application/json":
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
+
+// decodeJSONFeed unmarshals a JSON Feed document into the same RSSFeed shape
+// used for RSS 2.0
+func decodeJSONFeed(body []byte) (*RSSFeed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+
+	feed := &RSSFeed{Channel: RSSChannel{
+		Title:       doc.Title,
+		Link:        doc.HomePageURL,
+		Description: doc.Description,
+	}}
+	for _, item := range doc.Items {
+		description := item.ContentHTML
+		if strings.TrimSpace(description) == "" {
+			description = item.ContentText
+		}
+		feed.Channel.Items = append(feed.Channel.Items, RSSItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: description,
+			PubDate:     item.DatePublished,
+			GUID:        item.ID,
+			Author:      item.authorName(),
+			Categories:  item.Tags,
+			Enclosure:   item.enclosure(),
+		})
+	}
+	return feed, nil
+}
+
+// enclosure is the item's first attachment as an Enclosure, if any
+func (item jsonFeedItem) enclosure() *Enclosure {
+	if len(item.Attachments) == 0 || item.Attachments[0].URL == "" {
+		return nil
+	}
+	attachment := item.Attachments[0]
+	return &Enclosure{URL: attachment.URL, Type: attachment.MimeType, Length: attachment.SizeInBytes}
+}
+
+// authorName is the name of the item's first author, if any
+func (item jsonFeedItem) authorName() string {
+	if len(item.Authors) > 0 {
+		return item.Authors[0].Name
+	}
+	if item.Author != nil {
+		return item.Author.Name
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package rss
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const sampleJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON Blog",
+  "home_page_url": "https://example.org/",
+  "description": "Posts served as JSON",
+  "items": [
+    {
+      "id": "1",
+      "url": "https://example.org/one",
+      "title": "One",
+      "content_html": "<p>Hello</p>",
+      "date_published": "2024-05-01T12:00:00Z",
+      "authors": [{"name": "Sam"}],
+      "tags": ["news", "json"]
+    },
+    {
+      "id": "2",
+      "url": "https://example.org/two",
+      "title": "Two",
+      "content_text": "Plain text"
+    }
+  ]
+}`
+
+func TestIsJSONFeed(t *testing.T) {
+	cases := []struct {
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"application/feed+json", sampleJSONFeed, true},
+		{"application/json; charset=utf-8", sampleJSONFeed, true},
+		{"text/plain", sampleJSONFeed, true},
+		{"", "<rss/>", false},
+		{"application/atom+xml", sampleAtom, false},
+	}
+	for _, tc := range cases {
+		if got := isJSONFeed(tc.contentType, []byte(tc.body)); got != tc.want {
+			t.Errorf("isJSONFeed(%q, ...) = %v; want %v", tc.contentType, got, tc.want)
+		}
+	}
+}
+
+func TestFetchFeed_JSONFeed(t *testing.T) {
+	for _, contentType := range []string{"application/json", "text/plain"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Write([]byte(sampleJSONFeed))
+		}))
+
+		feed, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: FetchFeed returned error: %v", contentType, err)
+		}
+		if feed.Channel.Title != "JSON Blog" || feed.Channel.Description != "Posts served as JSON" {
+			t.Fatalf("%s: expected channel title and description from the top level, got %+v", contentType, feed.Channel)
+		}
+
+		want := []RSSItem{
+			{Title: "One", Link: "https://example.org/one", Description: "<p>Hello</p>", PubDate: "2024-05-01T12:00:00Z", GUID: "1", Author: "Sam", Categories: []string{"news", "json"}},
+			{Title: "Two", Link: "https://example.org/two", Description: "Plain text", GUID: "2"},
+		}
+		if len(feed.Channel.Items) != len(want) {
+			t.Fatalf("%s: expected %d items, got %+v", contentType, len(want), feed.Channel.Items)
+		}
+		for i, item := range want {
+			if !reflect.DeepEqual(feed.Channel.Items[i], item) {
+				t.Errorf("%s: item %d = %+v; want %+v", contentType, i, feed.Channel.Items[i], item)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package rss
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gator/internal/database"
+)
+
+// trackingParams are query parameters that identify a referral rather than
+// the resource itself, so they are dropped when normalizing feed URLs
+var trackingParams = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+	"mc_cid": true,
+	"mc_eid": true,
+}
+
+// NormalizeURL canonicalizes a feed URL so that trivially different spellings of
+// the same feed compare equal: it lowercases the scheme and host, strips default
+// ports, trailing slashes, fragments, and tracking query parameters (utm_* etc.).
+// The remaining query parameters are sorted by name but otherwise kept as
+// written, as is the path's encoding, so that migration 022 can compute the
+// same URL in SQL. URLs that can't be parsed as absolute URLs are returned
+// trimmed but otherwise unchanged.
+func NormalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+
+	u.RawPath = strings.TrimRight(u.EscapedPath(), "/")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.ForceQuery = false
+
+	if u.RawQuery != "" {
+		var params []string
+		for _, param := range strings.Split(u.RawQuery, "&") {
+			key := strings.ToLower(queryParamName(param))
+			if param == "" || strings.HasPrefix(key, "utm_") || trackingParams[key] {
+				continue
+			}
+			params = append(params, param)
+		}
+		slices.SortStableFunc(params, func(a, b string) int {
+			return strings.Compare(queryParamName(a), queryParamName(b))
+		})
+		u.RawQuery = strings.Join(params, "&")
+	}
+
+	return u.String()
+}
+
+// queryParamName returns the still-encoded name of a "name=value" query parameter
+func queryParamName(param string) string {
+	name, _, _ := strings.Cut(param, "=")
+	return name
+}
+
+// urlVariants returns the URLs a feed at rawURL may be stored under: as given,
+// normalized, and normalized with the other of http and https
+func urlVariants(rawURL string) []string {
+	rawURL = strings.TrimSpace(rawURL)
+	normalized := NormalizeURL(rawURL)
+	variants := []string{rawURL}
+	if normalized != rawURL {
+		variants = append(variants, normalized)
+	}
+	if rest, ok := strings.CutPrefix(normalized, "http://"); ok {
+		variants = append(variants, "https://"+rest)
+	} else if rest, ok := strings.CutPrefix(normalized, "https://"); ok {
+		variants = append(variants, "http://"+rest)
+	}
+	return variants
+}
+
+// LookupFeed returns the feed stored under rawURL or any URL that normalizes
+// the same, including over the other of http and https. It returns
+// sql.ErrNoRows when there is none.
+func LookupFeed(ctx context.Context, db *database.Queries, rawURL string) (database.Feed, error) {
+	for _, u := range urlVariants(rawURL) {
+		feed, err := db.GetFeedByURL(ctx, u)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return feed, err
+		}
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
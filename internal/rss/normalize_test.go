@@ -0,0 +1,52 @@
+package rss
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/feed.xml", "https://example.com/feed.xml"},
+		{"  https://example.com/feed.xml  ", "https://example.com/feed.xml"},
+		{"HTTPS://Example.COM/Feed.xml", "https://example.com/Feed.xml"},
+		{"https://example.com:443/feed", "https://example.com/feed"},
+		{"http://example.com:80/feed", "http://example.com/feed"},
+		{"https://example.com:8443/feed", "https://example.com:8443/feed"},
+		{"https://example.com/feed/", "https://example.com/feed"},
+		{"https://example.com/", "https://example.com"},
+		{"https://example.com/feed#latest", "https://example.com/feed"},
+		{"https://example.com/feed?utm_source=rss&utm_medium=feed", "https://example.com/feed"},
+		{"https://example.com/feed?fbclid=abc&format=atom", "https://example.com/feed?format=atom"},
+		{"https://example.com/feed?b=2&a=1", "https://example.com/feed?a=1&b=2"},
+		{"https://example.com/feed?tag=b&a-b=1&tag=a&a=2", "https://example.com/feed?a=2&a-b=1&tag=b&tag=a"},
+		{"https://example.com/feed?tags=go,rss&q=a+b&&flag", "https://example.com/feed?flag&q=a+b&tags=go,rss"},
+		{"https://example.com/feed?", "https://example.com/feed"},
+		{"https://example.com/~me/feed%2Exml/", "https://example.com/~me/feed%2Exml"},
+		{"not a url", "not a url"},
+		{"/relative/feed", "/relative/feed"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeURL(tt.in); got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestURLVariants(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"https://example.com/feed", []string{"https://example.com/feed", "http://example.com/feed"}},
+		{"http://Example.com/feed/?utm_source=x", []string{"http://Example.com/feed/?utm_source=x", "http://example.com/feed", "https://example.com/feed"}},
+		{"not a url", []string{"not a url"}},
+	}
+	for _, tt := range tests {
+		if got := urlVariants(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("urlVariants(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
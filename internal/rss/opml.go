@@ -0,0 +1,150 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultMaxListSize is the largest subscription list FetchSubscriptions
+// reads, in bytes
+const DefaultMaxListSize = 5 << 20
+
+// Subscription is one feed listed in an OPML or JSON subscription list
+type Subscription struct {
+	Title string
+	URL   string
+}
+
+// opmlOutline is an OPML <outline>; categories nest feeds inside outlines
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+// ParseOPML returns the feeds listed in an OPML document, including those
+// nested in category outlines. Outlines without an xmlUrl are not feeds and
+// are left out.
+func ParseOPML(data []byte) ([]Subscription, error) {
+	var doc opmlDocument
+	if err := unmarshalXML(trimFeedPrologue(data), &doc); err != nil {
+		return nil, fmt.Errorf("couldn't parse OPML: %w", err)
+	}
+
+	var subscriptions []Subscription
+	var walk func(outlines []opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, outline := range outlines {
+			if feedURL := strings.TrimSpace(outline.XMLURL); feedURL != "" {
+				title := strings.TrimSpace(outline.Title)
+				if title == "" {
+					title = strings.TrimSpace(outline.Text)
+				}
+				subscriptions = append(subscriptions, Subscription{Title: title, URL: feedURL})
+			}
+			walk(outline.Outlines)
+		}
+	}
+	walk(doc.Body)
+	return subscriptions, nil
+}
+
+// parseSubscriptionsJSON returns the feeds in a JSON list shaped like the
+// output of `gator feeds --json`: an object with a "feeds" array of objects
+// carrying "url" and optionally "name"
+func parseSubscriptionsJSON(data []byte) ([]Subscription, error) {
+	var doc struct {
+		Feeds []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"feeds"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("couldn't parse JSON subscription list: %w", err)
+	}
+
+	var subscriptions []Subscription
+	for _, feed := range doc.Feeds {
+		if feedURL := strings.TrimSpace(feed.URL); feedURL != "" {
+			subscriptions = append(subscriptions, Subscription{Title: strings.TrimSpace(feed.Name), URL: feedURL})
+		}
+	}
+	return subscriptions, nil
+}
+
+// isListContentType reports whether contentType is one a subscription list
+// could be served as: XML (including OPML's own types) or JSON. A missing
+// Content-Type gets the benefit of the doubt.
+func isListContentType(contentType string) bool {
+	if isFeedContentType(contentType) {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/x-opml" || mediaType == "application/x-opml"
+}
+
+// FetchSubscriptions downloads the OPML or JSON subscription list at listURL,
+// such as another reader's export endpoint, and returns the feeds it lists.
+// Lists over DefaultMaxListSize bytes fail with ErrFeedTooLarge.
+func FetchSubscriptions(ctx context.Context, client *http.Client, listURL string, opts FetchOptions) ([]Subscription, error) {
+	if client.Timeout == 0 {
+		return nil, fmt.Errorf("HTTP client must have a timeout configured")
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	maxSize := opts.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxListSize
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	setBasicAuth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newStatusError(resp, time.Now())
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !isListContentType(contentType) {
+		return nil, fmt.Errorf("not a subscription list: served as %q, not OPML or JSON", contentType)
+	}
+
+	body, err := readBody(ctx, resp.Body, maxSize)
+	if errors.Is(err, ErrFeedTooLarge) {
+		return nil, fmt.Errorf("subscription list is over %d bytes: %w", maxSize, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasSuffix(mediaType, "json") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return parseSubscriptionsJSON(body)
+	}
+	return ParseOPML(body)
+}
@@ -0,0 +1,78 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>My subscriptions</title></head>
+  <body>
+    <outline text="Tech" title="Tech">
+      <outline type="rss" text="Go Blog" title="The Go Blog" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+      <outline type="rss" text="Ars" xmlUrl=" https://feeds.arstechnica.com/arstechnica/index "/>
+    </outline>
+    <outline type="rss" text="Top level" xmlUrl="https://example.com/feed.xml"/>
+    <outline text="Just a heading"/>
+  </body>
+</opml>`
+
+func TestParseOPML_NestedOutlines(t *testing.T) {
+	got, err := ParseOPML([]byte(testOPML))
+	if err != nil {
+		t.Fatalf("ParseOPML returned error: %v", err)
+	}
+	want := []Subscription{
+		{Title: "The Go Blog", URL: "https://go.dev/blog/feed.atom"},
+		{Title: "Ars", URL: "https://feeds.arstechnica.com/arstechnica/index"},
+		{Title: "Top level", URL: "https://example.com/feed.xml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseOPML = %+v; want %+v", got, want)
+	}
+}
+
+func TestFetchSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feeds.opml":
+			w.Header().Set("Content-Type", "text/x-opml")
+			w.Write([]byte(testOPML))
+		case "/feeds.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"schema_version": 1, "feeds": [{"name": "Blog", "url": "https://blog.example.com/feed"}, {"name": "No URL"}]}`))
+		case "/login":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Please sign in</html>"))
+		case "/huge.opml":
+			w.Header().Set("Content-Type", "text/xml")
+			w.Write([]byte(testOPML + strings.Repeat("<!-- padding -->", 100)))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	subs, err := FetchSubscriptions(ctx, NewHTTPClient(), server.URL+"/feeds.opml", FetchOptions{})
+	if err != nil || len(subs) != 3 {
+		t.Fatalf("OPML list: got %+v, %v; want 3 feeds", subs, err)
+	}
+
+	subs, err = FetchSubscriptions(ctx, NewHTTPClient(), server.URL+"/feeds.json", FetchOptions{})
+	if err != nil || !reflect.DeepEqual(subs, []Subscription{{Title: "Blog", URL: "https://blog.example.com/feed"}}) {
+		t.Fatalf("JSON list: got %+v, %v", subs, err)
+	}
+
+	if _, err := FetchSubscriptions(ctx, NewHTTPClient(), server.URL+"/login", FetchOptions{}); err == nil || !strings.Contains(err.Error(), "not a subscription list") {
+		t.Fatalf("expected an HTML page to be rejected, got %v", err)
+	}
+
+	if _, err := FetchSubscriptions(ctx, NewHTTPClient(), server.URL+"/huge.opml", FetchOptions{MaxBodySize: int64(len(testOPML))}); !errors.Is(err, ErrFeedTooLarge) {
+		t.Fatalf("expected an oversized list to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FetchFunc fetches and parses the feed at url; FetchFeed is the usual one
+type FetchFunc func(ctx context.Context, client *http.Client, url string) (*RSSFeed, error)
+
+// Backoff between retries starts at retryBaseDelay, doubles on each attempt,
+// and is capped at retryMaxDelay. Each wait is jittered to between half and
+// all of that so feeds on the same host don't retry in lockstep.
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// StatusError is returned by FetchFeed when the server answers with a
+// non-2xx status. RetryAfter is the delay the server asked for in a
+// Retry-After header on a 429 or 503 response, or zero.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("unexpected status %d %s (retry after %s)", e.StatusCode, http.StatusText(e.StatusCode), e.RetryAfter)
+	}
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// newStatusError builds the StatusError for resp, reading Retry-After when
+// the status is one that uses it
+func newStatusError(resp *http.Response, now time.Time) *StatusError {
+	err := &StatusError{StatusCode: resp.StatusCode}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		err.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	}
+	return err
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date. A date in the past means no wait.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return max(when.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// WithRetry wraps fetch so that network errors, 429s, and 5xx responses are
+// retried up to retries more times with exponential backoff, or after the
+// server's Retry-After delay when it gives one. It stops early, returning the
+// last error, when ctx is done or its deadline would pass before the next
+// attempt; a *StatusError's RetryAfter then tells the caller when to try again.
+func WithRetry(fetch FetchFunc, retries int) FetchFunc {
+	return func(ctx context.Context, client *http.Client, url string) (*RSSFeed, error) {
+		feed, err := fetch(ctx, client, url)
+		for attempt := 0; attempt < retries && err != nil && isRetryable(ctx, err); attempt++ {
+			delay := backoff(attempt)
+			// A server that says when to come back is taken at its word
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+				delay = statusErr.RetryAfter
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				return nil, err
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, err
+			case <-timer.C:
+			}
+			feed, err = fetch(ctx, client, url)
+		}
+		return feed, err
+	}
+}
+
+// backoff returns the jittered wait before retry number attempt (from 0)
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable reports whether err looks transient: a 429 or 5xx status, or a
+// network failure that wasn't caused by ctx ending
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,183 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries shrinks the backoff for the duration of a test
+func fastRetries(t *testing.T) {
+	base, maxDelay := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, maxDelay })
+}
+
+func TestWithRetry_RecoversAfterTransientFailures(t *testing.T) {
+	fastRetries(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<rss version="2.0"><channel><title>Back</title><item><title>One</title><link>https://example.com/1</link></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	feed, err := WithRetry(FetchFeed, 2)(context.Background(), NewHTTPClient(), server.URL)
+	if err != nil {
+		t.Fatalf("expected success on the third attempt, got %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests.Load())
+	}
+	if feed.Channel.Title != "Back" || len(feed.Channel.Items) != 1 {
+		t.Fatalf("unexpected feed: %+v", feed.Channel)
+	}
+}
+
+func TestWithRetry_GivesUpAfterRetries(t *testing.T) {
+	fastRetries(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := WithRetry(FetchFeed, 2)(context.Background(), NewHTTPClient(), server.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 StatusError, got %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("expected 1 attempt plus 2 retries, got %d requests", requests.Load())
+	}
+}
+
+func TestWithRetry_DoesNotRetryClientErrors(t *testing.T) {
+	fastRetries(t)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := WithRetry(FetchFeed, 3)(context.Background(), NewHTTPClient(), server.URL); err == nil {
+		t.Fatalf("expected an error for a 404")
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("expected a 404 not to be retried, got %d requests", requests.Load())
+	}
+}
+
+func TestWithRetry_StopsAtContextDeadline(t *testing.T) {
+	// Backoff longer than the deadline means no retry is attempted
+	calls := 0
+	fetch := func(ctx context.Context, client *http.Client, url string) (*RSSFeed, error) {
+		calls++
+		return nil, &StatusError{StatusCode: http.StatusServiceUnavailable}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := WithRetry(fetch, 5)(ctx, nil, "https://example.com/feed"); err == nil {
+		t.Fatalf("expected the last error to be returned")
+	}
+	if calls != 1 || time.Since(start) > time.Second {
+		t.Fatalf("expected to give up immediately, got %d calls after %s", calls, time.Since(start))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Sat, 01 Jun 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Sat, 01 Jun 2024 11:59:00 GMT", 0, true},
+		{"", 0, false},
+		{"-5", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestFetchFeed_TooManyRequestsCarriesRetryAfter(t *testing.T) {
+	retryAt := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	for header, atLeast := range map[string]time.Duration{"30": 30 * time.Second, retryAt: 80 * time.Second} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", header)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		_, err := FetchFeed(context.Background(), NewHTTPClient(), server.URL)
+		server.Close()
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Retry-After %q: expected a 429 StatusError, got %v", header, err)
+		}
+		if statusErr.RetryAfter < atLeast || statusErr.RetryAfter > atLeast+15*time.Second {
+			t.Fatalf("Retry-After %q: got delay %s, want about %s", header, statusErr.RetryAfter, atLeast)
+		}
+	}
+}
+
+func TestWithRetry_WaitsForRetryAfter(t *testing.T) {
+	fastRetries(t)
+
+	calls := 0
+	fetch := func(ctx context.Context, client *http.Client, url string) (*RSSFeed, error) {
+		calls++
+		if calls == 1 {
+			return nil, &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond}
+		}
+		return &RSSFeed{}, nil
+	}
+
+	start := time.Now()
+	if _, err := WithRetry(fetch, 1)(context.Background(), nil, "https://example.com/feed"); err != nil {
+		t.Fatalf("expected success after waiting, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected to wait out Retry-After, retried after %s", elapsed)
+	}
+}
+
+func TestWithRetry_RetryAfterBeyondDeadlineReturnsDelay(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, client *http.Client, url string) (*RSSFeed, error) {
+		calls++
+		return nil, &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := WithRetry(fetch, 3)(ctx, nil, "https://example.com/feed")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.RetryAfter != time.Hour {
+		t.Fatalf("expected the Retry-After delay to be surfaced, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retry past the deadline, got %d calls", calls)
+	}
+}
@@ -1,8 +1,21 @@
 package rss
 
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
 // RSSFeed represents the structure of an RSS feed
 type RSSFeed struct {
 	Channel RSSChannel `xml:"channel"`
+
+	// FinalURL is where the feed was fetched from after following redirects,
+	// or empty when it was served from the requested URL
+	FinalURL string `xml:"-"`
+	// MovedPermanently is set when every redirect to FinalURL was permanent,
+	// so FinalURL should replace the requested URL
+	MovedPermanently bool `xml:"-"`
 }
 
 // RSSChannel represents the channel information in an RSS feed
@@ -22,4 +35,38 @@ type RSSItem struct {
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
 	GUID        string `xml:"guid"`
+	// Author is the item's author. FetchFeed fills it from Creator when set,
+	// since <author> is meant to hold an email address.
+	Author string `xml:"author"`
+	// Creator is the Dublin Core <dc:creator> element
+	Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	// Categories holds the item's <category> elements
+	Categories []string `xml:"category"`
+	// Enclosure is the attached media file, such as a podcast episode, if any
+	Enclosure *Enclosure `xml:"enclosure"`
+}
+
+// Enclosure is a media file attached to an item
+type Enclosure struct {
+	URL  string
+	Type string
+	// Length is the size in bytes, or 0 when unknown
+	Length int64
+}
+
+// UnmarshalXML reads an <enclosure>'s attributes. A malformed length is
+// treated as unknown rather than failing the whole item, as feeds often get
+// it wrong.
+func (e *Enclosure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "url":
+			e.URL = strings.TrimSpace(attr.Value)
+		case "type":
+			e.Type = strings.TrimSpace(attr.Value)
+		case "length":
+			e.Length, _ = strconv.ParseInt(strings.TrimSpace(attr.Value), 10, 64)
+		}
+	}
+	return d.Skip()
 }
@@ -0,0 +1,106 @@
+// Package search looks up a user's posts by a search term, ranking matches by
+// relevance when full-text search finds the term.
+package search
+
+import (
+	"context"
+	"database/sql"
+	"gator/internal/database"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// minFullTextLength is the shortest term sent to full-text search. Shorter
+// terms are usually word fragments, which only a substring match finds.
+const minFullTextLength = 3
+
+// useFullText reports whether query is long enough for full-text search
+func useFullText(query string) bool {
+	return utf8.RuneCountInString(query) >= minFullTextLength
+}
+
+// countFullText returns how many of the user's posts full-text search matches
+// for query, or 0 when query is too short for it. Stop words such as "the",
+// word prefixes such as "kube", and fragments of URLs or code match nothing,
+// so a search falls back to a substring match when it returns 0.
+func countFullText(ctx context.Context, db *database.Queries, userID uuid.UUID, query string) (int64, error) {
+	if !useFullText(query) {
+		return 0, nil
+	}
+	return db.CountSearchPostsFTS(ctx, database.CountSearchPostsFTSParams{
+		UserID: userID,
+		Query:  query,
+	})
+}
+
+// Posts returns one page of the user's posts matching query, most relevant
+// first. Terms full-text search finds nothing for fall back to a substring
+// match, newest first. A page of full-text matches takes a single query;
+// only an empty page makes it check whether to fall back.
+func Posts(ctx context.Context, db *database.Queries, userID uuid.UUID, query string, limit, offset int32) ([]database.SearchPostsForUserRow, error) {
+	if useFullText(query) {
+		posts, err := fullTextPosts(ctx, db, userID, query, limit, offset)
+		if err != nil || len(posts) > 0 {
+			return posts, err
+		}
+		// Past the first page, an empty page may just be past the last match
+		if offset > 0 {
+			matches, err := countFullText(ctx, db, userID, query)
+			if err != nil || matches > 0 {
+				return nil, err
+			}
+		}
+	}
+
+	return db.SearchPostsForUser(ctx, database.SearchPostsForUserParams{
+		UserID:  userID,
+		Column2: sql.NullString{String: query, Valid: true},
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// fullTextPosts returns one page of the user's posts full-text search matches
+// for query, most relevant first
+func fullTextPosts(ctx context.Context, db *database.Queries, userID uuid.UUID, query string, limit, offset int32) ([]database.SearchPostsForUserRow, error) {
+	rows, err := db.SearchPostsFTS(ctx, database.SearchPostsFTSParams{
+		UserID: userID,
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.SearchPostsForUserRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.SearchPostsForUserRow{
+			ID:           row.ID,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+			Title:        row.Title,
+			Url:          row.Url,
+			Description:  row.Description,
+			PublishedAt:  row.PublishedAt,
+			FeedID:       row.FeedID,
+			Author:       row.Author,
+			EnclosureUrl: row.EnclosureUrl,
+			DateSource:   row.DateSource,
+			FeedName:     row.FeedName,
+		}
+	}
+	return posts, nil
+}
+
+// Count returns how many of the user's posts Posts would match in total
+func Count(ctx context.Context, db *database.Queries, userID uuid.UUID, query string) (int64, error) {
+	matches, err := countFullText(ctx, db, userID, query)
+	if err != nil || matches > 0 {
+		return matches, err
+	}
+	return db.CountSearchPostsForUser(ctx, database.CountSearchPostsForUserParams{
+		UserID:  userID,
+		Column2: sql.NullString{String: query, Valid: true},
+	})
+}
@@ -0,0 +1,236 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+func TestUseFullText(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"go", false},
+		{"né", false},
+		{"git", true},
+		{"generics", true},
+	}
+	for _, tt := range tests {
+		if got := useFullText(tt.query); got != tt.want {
+			t.Errorf("useFullText(%q) = %v; want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// seedPosts creates a user following one feed holding posts, returning the
+// user's ID. Posts are published a day apart, the first one newest.
+func seedPosts(t *testing.T, db *database.Queries, posts [][2]string) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	user, err := db.CreateUser(ctx, database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      "searcher",
+	})
+	if err != nil {
+		t.Fatalf("couldn't create user: %v", err)
+	}
+	feed, err := db.CreateFeed(ctx, database.CreateFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      "Blog",
+		Url:       "https://example.com/feed.xml",
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed: %v", err)
+	}
+	if _, err := db.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	}); err != nil {
+		t.Fatalf("couldn't follow feed: %v", err)
+	}
+
+	for i, post := range posts {
+		if _, err := db.CreatePost(ctx, database.CreatePostParams{
+			ID:          uuid.New(),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Title:       post[0],
+			Url:         "https://example.com/" + uuid.NewString(),
+			Description: sql.NullString{String: post[1], Valid: post[1] != ""},
+			PublishedAt: sql.NullTime{Time: now.Add(-time.Duration(i) * 24 * time.Hour), Valid: true},
+			FeedID:      feed.ID,
+		}); err != nil {
+			t.Fatalf("couldn't create post %q: %v", post[0], err)
+		}
+	}
+	return user.ID
+}
+
+func titles(posts []database.SearchPostsForUserRow) []string {
+	out := make([]string, len(posts))
+	for i, post := range posts {
+		out[i] = post.Title
+	}
+	return out
+}
+
+func TestPosts_RankedByRelevance(t *testing.T) {
+	db := database.New(dbtest.Open(t))
+	userID := seedPosts(t, db, [][2]string{
+		{"Weekly roundup", "Links, including one on generics"},
+		{"Rust release notes", "Nothing relevant here"},
+		{"Generics in practice", "Writing generic containers with generics"},
+	})
+
+	posts, err := Posts(context.Background(), db, userID, "generics", 10, 0)
+	if err != nil {
+		t.Fatalf("Posts: %v", err)
+	}
+	got := titles(posts)
+	if len(got) != 2 || got[0] != "Generics in practice" || got[1] != "Weekly roundup" {
+		t.Fatalf("titles = %q; want the title match ranked above the newer description match", got)
+	}
+
+	count, err := Count(context.Background(), db, userID, "generics")
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count = %d; want 2", count)
+	}
+}
+
+func TestPosts_ShortTermFallsBackToSubstringMatch(t *testing.T) {
+	db := database.New(dbtest.Open(t))
+	userID := seedPosts(t, db, [][2]string{
+		{"Rust release notes", ""},
+		{"Trusting trust", ""},
+		{"Go generics", ""},
+	})
+
+	// "ru" is no word, so only a substring match finds anything
+	posts, err := Posts(context.Background(), db, userID, "ru", 10, 0)
+	if err != nil {
+		t.Fatalf("Posts: %v", err)
+	}
+	got := titles(posts)
+	if len(got) != 2 || got[0] != "Rust release notes" || got[1] != "Trusting trust" {
+		t.Fatalf("titles = %q; want substring matches, newest first", got)
+	}
+
+	count, err := Count(context.Background(), db, userID, "ru")
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count = %d; want 2", count)
+	}
+}
+
+func TestPosts_FallsBackWhenFullTextFindsNothing(t *testing.T) {
+	db := database.New(dbtest.Open(t))
+	userID := seedPosts(t, db, [][2]string{
+		{"The state of the web", ""},
+		{"Kubernetes operators", ""},
+		{"Release notes", "Code at github.com/see-why/gator/tree/main"},
+	})
+
+	tests := map[string]string{
+		"the":           "The state of the web", // a stop word
+		"kube":          "Kubernetes operators", // a word prefix
+		"see-why/gator": "Release notes",        // part of a URL
+	}
+	for query, want := range tests {
+		posts, err := Posts(context.Background(), db, userID, query, 10, 0)
+		if err != nil {
+			t.Fatalf("Posts(%q): %v", query, err)
+		}
+		if got := titles(posts); len(got) != 1 || got[0] != want {
+			t.Errorf("Posts(%q) titles = %q; want [%q]", query, got, want)
+		}
+
+		count, err := Count(context.Background(), db, userID, query)
+		if err != nil {
+			t.Fatalf("Count(%q): %v", query, err)
+		}
+		if count != 1 {
+			t.Errorf("Count(%q) = %d; want 1", query, count)
+		}
+	}
+}
+
+// countingDB counts the queries run through it
+type countingDB struct {
+	*sql.DB
+	queries int
+}
+
+func (c *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries++
+	return c.DB.QueryContext(ctx, query, args...)
+}
+
+func (c *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries++
+	return c.DB.QueryRowContext(ctx, query, args...)
+}
+
+func TestPosts_PagesFullTextAndFallbackMatches(t *testing.T) {
+	conn := &countingDB{DB: dbtest.Open(t)}
+	db := database.New(conn)
+	userID := seedPosts(t, db, [][2]string{
+		{"Generics in practice", ""},
+		{"Kubernetes operators", ""},
+		{"Nongenericsy", ""}, // only a substring match finds "generics" here
+		{"Kubernetes upgrades", ""},
+		{"Weekly roundup on generics", ""},
+		{"Kubernetes networking", ""},
+	})
+	ctx := context.Background()
+
+	// A page of full-text matches takes a single query
+	conn.queries = 0
+	posts, err := Posts(ctx, db, userID, "generics", 2, 0)
+	if err != nil {
+		t.Fatalf("Posts: %v", err)
+	}
+	if len(posts) != 2 || conn.queries != 1 {
+		t.Fatalf("got %d posts in %d queries; want 2 in 1", len(posts), conn.queries)
+	}
+
+	// Past the last full-text match there is nothing more, not substring matches
+	posts, err = Posts(ctx, db, userID, "generics", 2, 2)
+	if err != nil {
+		t.Fatalf("Posts: %v", err)
+	}
+	if len(posts) != 0 {
+		t.Fatalf("titles = %q; want none past the last full-text match", titles(posts))
+	}
+
+	// Later pages of a search that fell back keep falling back
+	posts, err = Posts(ctx, db, userID, "kube", 2, 2)
+	if err != nil {
+		t.Fatalf("Posts: %v", err)
+	}
+	if got := titles(posts); len(got) != 1 || got[0] != "Kubernetes networking" {
+		t.Fatalf("titles = %q; want the last substring match", got)
+	}
+}
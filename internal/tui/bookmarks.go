@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// bookmarkToggledMsg reports the result of toggling a post's bookmark
+type bookmarkToggledMsg struct {
+	postID     string
+	bookmarked bool
+	// already is set when the post turned out to be bookmarked already
+	already bool
+	err     error
+}
+
+// toggleBookmark bookmarks post, or removes its bookmark if it has one
+func (m Model) toggleBookmark(post PostItem) tea.Cmd {
+	return func() tea.Msg {
+		postID, err := uuid.Parse(post.ID)
+		if err != nil {
+			return bookmarkToggledMsg{postID: post.ID, bookmarked: post.Bookmarked, err: err}
+		}
+
+		if post.Bookmarked {
+			_, err := m.db.DeleteBookmark(context.Background(), database.DeleteBookmarkParams{
+				UserID: m.userID,
+				PostID: postID,
+			})
+			if err != nil {
+				return bookmarkToggledMsg{postID: post.ID, bookmarked: true, err: err}
+			}
+			return bookmarkToggledMsg{postID: post.ID, bookmarked: false}
+		}
+
+		now := time.Now().UTC()
+		_, err = m.db.CreateBookmark(context.Background(), database.CreateBookmarkParams{
+			ID:        uuid.New(),
+			CreatedAt: now,
+			UpdatedAt: now,
+			UserID:    m.userID,
+			PostID:    postID,
+		})
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return bookmarkToggledMsg{postID: post.ID, bookmarked: true, already: true}
+		}
+		if err != nil {
+			return bookmarkToggledMsg{postID: post.ID, bookmarked: false, err: err}
+		}
+		return bookmarkToggledMsg{postID: post.ID, bookmarked: true}
+	}
+}
+
+// applyBookmark records a toggled bookmark on the listed and selected posts
+func (m Model) applyBookmark(msg bookmarkToggledMsg) Model {
+	for i := range m.posts {
+		if m.posts[i].ID == msg.postID {
+			m.posts[i].Bookmarked = msg.bookmarked
+		}
+	}
+	if m.selectedPost.ID == msg.postID {
+		m.selectedPost.Bookmarked = msg.bookmarked
+		if m.viewingPost {
+			m.postView.setContent(m.renderPostBody())
+		}
+	}
+
+	switch {
+	case msg.err != nil:
+		m.status = fmt.Sprintf("Couldn't update bookmark: %v", msg.err)
+	case msg.already:
+		m.status = "★ Already bookmarked"
+	case msg.bookmarked:
+		m.status = "★ Bookmarked"
+	default:
+		m.status = "Bookmark removed"
+	}
+	return m
+}
+
+// markBookmarked flags the items the user has bookmarked
+func (m Model) markBookmarked(items []PostItem) error {
+	ids, err := m.db.GetBookmarkedPostIDs(context.Background(), m.userID)
+	if err != nil {
+		return err
+	}
+	bookmarked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		bookmarked[id.String()] = true
+	}
+	for i := range items {
+		items[i].Bookmarked = bookmarked[items[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gator/internal/database"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+func newBookmarkModel(db *recordingDB) Model {
+	return Model{
+		db:     database.New(db),
+		userID: uuid.New(),
+		posts: []PostItem{
+			{ID: uuid.NewString(), Title: "first", Bookmarked: true},
+			{ID: uuid.NewString(), Title: "second"},
+		},
+	}
+}
+
+func TestBookmarkToggle_RemovesFromList(t *testing.T) {
+	db := &recordingDB{}
+	m := newBookmarkModel(db)
+
+	next, cmd := m.Update(runes("b"))
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatalf("expected b to return a command toggling the bookmark")
+	}
+	msg := cmd()
+	if len(db.execArgs) != 1 || db.execArgs[0][0] != m.userID || db.execArgs[0][1].(uuid.UUID).String() != m.posts[0].ID {
+		t.Fatalf("expected DeleteBookmark for the highlighted post, got %v", db.execArgs)
+	}
+
+	next, _ = m.Update(msg)
+	m = next.(Model)
+	if m.posts[0].Bookmarked || m.status != "Bookmark removed" {
+		t.Fatalf("expected the bookmark to be removed, got %+v status=%q", m.posts[0], m.status)
+	}
+	if strings.Contains(m.View(), "first ★") {
+		t.Fatalf("expected no bookmark indicator, got %q", m.View())
+	}
+}
+
+func TestBookmarkToggle_IndicatorAndAlreadyBookmarked(t *testing.T) {
+	m := newBookmarkModel(&recordingDB{})
+	if !strings.Contains(m.View(), "first ★") {
+		t.Fatalf("expected an indicator next to the bookmarked post, got %q", m.View())
+	}
+
+	// Opening the post and toggling it targets the viewed post
+	m = typeKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	next, _ := m.Update(bookmarkToggledMsg{postID: m.selectedPost.ID, bookmarked: true, already: true})
+	m = next.(Model)
+	if !m.selectedPost.Bookmarked || !m.posts[1].Bookmarked {
+		t.Fatalf("expected the viewed post to be marked bookmarked")
+	}
+	if !strings.Contains(m.View(), "★ Already bookmarked") || !strings.Contains(m.View(), "★ Bookmarked") {
+		t.Fatalf("expected the already-bookmarked status and indicator, got %q", m.View())
+	}
+
+	// The status clears on the next key
+	m = typeKeys(m, runes("j"))
+	if m.status != "" {
+		t.Fatalf("expected the status to clear, got %q", m.status)
+	}
+}
+
+func TestBookmarkToggle_ErrorKeepsState(t *testing.T) {
+	m := newBookmarkModel(&recordingDB{})
+	next, _ := m.Update(bookmarkToggledMsg{postID: m.posts[1].ID, bookmarked: false, err: errors.New("db down")})
+	m = next.(Model)
+	if m.posts[1].Bookmarked || m.err != nil || !strings.Contains(m.status, "db down") {
+		t.Fatalf("expected the error in the status line only, got post=%+v err=%v status=%q", m.posts[1], m.err, m.status)
+	}
+}
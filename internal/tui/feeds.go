@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FeedItem represents a followed feed in the TUI
+type FeedItem struct {
+	Name string
+	URL  string
+}
+
+type feedsLoadedMsg struct {
+	feeds []FeedItem
+	err   error
+}
+
+type feedUnfollowedMsg struct {
+	feed FeedItem
+	err  error
+}
+
+// updateFeeds handles key input while the feed list is shown
+func (m Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmUnfollow {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "y", "Y":
+			m.confirmUnfollow = false
+			m.loading = true
+			return m, m.unfollowFeed(m.feeds[m.feedCursor])
+		case "n", "N", "esc":
+			m.confirmUnfollow = false
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "esc":
+		// Follows may have changed, so reload the post list
+		m.viewingFeeds = false
+		m.status = ""
+		m.cursor = 0
+		m.currentPage = 1
+		m.isSearching = false
+		m.searchQuery = ""
+		m.loading = true
+		return m, m.loadPosts()
+
+	case "up", "k":
+		if m.feedCursor > 0 {
+			m.feedCursor--
+		}
+
+	case "down", "j":
+		if m.feedCursor < len(m.feeds)-1 {
+			m.feedCursor++
+		}
+
+	case "enter":
+		// Show only this feed's posts
+		if len(m.feeds) > 0 {
+			m.feedFilter = m.feeds[m.feedCursor]
+			m.viewingFeeds = false
+			m.status = ""
+			m.cursor = 0
+			m.currentPage = 1
+			m.isSearching = false
+			m.searchQuery = ""
+			m.loading = true
+			return m, m.loadPosts()
+		}
+
+	case "u", "d":
+		if len(m.feeds) > 0 {
+			m.confirmUnfollow = true
+			m.status = ""
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) renderFeedList() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("62")).
+		Padding(0, 1)
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📚 Following %d feeds", len(m.feeds))))
+	b.WriteString("\n\n")
+
+	if len(m.feeds) == 0 {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244")).
+			Render("You're not following any feeds."))
+		b.WriteString("\n")
+	}
+
+	for i, feed := range m.feeds {
+		style := lipgloss.NewStyle().Padding(0, 2)
+		if i == m.feedCursor {
+			style = style.
+				Background(lipgloss.Color("62")).
+				Foreground(lipgloss.Color("230")).
+				Bold(true)
+		}
+		label := fmt.Sprintf("▶ %s", truncate(feed.Name, 40))
+		if feed.URL == m.feedFilter.URL {
+			label += " (filtered)"
+		}
+		b.WriteString(style.Render(label))
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(" " + feed.URL))
+		b.WriteString("\n")
+
+		// Confirmation prompt, inline under the selected feed
+		if i == m.feedCursor && m.confirmUnfollow {
+			b.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color("208")).
+				Bold(true).
+				Padding(0, 4).
+				Render(fmt.Sprintf("Unfollow %s? (y/n)", feed.Name)))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.status != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(m.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	controlsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	controls := "Navigate: ↑/k ↓/j  Show posts: Enter  Unfollow: u/d  Back: Esc  Quit: q"
+	b.WriteString(controlsStyle.Render(controls))
+
+	return b.String()
+}
+
+func (m Model) loadFeeds() tea.Cmd {
+	return func() tea.Msg {
+		follows, err := m.db.GetFeedFollowsForUser(context.Background(), m.userID)
+		if err != nil {
+			return feedsLoadedMsg{err: err}
+		}
+
+		items := make([]FeedItem, len(follows))
+		for i, follow := range follows {
+			items[i] = FeedItem{Name: follow.FeedName, URL: follow.FeedUrl}
+		}
+		return feedsLoadedMsg{feeds: items}
+	}
+}
+
+func (m Model) unfollowFeed(feed FeedItem) tea.Cmd {
+	return func() tea.Msg {
+		_, err := m.db.DeleteFeedFollowByUserAndFeedURL(context.Background(), database.DeleteFeedFollowByUserAndFeedURLParams{
+			UserID: m.userID,
+			Url:    feed.URL,
+		})
+		return feedUnfollowedMsg{feed: feed, err: err}
+	}
+}
+
+// loadFeedPosts loads a page of posts from the filtered feed and its post count
+func (m Model) loadFeedPosts(offset int32) ([]database.GetPostsForUserRow, int64, error) {
+	feedPosts, err := m.db.GetPostsForUserByFeed(context.Background(), database.GetPostsForUserByFeedParams{
+		UserID: m.userID,
+		Feed:   m.feedFilter.URL,
+		Limit:  postsPerPage,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := m.db.CountPostsForUserByFeed(context.Background(), database.CountPostsForUserByFeedParams{
+		UserID: m.userID,
+		Feed:   m.feedFilter.URL,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	posts := make([]database.GetPostsForUserRow, len(feedPosts))
+	for i, post := range feedPosts {
+		posts[i] = database.GetPostsForUserRow(post)
+	}
+	return posts, count, nil
+}
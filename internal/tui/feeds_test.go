@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"gator/internal/database"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+// recordingDB is a database.DBTX that records Exec arguments. Queries are not supported.
+type recordingDB struct {
+	execArgs [][]interface{}
+}
+
+func (r *recordingDB) ExecContext(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	r.execArgs = append(r.execArgs, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (r *recordingDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	panic("recordingDB does not support PrepareContext")
+}
+
+func (r *recordingDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	panic("recordingDB does not support QueryContext")
+}
+
+func (r *recordingDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	panic("recordingDB does not support QueryRowContext")
+}
+
+// firstMsg runs cmd, or the first command of a batch, such as a load batched
+// with the spinner's tick
+func firstMsg(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		return batch[0]()
+	}
+	return msg
+}
+
+func newFeedListModel(db *recordingDB) Model {
+	return Model{
+		db:           database.New(db),
+		userID:       uuid.New(),
+		viewingFeeds: true,
+		feeds: []FeedItem{
+			{Name: "A", URL: "https://a.example.com/feed"},
+			{Name: "B", URL: "https://b.example.com/feed"},
+		},
+	}
+}
+
+func TestFeedList_ConfirmThenUnfollow(t *testing.T) {
+	db := &recordingDB{}
+	m := newFeedListModel(db)
+
+	m = typeKeys(m, runes("j"), runes("u"))
+	if !m.confirmUnfollow {
+		t.Fatalf("expected confirmation prompt after u")
+	}
+	if len(db.execArgs) != 0 {
+		t.Fatalf("expected no unfollow before confirmation")
+	}
+
+	next, cmd := m.Update(runes("y"))
+	m = next.(Model)
+	if m.confirmUnfollow || !m.loading || cmd == nil {
+		t.Fatalf("expected y to close the prompt and start the unfollow, got confirm=%v loading=%v", m.confirmUnfollow, m.loading)
+	}
+
+	msg := firstMsg(cmd)
+	unfollowed, ok := msg.(feedUnfollowedMsg)
+	if !ok || unfollowed.err != nil {
+		t.Fatalf("expected successful feedUnfollowedMsg, got %#v", msg)
+	}
+	if len(db.execArgs) != 1 || db.execArgs[0][1] != "https://b.example.com/feed" {
+		t.Fatalf("expected unfollow of the selected feed, got %v", db.execArgs)
+	}
+
+	// The result refreshes the feed list
+	next, cmd = m.Update(msg)
+	m = next.(Model)
+	if cmd == nil || m.status != "Unfollowed B" {
+		t.Fatalf("expected a refresh and status after unfollow, got status=%q", m.status)
+	}
+}
+
+func TestFeedList_CancelUnfollow(t *testing.T) {
+	for _, cancel := range []tea.KeyMsg{runes("n"), {Type: tea.KeyEsc}} {
+		db := &recordingDB{}
+		m := newFeedListModel(db)
+
+		m = typeKeys(m, runes("d"), cancel)
+		if m.confirmUnfollow || m.loading {
+			t.Fatalf("expected %q to cancel the prompt", cancel.String())
+		}
+		if !m.viewingFeeds || len(m.feeds) != 2 {
+			t.Fatalf("expected to stay on the unchanged feed list after %q", cancel.String())
+		}
+		if len(db.execArgs) != 0 {
+			t.Fatalf("expected no unfollow after %q, got %v", cancel.String(), db.execArgs)
+		}
+	}
+}
+
+func TestFeedList_EnterFiltersPosts(t *testing.T) {
+	m := newFeedListModel(&recordingDB{})
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.viewingFeeds || !m.loading || cmd == nil {
+		t.Fatalf("expected Enter to return to a loading post list, got viewingFeeds=%v loading=%v", m.viewingFeeds, m.loading)
+	}
+	if m.feedFilter.URL != "https://a.example.com/feed" {
+		t.Fatalf("feedFilter = %+v; want feed A", m.feedFilter)
+	}
+
+	next, _ = m.Update(postsLoadedMsg{posts: []PostItem{{ID: uuid.NewString(), Title: "from A"}}, totalPages: 1})
+	m = next.(Model)
+	if view := m.View(); !strings.Contains(view, "📰 A - Page 1/1") {
+		t.Fatalf("expected the active filter in the header, got %q", view)
+	}
+
+	// c returns to all posts
+	next, cmd = m.Update(runes("c"))
+	m = next.(Model)
+	if m.feedFilter.URL != "" || !m.loading || cmd == nil {
+		t.Fatalf("expected c to clear the filter and reload, got %+v", m.feedFilter)
+	}
+}
+
+func TestFeedList_UnfollowingFilteredFeedClearsFilter(t *testing.T) {
+	m := newFeedListModel(&recordingDB{})
+	m.feedFilter = m.feeds[0]
+
+	next, _ := m.Update(feedUnfollowedMsg{feed: m.feeds[0]})
+	m = next.(Model)
+	if m.feedFilter.URL != "" {
+		t.Fatalf("expected the filter on an unfollowed feed to be cleared, got %+v", m.feedFilter)
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpSection groups related shortcuts in the help overlay
+type helpSection struct {
+	title string
+	keys  [][2]string // key, description
+}
+
+var helpSections = []helpSection{
+	{"Navigation", [][2]string{
+		{"↑/k ↓/j", "Move through posts, or scroll an open post"},
+		{"PgUp/PgDn", "Scroll an open post by a screen"},
+		{"Enter", "Open the highlighted post"},
+		{"Esc", "Back to the list"},
+	}},
+	{"Pages", [][2]string{
+		{"←/h →/l", "Previous and next page"},
+		{"g/G/:", "Jump to a page"},
+	}},
+	{"Search", [][2]string{
+		{"/", "Search posts"},
+		{"c", "Clear the search and feed filter"},
+	}},
+	{"Posts", [][2]string{
+		{"b", "Bookmark or unbookmark a post"},
+		{"o", "Open the highlighted or open post in the browser"},
+	}},
+	{"Feeds", [][2]string{
+		{"f", "List followed feeds"},
+		{"Enter", "Show only the highlighted feed's posts"},
+		{"u/d", "Unfollow the highlighted feed"},
+	}},
+	{"General", [][2]string{
+		{"?", "Show or hide this help"},
+		{"q/Ctrl+C", "Quit"},
+	}},
+}
+
+// updateHelp handles key input while the help overlay is shown
+func (m Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "?", "esc":
+		m.showHelp = false
+	}
+	return m, nil
+}
+
+func (m Model) renderHelp() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("62"))
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("208"))
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("39")).
+		Width(12)
+	descStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	b.WriteString(headerStyle.Render("⌨  Keyboard shortcuts"))
+	b.WriteString("\n")
+	for _, section := range helpSections {
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render(section.title))
+		b.WriteString("\n")
+		for _, key := range section.keys {
+			b.WriteString(fmt.Sprintf("  %s%s\n", keyStyle.Render(key[0]), descStyle.Render(key[1])))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Render("Press ? or Esc to close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Render(b.String())
+
+	if m.width == 0 || m.height == 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// spinnerFrames animate the loading indicator, one frame per spinnerInterval
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// spinnerTickMsg advances the loading spinner by one frame
+type spinnerTickMsg struct{}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// spinnerFrame is the spinner's current frame
+func (m Model) spinnerFrame() string {
+	return spinnerFrames[m.spinnerStep%len(spinnerFrames)]
+}
+
+// startSpinner starts the spinner's tick loop when a load is under way and
+// the loop isn't already running. The loop ends on the first tick after the
+// load finishes.
+func (m Model) startSpinner(cmd tea.Cmd) (Model, tea.Cmd) {
+	if !m.loading || m.spinning {
+		return m, cmd
+	}
+	m.spinning = true
+	return m, tea.Batch(cmd, spinnerTick())
+}
+
+// updateSpinner handles a spinner tick
+func (m Model) updateSpinner() (tea.Model, tea.Cmd) {
+	if !m.loading {
+		m.spinning = false
+		return m, nil
+	}
+	m.spinnerStep++
+	return m, spinnerTick()
+}
@@ -2,11 +2,12 @@ package tui
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"gator/internal/database"
+	"gator/internal/search"
 	"html"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,13 @@ import (
 
 const (
 	postsPerPage = 10
+
+	// searchDebounce is how long live search waits after the last keystroke
+	searchDebounce = 300 * time.Millisecond
+
+	// postViewChrome is how many lines the post view's header and footer take
+	// around the scrolling body
+	postViewChrome = 9
 )
 
 // PostItem represents a post in the TUI
@@ -26,9 +34,11 @@ type PostItem struct {
 	Title       string
 	URL         string
 	FeedName    string
+	Author      string
 	Description string
 	PublishedAt time.Time
 	HasDate     bool
+	Bookmarked  bool
 }
 
 // Model represents the TUI state
@@ -37,44 +47,104 @@ type Model struct {
 	userID       uuid.UUID
 	posts        []PostItem
 	currentPage  int
+	totalPages   int
 	cursor       int
 	viewingPost  bool
 	selectedPost PostItem
+	postView     viewport
 	loading      bool
+	spinning     bool // the spinner's tick loop is running
+	spinnerStep  int
 	err          error
 	width        int
 	height       int
 	searchMode   bool
 	searchQuery  string
 	isSearching  bool
+	jumpMode     bool
+	jumpInput    string
+	showHelp     bool
+
+	// Live search runs a debounced search as the query is typed. Each
+	// keystroke bumps searchGen, so pending searches and results from
+	// older generations are discarded.
+	liveSearch bool
+	searchGen  int
+
+	// Feed list state; confirmUnfollow is set while the inline
+	// "unfollow?" prompt is shown for the feed under feedCursor
+	viewingFeeds    bool
+	feeds           []FeedItem
+	feedCursor      int
+	confirmUnfollow bool
+
+	// feedFilter limits the post list to one feed, picked with Enter in
+	// the feed list; a zero FeedItem shows every followed feed
+	feedFilter FeedItem
+
+	// status is a one-line result message, such as a bookmark change,
+	// shown until the next key press
+	status string
 }
 
 type postsLoadedMsg struct {
-	posts []PostItem
-	err   error
+	posts      []PostItem
+	totalPages int
+	err        error
+
+	// live and gen identify results of a live search
+	live bool
+	gen  int
+}
+
+// postReadMsg reports the result of marking an opened post read
+type postReadMsg struct {
+	err error
+}
+
+// searchDebounceMsg fires when the debounce delay for live search generation gen expires
+type searchDebounceMsg struct {
+	gen int
 }
 
-// NewModel creates a new TUI model
-func NewModel(db *database.Queries, userID uuid.UUID) Model {
+// NewModel creates a new TUI model. With liveSearch, results update as the
+// search query is typed instead of only on Enter.
+func NewModel(db *database.Queries, userID uuid.UUID, liveSearch bool) Model {
 	return Model{
 		db:          db,
 		userID:      userID,
 		currentPage: 1,
+		totalPages:  1,
 		loading:     true,
+		spinning:    true, // Init starts the tick loop
+		liveSearch:  liveSearch,
 	}
 }
 
 // Init initializes the TUI
 func (m Model) Init() tea.Cmd {
-	return m.loadPosts()
+	return tea.Batch(m.loadPosts(), spinnerTick())
 }
 
-// Update handles TUI events
+// Update handles TUI events, animating the spinner whenever a load starts
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(spinnerTickMsg); ok {
+		return m.updateSpinner()
+	}
+	next, cmd := m.update(msg)
+	return next.(Model).startSpinner(cmd)
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.postView.setHeight(max(m.height-postViewChrome, 1))
+		if m.viewingPost {
+			// The body wraps to the new width
+			m.postView.setContent(m.renderPostBody())
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -82,23 +152,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.showHelp {
+			return m.updateHelp(msg)
+		}
+
+		if m.jumpMode {
+			return m.updateJump(msg)
+		}
+
+		if m.viewingFeeds {
+			return m.updateFeeds(msg)
+		}
+
+		if m.searchMode {
+			return m.updateSearch(msg)
+		}
+
+		m.status = ""
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 
 		case "esc":
-			if m.searchMode {
-				m.searchMode = false
-				m.searchQuery = ""
-				m.isSearching = false
-				return m, nil
-			}
 			if m.viewingPost {
 				m.viewingPost = false
 				return m, nil
 			}
 			return m, tea.Quit
 
+		case "?":
+			m.showHelp = true
+			return m, nil
+
+		case "g", "G", ":":
+			if !m.viewingPost {
+				m.jumpMode = true
+				m.jumpInput = ""
+				return m, nil
+			}
+
+		case "f":
+			if !m.viewingPost {
+				m.viewingFeeds = true
+				m.feedCursor = 0
+				m.status = ""
+				m.loading = true
+				return m, m.loadFeeds()
+			}
+
 		case "/":
 			if !m.viewingPost {
 				m.searchMode = true
@@ -107,23 +208,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "enter":
-			if m.searchMode {
-				m.searchMode = false
-				m.isSearching = true
-				m.loading = true
-				m.cursor = 0
-				m.currentPage = 1
-				return m, m.searchPosts()
-			}
 			if !m.viewingPost && len(m.posts) > 0 {
 				m.selectedPost = m.posts[m.cursor]
 				m.viewingPost = true
-				return m, nil
+				m.postView.setContent(m.renderPostBody())
+				m.postView.gotoTop()
+				return m, m.markRead(m.selectedPost)
 			}
 
 		case "c":
-			if !m.viewingPost && !m.searchMode {
-				// Clear search and go back to browse mode
+			if !m.viewingPost {
+				// Clear search and feed filter and go back to browse mode
+				m.feedFilter = FeedItem{}
 				m.searchQuery = ""
 				m.isSearching = false
 				m.loading = true
@@ -132,75 +228,238 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.loadPosts()
 			}
 
+		case "b":
+			if m.viewingPost {
+				return m, m.toggleBookmark(m.selectedPost)
+			}
+			if len(m.posts) > 0 {
+				return m, m.toggleBookmark(m.posts[m.cursor])
+			}
+
 		case "o":
+			// Open the viewed or highlighted post in the browser
 			if m.viewingPost {
-				// Open in browser
-				browser.OpenURL(m.selectedPost.URL)
-				return m, nil
+				return m.openInBrowser(m.selectedPost), nil
+			}
+			if len(m.posts) > 0 {
+				return m.openInBrowser(m.posts[m.cursor]), nil
 			}
 
 		case "up", "k":
-			if !m.viewingPost && !m.searchMode && m.cursor > 0 {
+			if m.viewingPost {
+				m.postView.scroll(-1)
+			} else if m.cursor > 0 {
 				m.cursor--
 			}
 
 		case "down", "j":
-			if !m.viewingPost && !m.searchMode && m.cursor < len(m.posts)-1 {
+			if m.viewingPost {
+				m.postView.scroll(1)
+			} else if m.cursor < len(m.posts)-1 {
 				m.cursor++
 			}
 
-		case "left", "h":
-			if !m.viewingPost && !m.searchMode && m.currentPage > 1 {
-				m.currentPage--
-				m.cursor = 0
-				m.loading = true
-				if m.isSearching {
-					return m, m.searchPosts()
-				}
-				return m, m.loadPosts()
+		case "pgup":
+			if m.viewingPost {
+				m.postView.pageUp()
 			}
 
-		case "right", "l":
-			if !m.viewingPost && !m.searchMode {
-				m.currentPage++
-				m.cursor = 0
-				m.loading = true
-				if m.isSearching {
-					return m, m.searchPosts()
-				}
-				return m, m.loadPosts()
+		case "pgdown":
+			if m.viewingPost {
+				m.postView.pageDown()
 			}
 
-		case "backspace":
-			if m.searchMode && len(m.searchQuery) > 0 {
-				m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+		case "left", "h":
+			if !m.viewingPost && m.currentPage > 1 {
+				return m.goToPage(m.currentPage - 1)
 			}
 
-		default:
-			if m.searchMode && len(msg.String()) == 1 {
-				m.searchQuery += msg.String()
+		case "right", "l":
+			if !m.viewingPost && m.currentPage < m.totalPages {
+				return m.goToPage(m.currentPage + 1)
 			}
 		}
 
+	case searchDebounceMsg:
+		if msg.gen != m.searchGen || !m.searchMode {
+			return m, nil
+		}
+		m.isSearching = true
+		m.cursor = 0
+		m.currentPage = 1
+		return m, m.liveSearchPosts(msg.gen)
+
 	case postsLoadedMsg:
+		if msg.live && msg.gen != m.searchGen {
+			return m, nil
+		}
 		m.loading = false
 		m.posts = msg.posts
+		m.totalPages = msg.totalPages
 		m.err = msg.err
 		if m.cursor >= len(m.posts) {
 			m.cursor = 0
 		}
 		return m, nil
+
+	case bookmarkToggledMsg:
+		return m.applyBookmark(msg), nil
+
+	case postReadMsg:
+		// Failing to record the read shouldn't get in the way of reading
+		return m, nil
+
+	case feedsLoadedMsg:
+		m.loading = false
+		m.feeds = msg.feeds
+		m.err = msg.err
+		if m.feedCursor >= len(m.feeds) {
+			m.feedCursor = max(len(m.feeds)-1, 0)
+		}
+		return m, nil
+
+	case feedUnfollowedMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.err = msg.err
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Unfollowed %s", msg.feed.Name)
+		if msg.feed.URL == m.feedFilter.URL {
+			m.feedFilter = FeedItem{}
+		}
+		return m, m.loadFeeds()
+	}
+
+	return m, nil
+}
+
+// updateSearch handles key input while the search prompt is open. Every
+// printable key is part of the query; with live search, query changes
+// schedule a debounced search.
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prevQuery := m.searchQuery
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.searchMode = false
+		m.searchQuery = ""
+		m.searchGen++
+		if m.liveSearch && m.isSearching {
+			// Live results replaced the listing, so restore it
+			m.isSearching = false
+			m.loading = true
+			m.cursor = 0
+			m.currentPage = 1
+			return m, m.loadPosts()
+		}
+		m.isSearching = false
+		return m, nil
+
+	case "enter":
+		m.searchMode = false
+		m.searchGen++
+		m.isSearching = true
+		m.loading = true
+		m.cursor = 0
+		m.currentPage = 1
+		return m, m.searchPosts()
+
+	case "backspace":
+		if len(m.searchQuery) > 0 {
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+		}
+
+	default:
+		switch msg.Type {
+		case tea.KeyRunes:
+			m.searchQuery += string(msg.Runes)
+		case tea.KeySpace:
+			m.searchQuery += " "
+		}
 	}
 
+	if m.liveSearch && m.searchQuery != prevQuery {
+		return m, m.scheduleSearch()
+	}
 	return m, nil
 }
 
+// updateJump handles key input while the jump-to-page prompt is open
+func (m Model) updateJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.jumpMode = false
+		m.jumpInput = ""
+
+	case "backspace":
+		if len(m.jumpInput) > 0 {
+			m.jumpInput = m.jumpInput[:len(m.jumpInput)-1]
+		}
+
+	case "enter":
+		m.jumpMode = false
+		page, err := strconv.Atoi(m.jumpInput)
+		m.jumpInput = ""
+		if err != nil {
+			return m, nil
+		}
+		return m.goToPage(clampPage(page, m.totalPages))
+
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+			m.jumpInput += key
+		}
+	}
+	return m, nil
+}
+
+// goToPage loads the given page of the current listing
+func (m Model) goToPage(page int) (tea.Model, tea.Cmd) {
+	m.currentPage = page
+	m.cursor = 0
+	m.loading = true
+	if m.isSearching {
+		return m, m.searchPosts()
+	}
+	return m, m.loadPosts()
+}
+
+// clampPage limits page to the range 1..totalPages
+func clampPage(page, totalPages int) int {
+	if page > totalPages {
+		page = totalPages
+	}
+	if page < 1 {
+		page = 1
+	}
+	return page
+}
+
+// pageCount returns the number of pages needed to show count posts, at least 1
+func pageCount(count int64) int {
+	pages := int((count + postsPerPage - 1) / postsPerPage)
+	if pages < 1 {
+		pages = 1
+	}
+	return pages
+}
+
 // View renders the TUI
 func (m Model) View() string {
 	if m.loading {
+		loadingText := m.spinnerFrame() + " Loading posts..."
+		if m.viewingFeeds {
+			loadingText = m.spinnerFrame() + " Loading feeds..."
+		}
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color("69")).
-			Render("Loading posts...")
+			Render(loadingText)
 	}
 
 	if m.err != nil {
@@ -209,10 +468,18 @@ func (m Model) View() string {
 			Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
+	if m.showHelp {
+		return m.renderHelp()
+	}
+
 	if m.viewingPost {
 		return m.renderPostView()
 	}
 
+	if m.viewingFeeds {
+		return m.renderFeedList()
+	}
+
 	return m.renderPostList()
 }
 
@@ -225,14 +492,29 @@ func (m Model) renderPostList() string {
 		Foreground(lipgloss.Color("62")).
 		Padding(0, 1)
 
-	headerText := fmt.Sprintf("📰 Gator Posts - Page %d", m.currentPage)
+	headerText := fmt.Sprintf("📰 Gator Posts - Page %d/%d", m.currentPage, m.totalPages)
+	if m.feedFilter.URL != "" {
+		headerText = fmt.Sprintf("📰 %s - Page %d/%d", m.feedFilter.Name, m.currentPage, m.totalPages)
+	}
 	if m.isSearching && m.searchQuery != "" {
-		headerText = fmt.Sprintf("🔍 Search: \"%s\" - Page %d", m.searchQuery, m.currentPage)
+		headerText = fmt.Sprintf("🔍 Search: \"%s\" - Page %d/%d", m.searchQuery, m.currentPage, m.totalPages)
 	}
 
 	b.WriteString(headerStyle.Render(headerText))
 	b.WriteString("\n\n")
 
+	// Jump-to-page prompt
+	if m.jumpMode {
+		jumpStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")).
+			Border(lipgloss.RoundedBorder()).
+			Padding(0, 1)
+
+		jumpText := fmt.Sprintf("Go to page (1-%d): %s", m.totalPages, m.jumpInput)
+		b.WriteString(jumpStyle.Render(jumpText))
+		b.WriteString("\n\n")
+	}
+
 	// Search input if in search mode
 	if m.searchMode {
 		searchStyle := lipgloss.NewStyle().
@@ -246,11 +528,16 @@ func (m Model) renderPostList() string {
 		}
 		b.WriteString(searchStyle.Render(searchText))
 		b.WriteString("\n\n")
-		return b.String()
+		if !m.liveSearch {
+			return b.String()
+		}
 	}
 
 	if len(m.posts) == 0 {
 		noPostsText := "No posts found. Try following some feeds first!"
+		if m.feedFilter.URL != "" {
+			noPostsText = fmt.Sprintf("No posts from %s yet. Press c to show all feeds.", m.feedFilter.Name)
+		}
 		if m.isSearching {
 			noPostsText = fmt.Sprintf("No posts found matching \"%s\"", m.searchQuery)
 		}
@@ -275,6 +562,9 @@ func (m Model) renderPostList() string {
 			if post.FeedName != "" {
 				postContent += fmt.Sprintf(" [%s]", post.FeedName)
 			}
+			if post.Bookmarked {
+				postContent += " ★"
+			}
 
 			b.WriteString(style.Render(postContent))
 			b.WriteString("\n")
@@ -290,6 +580,12 @@ func (m Model) renderPostList() string {
 		}
 	}
 
+	if m.status != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(m.status))
+		b.WriteString("\n")
+	}
+
 	// Controls
 	b.WriteString("\n")
 	controlsStyle := lipgloss.NewStyle().
@@ -297,7 +593,7 @@ func (m Model) renderPostList() string {
 		Border(lipgloss.RoundedBorder()).
 		Padding(0, 1)
 
-	controls := "Navigate: ↑/k ↓/j  Pages: ←/h →/l  Select: Enter  Search: /  Clear: c  Quit: q"
+	controls := "Navigate: ↑/k ↓/j  Pages: ←/h →/l  Jump: g/:  Select: Enter  Open: o  Bookmark: b  Search: /  Clear: c  Feeds: f  Help: ?  Quit: q"
 	b.WriteString(controlsStyle.Render(controls))
 
 	return b.String()
@@ -317,6 +613,36 @@ func (m Model) renderPostView() string {
 	b.WriteString(headerStyle.Render("📖 Post Details"))
 	b.WriteString("\n\n")
 
+	// Body, scrolled to fit the terminal
+	b.WriteString(m.postView.view())
+	b.WriteString("\n\n")
+
+	// Scroll position, status, and controls
+	metaStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244"))
+	b.WriteString(metaStyle.Render(fmt.Sprintf("%3.0f%%", m.postView.scrollPercent()*100)))
+	if m.status != "" {
+		b.WriteString("  ")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render(m.status))
+	}
+	b.WriteString("\n")
+
+	controlsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+
+	controls := "Scroll: ↑/k ↓/j PgUp/PgDn  Bookmark: b  Open in browser: o  Back: Esc  Help: ?  Quit: q/Ctrl+C"
+	b.WriteString(controlsStyle.Render(controls))
+
+	return b.String()
+}
+
+// renderPostBody renders the selected post's title, metadata, and
+// description, wrapped to the terminal width, for the post view's viewport
+func (m Model) renderPostBody() string {
+	var b strings.Builder
+
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -331,8 +657,16 @@ func (m Model) renderPostView() string {
 		Foreground(lipgloss.Color("244")).
 		Italic(true)
 
+	// The author shares the feed's line, keeping the header within postViewChrome
+	var source []string
 	if m.selectedPost.FeedName != "" {
-		b.WriteString(metaStyle.Render(fmt.Sprintf("Feed: %s", m.selectedPost.FeedName)))
+		source = append(source, fmt.Sprintf("Feed: %s", m.selectedPost.FeedName))
+	}
+	if m.selectedPost.Author != "" {
+		source = append(source, fmt.Sprintf("Author: %s", m.selectedPost.Author))
+	}
+	if len(source) > 0 {
+		b.WriteString(metaStyle.Render(strings.Join(source, " · ")))
 		b.WriteString("\n")
 	}
 
@@ -342,7 +676,12 @@ func (m Model) renderPostView() string {
 	}
 
 	b.WriteString(metaStyle.Render(fmt.Sprintf("URL: %s", m.selectedPost.URL)))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	if m.selectedPost.Bookmarked {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("220")).Render("★ Bookmarked"))
+		b.WriteString("\n")
+	}
 
 	// Description
 	if m.selectedPost.Description != "" {
@@ -351,19 +690,10 @@ func (m Model) renderPostView() string {
 			Width(m.width - 4)
 
 		cleanDesc := cleanHTML(m.selectedPost.Description)
+		b.WriteString("\n")
 		b.WriteString(descStyle.Render(cleanDesc))
-		b.WriteString("\n\n")
 	}
 
-	// Controls
-	controlsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("244")).
-		Border(lipgloss.RoundedBorder()).
-		Padding(0, 1)
-
-	controls := "Open in browser: o  Back: Esc  Quit: q/Ctrl+C"
-	b.WriteString(controlsStyle.Render(controls))
-
 	return b.String()
 }
 
@@ -371,12 +701,21 @@ func (m Model) loadPosts() tea.Cmd {
 	return func() tea.Msg {
 		offset := int32((m.currentPage - 1) * postsPerPage)
 
-		posts, err := m.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-			UserID: m.userID,
-			Limit:  postsPerPage,
-			Offset: offset,
-		})
-
+		var posts []database.GetPostsForUserRow
+		var count int64
+		var err error
+		if m.feedFilter.URL != "" {
+			posts, count, err = m.loadFeedPosts(offset)
+		} else {
+			posts, err = m.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+				UserID: m.userID,
+				Limit:  postsPerPage,
+				Offset: offset,
+			})
+			if err == nil {
+				count, err = m.db.CountPostsForUser(context.Background(), m.userID)
+			}
+		}
 		if err != nil {
 			return postsLoadedMsg{err: err}
 		}
@@ -389,6 +728,7 @@ func (m Model) loadPosts() tea.Cmd {
 				Title:       post.Title,
 				URL:         post.Url,
 				FeedName:    post.FeedName,
+				Author:      post.Author.String,
 				Description: post.Description.String,
 				HasDate:     post.PublishedAt.Valid,
 			}
@@ -397,7 +737,33 @@ func (m Model) loadPosts() tea.Cmd {
 			}
 		}
 
-		return postsLoadedMsg{posts: items}
+		if err := m.markBookmarked(items); err != nil {
+			return postsLoadedMsg{err: err}
+		}
+
+		return postsLoadedMsg{posts: items, totalPages: pageCount(count)}
+	}
+}
+
+// scheduleSearch starts a new live search generation and returns a command
+// that fires once the debounce delay passes without further keystrokes
+func (m *Model) scheduleSearch() tea.Cmd {
+	m.searchGen++
+	gen := m.searchGen
+	return tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+		return searchDebounceMsg{gen: gen}
+	})
+}
+
+// liveSearchPosts runs the search for the current query, tagging the results
+// with gen so they can be dropped if the query has changed since
+func (m Model) liveSearchPosts(gen int) tea.Cmd {
+	search := m.searchPosts()
+	return func() tea.Msg {
+		msg := search().(postsLoadedMsg)
+		msg.live = true
+		msg.gen = gen
+		return msg
 	}
 }
 
@@ -405,17 +771,17 @@ func (m Model) searchPosts() tea.Cmd {
 	return func() tea.Msg {
 		offset := int32((m.currentPage - 1) * postsPerPage)
 
-		posts, err := m.db.SearchPostsForUser(context.Background(), database.SearchPostsForUserParams{
-			UserID:  m.userID,
-			Column2: sql.NullString{String: m.searchQuery, Valid: true},
-			Limit:   postsPerPage,
-			Offset:  offset,
-		})
+		posts, err := search.Posts(context.Background(), m.db, m.userID, m.searchQuery, postsPerPage, offset)
 
 		if err != nil {
 			return postsLoadedMsg{err: err}
 		}
 
+		count, err := search.Count(context.Background(), m.db, m.userID, m.searchQuery)
+		if err != nil {
+			return postsLoadedMsg{err: err}
+		}
+
 		// Convert to PostItem
 		items := make([]PostItem, len(posts))
 		for i, post := range posts {
@@ -424,6 +790,7 @@ func (m Model) searchPosts() tea.Cmd {
 				Title:       post.Title,
 				URL:         post.Url,
 				FeedName:    post.FeedName,
+				Author:      post.Author.String,
 				Description: post.Description.String,
 				HasDate:     post.PublishedAt.Valid,
 			}
@@ -432,16 +799,50 @@ func (m Model) searchPosts() tea.Cmd {
 			}
 		}
 
-		return postsLoadedMsg{posts: items}
+		if err := m.markBookmarked(items); err != nil {
+			return postsLoadedMsg{err: err}
+		}
+
+		return postsLoadedMsg{posts: items, totalPages: pageCount(count)}
+	}
+}
+
+// openURL opens a URL in the user's browser; tests replace it
+var openURL = browser.OpenURL
+
+// openInBrowser opens post's URL, reporting failure in the status line
+func (m Model) openInBrowser(post PostItem) Model {
+	if err := openURL(post.URL); err != nil {
+		m.status = fmt.Sprintf("Couldn't open browser: %v", err)
+	}
+	return m
+}
+
+// markRead records that the user opened post, so `browse --unread` skips it
+func (m Model) markRead(post PostItem) tea.Cmd {
+	return func() tea.Msg {
+		postID, err := uuid.Parse(post.ID)
+		if err != nil {
+			return postReadMsg{err: err}
+		}
+		_, err = m.db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+			UserID: m.userID,
+			PostID: postID,
+			ReadAt: time.Now().UTC(),
+		})
+		return postReadMsg{err: err}
 	}
 }
 
 // Helper functions
+// truncate shortens s to length characters including the trailing "...",
+// counting runes so multi-byte characters aren't split
 func truncate(s string, length int) string {
-	if len(s) <= length {
+	runes := []rune(s)
+	if len(runes) <= length {
 		return s
 	}
-	return s[:length-3] + "..."
+	return string(runes[:length-3]) + "..."
 }
 
 func cleanHTML(s string) string {
@@ -460,8 +861,8 @@ func cleanHTML(s string) string {
 }
 
 // RunTUI starts the TUI application
-func RunTUI(db *database.Queries, userID uuid.UUID) error {
-	model := NewModel(db, userID)
+func RunTUI(db *database.Queries, userID uuid.UUID, liveSearch bool) error {
+	model := NewModel(db, userID, liveSearch)
 
 	program := tea.NewProgram(
 		model,
@@ -0,0 +1,331 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/pkg/browser"
+)
+
+// typeKeys feeds each key to the model in turn, discarding the returned commands
+func typeKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		next, _ := m.Update(key)
+		m = next.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestJumpToPage(t *testing.T) {
+	m := Model{currentPage: 1, totalPages: 12}
+
+	m = typeKeys(m, runes("G"), runes("7"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentPage != 7 {
+		t.Fatalf("currentPage = %d; want 7", m.currentPage)
+	}
+	if m.jumpMode {
+		t.Fatalf("expected jump prompt to close after Enter")
+	}
+	if !m.loading {
+		t.Fatalf("expected the jumped-to page to be loading")
+	}
+}
+
+func TestJumpToPage_ClampsToMax(t *testing.T) {
+	m := Model{currentPage: 2, totalPages: 12}
+
+	m = typeKeys(m, runes(":"), runes("9"), runes("9"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentPage != 12 {
+		t.Fatalf("currentPage = %d; want 12", m.currentPage)
+	}
+}
+
+func TestJumpToPage_LowercaseGWithBackspace(t *testing.T) {
+	m := Model{currentPage: 1, totalPages: 12}
+
+	m = typeKeys(m, runes("g"))
+	if !m.jumpMode || !strings.Contains(m.View(), "Go to page (1-12):") {
+		t.Fatalf("expected g to open the jump prompt, got %q", m.View())
+	}
+	m = typeKeys(m, runes("4"), runes("x"), runes("2"), tea.KeyMsg{Type: tea.KeyBackspace}, runes("5"))
+	if m.jumpInput != "45" {
+		t.Fatalf("jumpInput = %q; want digits only with backspace applied", m.jumpInput)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentPage != 4 || m.jumpMode {
+		t.Fatalf("currentPage = %d; want 4", m.currentPage)
+	}
+}
+
+func TestJumpToPage_BelowOneGoesToFirstPage(t *testing.T) {
+	m := Model{currentPage: 5, totalPages: 12}
+
+	m = typeKeys(m, runes("g"), runes("0"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentPage != 1 {
+		t.Fatalf("currentPage = %d; want 1", m.currentPage)
+	}
+}
+
+func TestJumpToPage_EscCancels(t *testing.T) {
+	m := Model{currentPage: 3, totalPages: 12}
+
+	m = typeKeys(m, runes("G"), runes("5"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentPage != 3 || m.jumpMode || m.loading {
+		t.Fatalf("expected Esc to cancel the jump, got page=%d jumpMode=%v loading=%v", m.currentPage, m.jumpMode, m.loading)
+	}
+}
+
+func TestPageCount(t *testing.T) {
+	cases := map[int64]int{0: 1, 1: 1, 10: 1, 11: 2, 120: 12}
+	for count, want := range cases {
+		if got := pageCount(count); got != want {
+			t.Fatalf("pageCount(%d) = %d; want %d", count, got, want)
+		}
+	}
+}
+
+func TestLiveSearch_OnlyFinalKeystrokeSearches(t *testing.T) {
+	m := Model{currentPage: 1, totalPages: 1, liveSearch: true}
+	m = typeKeys(m, runes("/"))
+
+	// Each keystroke schedules a debounced search for a new generation
+	var cmds []tea.Cmd
+	for _, key := range []string{"g", "o", "l"} {
+		next, cmd := m.Update(runes(key))
+		m = next.(Model)
+		if cmd == nil {
+			t.Fatalf("expected keystroke %q to schedule a search", key)
+		}
+		cmds = append(cmds, cmd)
+	}
+	if m.searchQuery != "gol" || m.searchGen != 3 {
+		t.Fatalf("query=%q gen=%d; want \"gol\" and 3", m.searchQuery, m.searchGen)
+	}
+
+	// The debounce timers for earlier keystrokes fire but are stale
+	for gen := 1; gen <= 2; gen++ {
+		next, cmd := m.Update(searchDebounceMsg{gen: gen})
+		m = next.(Model)
+		if cmd != nil {
+			t.Fatalf("expected stale debounce %d to be ignored", gen)
+		}
+	}
+	if m.isSearching {
+		t.Fatalf("expected no search before the final debounce")
+	}
+
+	next, cmd := m.Update(searchDebounceMsg{gen: 3})
+	m = next.(Model)
+	if cmd == nil || !m.isSearching {
+		t.Fatalf("expected the final debounce to start a search")
+	}
+
+	// Results from an older search are discarded
+	next, _ = m.Update(postsLoadedMsg{posts: []PostItem{{Title: "stale"}}, live: true, gen: 2})
+	m = next.(Model)
+	if len(m.posts) != 0 {
+		t.Fatalf("expected stale results to be discarded, got %v", m.posts)
+	}
+	next, _ = m.Update(postsLoadedMsg{posts: []PostItem{{Title: "golang"}}, live: true, gen: 3})
+	m = next.(Model)
+	if len(m.posts) != 1 || m.posts[0].Title != "golang" {
+		t.Fatalf("expected current results to be shown, got %v", m.posts)
+	}
+}
+
+func TestLiveSearch_DisabledSearchesOnlyOnEnter(t *testing.T) {
+	m := Model{currentPage: 1, totalPages: 1}
+	m = typeKeys(m, runes("/"))
+
+	_, cmd := m.Update(runes("g"))
+	if cmd != nil {
+		t.Fatalf("expected no search to be scheduled without live search")
+	}
+}
+
+func TestSearch_ShortcutLettersAreTyped(t *testing.T) {
+	m := Model{currentPage: 1, totalPages: 1}
+	m = typeKeys(m, runes("/"), runes("h"), runes("o"), runes("q"), tea.KeyMsg{Type: tea.KeySpace}, runes("k"))
+	if m.searchQuery != "hoq k" || !m.searchMode {
+		t.Fatalf("searchQuery = %q; want \"hoq k\" with the prompt still open", m.searchQuery)
+	}
+}
+
+func TestTruncate_KeepsRunesWhole(t *testing.T) {
+	got := truncate("ééééééééé", 6)
+	if !utf8.ValidString(got) || got != "ééé..." {
+		t.Fatalf("truncate = %q; want %q", got, "ééé...")
+	}
+}
+
+func TestOpeningPostMarksItRead(t *testing.T) {
+	m := NewModel(nil, uuid.New(), false)
+	m.loading = false
+	m.posts = []PostItem{{ID: uuid.NewString(), Title: "first"}}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if !m.viewingPost || m.selectedPost.Title != "first" {
+		t.Fatalf("expected the post to be opened")
+	}
+	if cmd == nil {
+		t.Fatalf("expected opening a post to return a command marking it read")
+	}
+}
+
+func TestPostView_ScrollsLongPosts(t *testing.T) {
+	m := NewModel(nil, uuid.New(), false)
+	m.loading = false
+	long := strings.Repeat("word ", 400)
+	m.posts = []PostItem{
+		{ID: uuid.NewString(), Title: "long", Description: long},
+		{ID: uuid.NewString(), Title: "second", Description: long},
+	}
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: postViewChrome + 5})
+	m = next.(Model)
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.postView.yOffset != 0 || m.postView.maxOffset() == 0 {
+		t.Fatalf("expected a scrollable body starting at the top, got offset %d of %d", m.postView.yOffset, m.postView.maxOffset())
+	}
+	if lines := strings.Count(m.View(), "\n") + 1; lines > postViewChrome+5 {
+		t.Fatalf("post view is %d lines; want it to fit the %d-line terminal", lines, postViewChrome+5)
+	}
+	if !strings.Contains(m.View(), "  0%") {
+		t.Fatalf("expected the scroll percentage at the top, got %q", m.View())
+	}
+
+	m = typeKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyDown})
+	if m.postView.yOffset != 2 {
+		t.Fatalf("yOffset = %d after two lines down; want 2", m.postView.yOffset)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.postView.yOffset != 7 {
+		t.Fatalf("yOffset = %d after a page down; want 7", m.postView.yOffset)
+	}
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyPgUp}, runes("k"))
+	if m.postView.yOffset != 1 {
+		t.Fatalf("yOffset = %d after a page and a line up; want 1", m.postView.yOffset)
+	}
+
+	for range 200 {
+		m = typeKeys(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	}
+	if m.postView.yOffset != m.postView.maxOffset() || !strings.Contains(m.View(), "100%") {
+		t.Fatalf("expected paging down to stop at the end, got offset %d of %d", m.postView.yOffset, m.postView.maxOffset())
+	}
+
+	// Esc returns to the list without moving the cursor, and a new post starts at the top
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.viewingPost || m.cursor != 0 {
+		t.Fatalf("expected Esc to return to the list, got viewingPost=%v cursor=%d", m.viewingPost, m.cursor)
+	}
+	m = typeKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.selectedPost.Title != "second" || m.postView.yOffset != 0 {
+		t.Fatalf("expected the second post at the top, got %q at offset %d", m.selectedPost.Title, m.postView.yOffset)
+	}
+}
+
+func TestViewport_ShortContentFits(t *testing.T) {
+	var v viewport
+	v.setHeight(10)
+	v.setContent("one\ntwo")
+	v.pageDown()
+	if v.yOffset != 0 || v.scrollPercent() != 1 || v.view() != "one\ntwo" {
+		t.Fatalf("unexpected viewport state: offset=%d percent=%v view=%q", v.yOffset, v.scrollPercent(), v.view())
+	}
+}
+
+func TestHelpOverlay_TogglesAndBlocksKeys(t *testing.T) {
+	m := NewModel(nil, uuid.New(), false)
+	m.loading = false
+	m.posts = []PostItem{{ID: uuid.NewString(), Title: "first"}, {ID: uuid.NewString(), Title: "second"}}
+
+	m = typeKeys(m, runes("?"))
+	if !m.showHelp || !strings.Contains(m.View(), "Keyboard shortcuts") || !strings.Contains(m.View(), "Bookmark") {
+		t.Fatalf("expected ? to show the help overlay, got %q", m.View())
+	}
+
+	// Other keys don't act on the list behind the overlay
+	m = typeKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.cursor != 0 || m.viewingPost {
+		t.Fatalf("expected keys to be ignored while help is shown, got cursor=%d viewingPost=%v", m.cursor, m.viewingPost)
+	}
+
+	m = typeKeys(m, runes("?"))
+	if m.showHelp || !strings.Contains(m.View(), "Help: ?") {
+		t.Fatalf("expected ? to close the overlay back to the list")
+	}
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("?"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showHelp || !m.viewingPost {
+		t.Fatalf("expected Esc to close the overlay and stay on the open post, got showHelp=%v viewingPost=%v", m.showHelp, m.viewingPost)
+	}
+}
+
+func TestSpinner_AnimatesOnlyWhileLoading(t *testing.T) {
+	m := Model{currentPage: 2, totalPages: 3}
+
+	// Starting a load starts the tick loop alongside it
+	next, cmd := m.Update(runes("h"))
+	m = next.(Model)
+	if !m.loading || !m.spinning {
+		t.Fatalf("expected a page change to load with the spinner running")
+	}
+	if _, ok := cmd().(tea.BatchMsg); !ok {
+		t.Fatalf("expected the load to be batched with a spinner tick")
+	}
+	first := m.View()
+
+	next, cmd = m.Update(spinnerTickMsg{})
+	m = next.(Model)
+	if cmd == nil || m.View() == first || !strings.Contains(m.View(), "Loading posts...") {
+		t.Fatalf("expected a tick to advance the spinner and schedule the next, got %q", m.View())
+	}
+
+	// Once the posts arrive, the next tick ends the loop
+	next, _ = m.Update(postsLoadedMsg{totalPages: 3})
+	m = next.(Model)
+	next, cmd = m.Update(spinnerTickMsg{})
+	m = next.(Model)
+	if cmd != nil || m.spinning {
+		t.Fatalf("expected the spinner to stop after loading")
+	}
+}
+
+func TestOpenFromList(t *testing.T) {
+	var opened []string
+	openErr := error(nil)
+	openURL = func(url string) error {
+		opened = append(opened, url)
+		return openErr
+	}
+	t.Cleanup(func() { openURL = browser.OpenURL })
+
+	m := Model{posts: []PostItem{{URL: "https://a.example.com/1"}, {URL: "https://a.example.com/2"}}}
+	m = typeKeys(m, runes("j"), runes("o"))
+	if len(opened) != 1 || opened[0] != "https://a.example.com/2" || m.viewingPost {
+		t.Fatalf("expected the highlighted post to open from the list, got %v", opened)
+	}
+
+	openErr = errors.New("no browser")
+	m = typeKeys(m, runes("o"))
+	if !strings.Contains(m.status, "no browser") || !strings.Contains(m.View(), "Couldn't open browser") {
+		t.Fatalf("expected the open error as a status, got %q", m.status)
+	}
+
+	// An empty list has nothing to open
+	opened = nil
+	m = typeKeys(Model{}, runes("o"))
+	if len(opened) != 0 {
+		t.Fatalf("expected nothing to open on an empty list, got %v", opened)
+	}
+}
@@ -0,0 +1,67 @@
+package tui
+
+import "strings"
+
+// viewport shows a window of height lines from content taller than the
+// screen. A height of zero (size not yet known) shows every line.
+type viewport struct {
+	lines   []string
+	height  int
+	yOffset int
+}
+
+// setContent replaces the viewport's content, keeping the offset in range
+func (v *viewport) setContent(s string) {
+	v.lines = strings.Split(strings.TrimRight(s, "\n"), "\n")
+	v.clamp()
+}
+
+// setHeight sets how many lines are visible at once
+func (v *viewport) setHeight(height int) {
+	v.height = max(height, 0)
+	v.clamp()
+}
+
+// maxOffset is the offset that shows the last line at the bottom
+func (v *viewport) maxOffset() int {
+	if v.height == 0 {
+		return 0
+	}
+	return max(len(v.lines)-v.height, 0)
+}
+
+func (v *viewport) clamp() {
+	v.yOffset = min(max(v.yOffset, 0), v.maxOffset())
+}
+
+// scroll moves the window down by n lines, or up when n is negative
+func (v *viewport) scroll(n int) {
+	v.yOffset += n
+	v.clamp()
+}
+
+// pageUp and pageDown scroll by a screenful
+func (v *viewport) pageUp()   { v.scroll(-max(v.height, 1)) }
+func (v *viewport) pageDown() { v.scroll(max(v.height, 1)) }
+
+func (v *viewport) gotoTop() {
+	v.yOffset = 0
+}
+
+// scrollPercent is how far through the content the window is, from 0 to 1.
+// Content that fits entirely counts as fully scrolled.
+func (v *viewport) scrollPercent() float64 {
+	if v.maxOffset() == 0 {
+		return 1
+	}
+	return float64(v.yOffset) / float64(v.maxOffset())
+}
+
+// view renders the visible lines
+func (v *viewport) view() string {
+	if v.height == 0 {
+		return strings.Join(v.lines, "\n")
+	}
+	end := min(v.yOffset+v.height, len(v.lines))
+	return strings.Join(v.lines[v.yOffset:end], "\n")
+}
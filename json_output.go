@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"gator/internal/api"
+	"gator/internal/database"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jsonSchemaVersion is included as "schema_version" in every --json CLI
+// output. It is shared with the API's responses; see api.SchemaVersion.
+const jsonSchemaVersion = api.SchemaVersion
+
+// feedJSON is the JSON representation of a feed in CLI output
+type feedJSON struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	UserName    string    `json:"user_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// feedsJSONOutput is the top-level document written by `gator feeds --json`
+type feedsJSONOutput struct {
+	SchemaVersion int        `json:"schema_version"`
+	Feeds         []feedJSON `json:"feeds"`
+}
+
+// writeFeedsJSON writes the feeds list as a versioned JSON document
+func writeFeedsJSON(w io.Writer, feeds []database.GetFeedsWithUsersRow) error {
+	output := feedsJSONOutput{
+		SchemaVersion: jsonSchemaVersion,
+		Feeds:         make([]feedJSON, len(feeds)),
+	}
+	for i, feed := range feeds {
+		output.Feeds[i] = feedJSON{
+			ID:          feed.ID,
+			Name:        feed.Name,
+			URL:         feed.Url,
+			Description: feed.Description.String,
+			UserName:    feed.UserName,
+			CreatedAt:   feed.CreatedAt,
+			UpdatedAt:   feed.UpdatedAt,
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// exportPostJSON is the JSON representation of a post in `gator export`
+type exportPostJSON struct {
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// exportFeedJSON is a followed feed with its newest posts in `gator export`
+type exportFeedJSON struct {
+	ID         uuid.UUID        `json:"id"`
+	Name       string           `json:"name"`
+	URL        string           `json:"url"`
+	FollowedAt time.Time        `json:"followed_at"`
+	Posts      []exportPostJSON `json:"posts"`
+}
+
+// exportJSONOutput is the top-level document written by `gator export --format json`
+type exportJSONOutput struct {
+	SchemaVersion int              `json:"schema_version"`
+	UserName      string           `json:"user_name"`
+	Feeds         []exportFeedJSON `json:"feeds"`
+}
+
+// writeExportJSON writes each followed feed with its posts nested under it
+func writeExportJSON(w io.Writer, userName string, follows []database.GetFeedFollowsForUserRow, postsByFeed map[uuid.UUID][]database.Post) error {
+	output := exportJSONOutput{
+		SchemaVersion: jsonSchemaVersion,
+		UserName:      userName,
+		Feeds:         make([]exportFeedJSON, len(follows)),
+	}
+	for i, follow := range follows {
+		posts := postsByFeed[follow.FeedID]
+		feed := exportFeedJSON{
+			ID:         follow.FeedID,
+			Name:       follow.FeedName,
+			URL:        follow.FeedUrl,
+			FollowedAt: follow.CreatedAt,
+			Posts:      make([]exportPostJSON, len(posts)),
+		}
+		for j, post := range posts {
+			feed.Posts[j] = exportPostJSON{
+				Title:       post.Title,
+				URL:         post.Url,
+				Description: post.Description.String,
+			}
+			if post.PublishedAt.Valid {
+				publishedAt := post.PublishedAt.Time
+				feed.Posts[j].PublishedAt = &publishedAt
+			}
+		}
+		output.Feeds[i] = feed
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func TestWriteFeedsJSON_IncludesSchemaVersion(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "a", Url: "https://a.example.com/feed", UserName: "alice", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: uuid.New(), Name: "b", Url: "https://b.example.com/feed", UserName: "bob", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+
+	var buf bytes.Buffer
+	if err := writeFeedsJSON(&buf, feeds); err != nil {
+		t.Fatalf("writeFeedsJSON returned error: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	raw, ok := decoded["schema_version"]
+	if !ok {
+		t.Fatalf("expected schema_version field in output: %s", buf.String())
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		t.Fatalf("schema_version is not a number: %v", err)
+	}
+	if version != jsonSchemaVersion {
+		t.Fatalf("schema_version = %d; want %d", version, jsonSchemaVersion)
+	}
+
+	var output feedsJSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("couldn't decode feeds output: %v", err)
+	}
+	if len(output.Feeds) != 2 || output.Feeds[0].URL != "https://a.example.com/feed" {
+		t.Fatalf("unexpected feeds in output: %+v", output.Feeds)
+	}
+}
+
+func TestWriteExportJSON_GroupsPostsUnderFeeds(t *testing.T) {
+	feedA, feedB := uuid.New(), uuid.New()
+	follows := []database.GetFeedFollowsForUserRow{
+		{FeedID: feedA, FeedName: "A", FeedUrl: "https://a.example.com/feed", CreatedAt: time.Now()},
+		{FeedID: feedB, FeedName: "B", FeedUrl: "https://b.example.com/feed", CreatedAt: time.Now()},
+	}
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	postsByFeed := map[uuid.UUID][]database.Post{
+		feedA: {
+			{Title: "a1", Url: "https://a.example.com/1", FeedID: feedA, PublishedAt: sql.NullTime{Time: published, Valid: true}},
+			{Title: "a2", Url: "https://a.example.com/2", FeedID: feedA},
+		},
+		feedB: {
+			{Title: "b1", Url: "https://b.example.com/1", FeedID: feedB},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportJSON(&buf, "alice", follows, postsByFeed); err != nil {
+		t.Fatalf("writeExportJSON returned error: %v", err)
+	}
+
+	var output exportJSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if output.SchemaVersion != jsonSchemaVersion || output.UserName != "alice" {
+		t.Fatalf("unexpected header: %+v", output)
+	}
+	if len(output.Feeds) != 2 {
+		t.Fatalf("expected 2 feeds, got %d", len(output.Feeds))
+	}
+
+	a, b := output.Feeds[0], output.Feeds[1]
+	if a.URL != "https://a.example.com/feed" || len(a.Posts) != 2 || a.Posts[0].Title != "a1" || a.Posts[1].Title != "a2" {
+		t.Fatalf("unexpected posts under feed A: %+v", a)
+	}
+	if a.Posts[0].PublishedAt == nil || !a.Posts[0].PublishedAt.Equal(published) || a.Posts[1].PublishedAt != nil {
+		t.Fatalf("unexpected published dates under feed A: %+v", a.Posts)
+	}
+	if b.URL != "https://b.example.com/feed" || len(b.Posts) != 1 || b.Posts[0].Title != "b1" {
+		t.Fatalf("unexpected posts under feed B: %+v", b)
+	}
+}
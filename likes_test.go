@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+)
+
+func TestHandlerLike_LikeUnlikeAndList(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	post := createTestPost(t, db, feed, "Liked post", "https://a.example.com/1", time.Now())
+	s, out, _ := newTestState(db, false)
+
+	// By ID, then by URL once it's already liked
+	if err := handlerLike(s, command{name: "like", args: []string{post.ID.String()}}, alice); err != nil {
+		t.Fatalf("handlerLike returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Successfully liked post "+post.ID.String()) {
+		t.Fatalf("expected a confirmation, got %q", out.String())
+	}
+	err := handlerLike(s, command{name: "like", args: []string{post.Url}}, alice)
+	if err == nil || !strings.Contains(err.Error(), "already liked") {
+		t.Fatalf("expected an already liked error, got %v", err)
+	}
+
+	out.Reset()
+	if err := handlerLikes(s, command{name: "likes"}, alice); err != nil {
+		t.Fatalf("handlerLikes returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1. Liked post") || !strings.Contains(out.String(), "Feed: A") {
+		t.Fatalf("expected the liked post to be listed, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := handlerUnlike(s, command{name: "unlike", args: []string{post.ID.String()}}, alice); err != nil {
+		t.Fatalf("handlerUnlike returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Successfully removed like from post") {
+		t.Fatalf("expected a confirmation, got %q", out.String())
+	}
+	err = handlerUnlike(s, command{name: "unlike", args: []string{post.ID.String()}}, alice)
+	if err == nil || !strings.Contains(err.Error(), "is not liked") {
+		t.Fatalf("expected a not liked error, got %v", err)
+	}
+
+	out.Reset()
+	if err := handlerLikes(s, command{name: "likes"}, alice); err != nil {
+		t.Fatalf("handlerLikes returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No liked posts found") {
+		t.Fatalf("expected no likes after unliking, got %q", out.String())
+	}
+}
+
+func TestHandlerLike_UnknownPost(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	s, _, _ := newTestState(db, false)
+
+	for _, handler := range []func(*state, command, database.User) error{handlerLike, handlerUnlike} {
+		err := handler(s, command{args: []string{"https://missing.example.com/post"}}, alice)
+		if err == nil || !strings.Contains(err.Error(), "post not found") {
+			t.Fatalf("expected a post not found error, got %v", err)
+		}
+	}
+}
+
+func TestHandlerLikes_Paginates(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	for i := range 6 {
+		post := createTestPost(t, db, feed, fmt.Sprintf("post %d", i), fmt.Sprintf("https://a.example.com/%d", i), time.Now())
+		likeTestPost(t, db, alice, post)
+	}
+	s, out, _ := newTestState(db, false)
+
+	if err := handlerLikes(s, command{name: "likes"}, alice); err != nil {
+		t.Fatalf("handlerLikes returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "showing 5 posts") || !strings.Contains(out.String(), "gator likes 2") {
+		t.Fatalf("expected a full first page with a hint, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := handlerLikes(s, command{name: "likes", args: []string{"2"}}, alice); err != nil {
+		t.Fatalf("handlerLikes returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "showing 1 posts") || !strings.Contains(out.String(), "6. ") {
+		t.Fatalf("expected the sixth like on page 2, got %q", out.String())
+	}
+}
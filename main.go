@@ -9,22 +9,57 @@ import (
 	"gator/internal/config"
 	"gator/internal/database"
 	"gator/internal/rss"
+	"gator/internal/search"
 	"gator/internal/tui"
+	"io"
+	"math"
 	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // state holds application state
 type state struct {
 	db  *database.Queries
 	cfg *config.Config
+
+	// conn is the underlying connection, for commands that need a transaction
+	conn *sql.DB
+
+	// out receives informational output and is silenced by --quiet.
+	// dataOut receives machine-readable output (e.g. --json) and is never silenced.
+	// errOut receives error messages.
+	out     io.Writer
+	dataOut io.Writer
+	errOut  io.Writer
+}
+
+// newState creates application state writing to the process's standard streams.
+// When quiet is set, informational output is discarded.
+func newState(db *database.Queries, cfg *config.Config, quiet bool) *state {
+	s := &state{
+		db:      db,
+		cfg:     cfg,
+		out:     os.Stdout,
+		dataOut: os.Stdout,
+		errOut:  os.Stderr,
+	}
+	if quiet {
+		s.out = io.Discard
+	}
+	return s
 }
 
 // command represents a CLI command and its arguments
@@ -40,12 +75,23 @@ type commands struct {
 	handlers map[string]func(*state, command) error
 }
 
+// errNotLoggedIn is returned by commands needing a user when none is logged in
+var errNotLoggedIn = errors.New("no user logged in; run 'gator login <name>' or 'gator register <name>'")
+
 // middlewareLoggedIn is a higher-order function that wraps handlers requiring authentication
 // It takes a handler that expects a user and returns a handler that can be registered
 func middlewareLoggedIn(handler func(s *state, cmd command, user database.User) error) func(*state, command) error {
 	return func(s *state, cmd command) error {
+		name := s.cfg.CurrentUser()
+		if name == "" {
+			return errNotLoggedIn
+		}
+
 		// Get the current user from the database
-		user, err := s.db.GetUser(context.Background(), s.cfg.CurrentUserName)
+		user, err := s.db.GetUser(context.Background(), name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("logged in as '%s', but that user no longer exists; run 'gator login <name>' or 'gator register <name>'", name)
+		}
 		if err != nil {
 			return fmt.Errorf("couldn't get current user: %w", err)
 		}
@@ -67,6 +113,15 @@ func (c *commands) run(s *state, cmd command) error {
 	return handler(s, cmd)
 }
 
+// runCommand runs a command, reporting any error to errOut, and returns the process exit code
+func runCommand(c *commands, s *state, cmd command) int {
+	if err := c.run(s, cmd); err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 // handlerLogin sets the current user in the config file
 func handlerLogin(s *state, cmd command) error {
 	if len(cmd.args) < 1 {
@@ -87,7 +142,22 @@ func handlerLogin(s *state, cmd command) error {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("User set to '%s'\n", username)
+	fmt.Fprintf(s.out, "User set to '%s'\n", username)
+	return nil
+}
+
+// handlerLogout clears the current user from the config
+func handlerLogout(s *state, cmd command) error {
+	username := s.cfg.CurrentUser()
+	if username == "" {
+		fmt.Fprintln(s.out, "No user is logged in")
+		return nil
+	}
+
+	if err := s.cfg.SetUser(""); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Logged out '%s'\n", username)
 	return nil
 }
 
@@ -97,14 +167,17 @@ func handlerRegister(s *state, cmd command) error {
 		return fmt.Errorf("register requires a username argument")
 	}
 	username := cmd.args[0]
+	if err := api.ValidateUsername(username); err != nil {
+		return fmt.Errorf("invalid username: %w", err)
+	}
 
 	// Create new user in database
 	user, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      username,
-		ApiKey:    sql.NullString{Valid: false}, // CLI users don't get API keys by default
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+		Name:       username,
+		ApiKeyHash: sql.NullString{Valid: false}, // CLI users don't get API keys by default
 	})
 	if err != nil {
 		return fmt.Errorf("couldn't create user: %w", err)
@@ -116,10 +189,10 @@ func handlerRegister(s *state, cmd command) error {
 		return err
 	}
 
-	fmt.Printf("User created successfully!\n")
-	fmt.Printf("ID: %s\n", user.ID)
-	fmt.Printf("Name: %s\n", user.Name)
-	fmt.Printf("Created: %s\n", user.CreatedAt)
+	fmt.Fprintf(s.out, "User created successfully!\n")
+	fmt.Fprintf(s.out, "ID: %s\n", user.ID)
+	fmt.Fprintf(s.out, "Name: %s\n", user.Name)
+	fmt.Fprintf(s.out, "Created: %s\n", user.CreatedAt)
 	return nil
 }
 
@@ -130,10 +203,127 @@ func handlerReset(s *state, cmd command) error {
 		return fmt.Errorf("couldn't reset users: %w", err)
 	}
 
-	fmt.Println("Database has been reset successfully!")
+	fmt.Fprintln(s.out, "Database has been reset successfully!")
+	return nil
+}
+
+// handlerResetAPIKey generates and stores a new API key for a user, defaulting to the current user
+func handlerResetAPIKey(s *state, cmd command) error {
+	username := s.cfg.CurrentUser()
+	if len(cmd.args) >= 1 {
+		username = cmd.args[0]
+	}
+	if username == "" {
+		return fmt.Errorf("reset-apikey requires a username argument when no user is logged in")
+	}
+
+	user, err := s.db.GetUser(context.Background(), username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("user '%s' not found", username)
+		}
+		return fmt.Errorf("database error while looking up user '%s': %w", username, err)
+	}
+
+	apiKey, err := api.GenerateAPIKey()
+	if err != nil {
+		return fmt.Errorf("couldn't generate API key: %w", err)
+	}
+
+	err = s.db.UpdateUserAPIKey(context.Background(), database.UpdateUserAPIKeyParams{
+		ID:         user.ID,
+		ApiKeyHash: sql.NullString{String: api.HashAPIKey(apiKey), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't update API key: %w", err)
+	}
+
+	// The key is only shown once; the previous key stops working immediately
+	fmt.Fprintf(s.out, "New API key for '%s' (store it now, it won't be shown again):\n", user.Name)
+	fmt.Fprintf(s.dataOut, "%s\n", apiKey)
+	return nil
+}
+
+// handlerConfig dispatches `config` subcommands
+func handlerConfig(s *state, cmd command) error {
+	if len(cmd.args) < 1 || cmd.args[0] != "edit" {
+		return fmt.Errorf("config requires a subcommand: edit")
+	}
+
+	if err := config.Edit(runEditor); err != nil {
+		return err
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Saved %s\n", path)
+	return nil
+}
+
+// handlerProfile switches the config to another profile, or lists the
+// profiles when no name is given
+func handlerProfile(s *state, cmd command) error {
+	if len(cmd.args) < 1 {
+		active := s.cfg.ActiveProfileName()
+		for _, name := range s.cfg.ProfileNames() {
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			fmt.Fprintf(s.out, "%s %s\n", marker, name)
+		}
+		return nil
+	}
+
+	name := cmd.args[0]
+	if err := s.cfg.SetActiveProfile(name); err != nil {
+		return err
+	}
+	if user := s.cfg.CurrentUser(); user != "" {
+		fmt.Fprintf(s.out, "Switched to profile %s (logged in as %s)\n", name, user)
+	} else {
+		fmt.Fprintf(s.out, "Switched to profile %s\n", name)
+	}
 	return nil
 }
 
+// currentUserWarning explains why the configured user probably can't be found,
+// or returns "" if the name looks usable. The user still has to exist in the
+// database; that is checked when a command needs it.
+func currentUserWarning(name string) string {
+	if name == "" {
+		return ""
+	}
+	if err := api.ValidateUsername(name); err != nil {
+		return fmt.Sprintf("current_user_name %q can't be registered (%v); run `gator login <name>` if commands can't find your user", name, err)
+	}
+	return ""
+}
+
+// runEditor opens path in $EDITOR, falling back to vi or nano
+func runEditor(path string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		for _, fallback := range []string{"vi", "nano"} {
+			if _, err := exec.LookPath(fallback); err == nil {
+				editor = []string{fallback}
+				break
+			}
+		}
+	}
+	if len(editor) == 0 {
+		return fmt.Errorf("no editor found; set the EDITOR environment variable")
+	}
+
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
 // handlerUsers lists all users from the database
 func handlerUsers(s *state, cmd command) error {
 	users, err := s.db.GetUsers(context.Background())
@@ -142,57 +332,251 @@ func handlerUsers(s *state, cmd command) error {
 	}
 
 	for _, user := range users {
-		if user.Name == s.cfg.CurrentUserName {
-			fmt.Printf("* %s (current)\n", user.Name)
+		if user.Name == s.cfg.CurrentUser() {
+			fmt.Fprintf(s.out, "* %s (current)\n", user.Name)
 		} else {
-			fmt.Printf("* %s\n", user.Name)
+			fmt.Fprintf(s.out, "* %s\n", user.Name)
 		}
 	}
 
 	return nil
 }
 
-// handlerAgg fetches a single feed and prints the entire struct to the console
-func handlerAgg(s *state, cmd command) error {
-	// If user asks to aggregate all feeds: `agg all [workers]`
-	if len(cmd.args) >= 1 && cmd.args[0] == "all" {
-		// Determine worker concurrency (optional second argument)
-		workers := 5 // default
-		if len(cmd.args) >= 2 {
-			if w, err := strconv.Atoi(cmd.args[1]); err == nil && w > 0 {
-				workers = w
-			}
+// defaultAggWorkers is the `agg all` worker count when neither --workers nor
+// agg_workers in the config is set
+const defaultAggWorkers = 5
+
+// aggWorkers determines the worker count for `agg all`. The --workers flag (or,
+// for compatibility, a bare number) wins over agg_workers from the config.
+func aggWorkers(args []string, cfg *config.Config) (int, error) {
+	value, rest, err := flagValue(args, "--workers")
+	if err != nil {
+		return 0, err
+	}
+	if value == "" && len(rest) >= 1 {
+		value = rest[0]
+	}
+	if value != "" {
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 1 {
+			return 0, fmt.Errorf("workers must be a positive number, got: %s", value)
 		}
+		return w, nil
+	}
+
+	if w := cfg.DefaultAggWorkers(); w > 0 {
+		return w, nil
+	}
+	return defaultAggWorkers, nil
+}
+
+// fetchFeed fetches a feed with the user_agent from the config, if one is set
+func (s *state) fetchFeed(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+	return rss.FetchFeedWithOptions(ctx, client, url, rss.FetchOptions{UserAgent: s.cfg.FetchUserAgent()})
+}
+
+// discoverFeedURL finds the feed a page advertises, sending the user_agent
+// from the config, if one is set
+func (s *state) discoverFeedURL(ctx context.Context, client *http.Client, pageURL string) (string, error) {
+	return rss.DiscoverFeedURL(ctx, client, pageURL, rss.FetchOptions{UserAgent: s.cfg.FetchUserAgent()})
+}
+
+// newAggregationConfig builds the config `agg` uses against the database.
+// The workers share one set of prepared statements, which Close releases.
+func newAggregationConfig(s *state, workers int) AggregationConfig {
+	db := s.db
+	var statements *database.StmtCache
+	if s.conn != nil {
+		statements = database.NewStmtCache(s.conn)
+		db = database.New(statements)
+	}
+	config := AggregationConfig{
+		Workers: workers,
+		// Size the idle connection pool so workers reuse connections
+		Client:      rss.NewHTTPClient(rss.WithWorkers(workers)),
+		DB:          db,
+		Statements:  statements,
+		Retries:     defaultFetchRetries,
+		Health:      dbFeedHealth{db: db},
+		Fetches:     dbFeedFetches{db: db},
+		Moves:       dbFeedMoves{db: db},
+		Fetch:       s.fetchFeed,
+		Discover:    s.discoverFeedURL,
+		Credentials: dbFeedCredentials{db: db},
+		// Created once so a failing host stays skipped across the runs of
+		// `agg <interval>`
+		Breaker: newHostBreaker(),
+		PerHost: defaultPerHostFetches,
+		Hosts:   rss.NewHostLimiter(defaultPerHostFetches),
+	}
+	if command := s.cfg.NewPostCommand(); command != "" {
+		config.Hook = newPostHook(command, s.errOut)
+	}
+	return config
+}
 
-		// Fetch all feeds from database
-		feeds, err := s.db.GetFeedsWithUsers(context.Background())
+// aggregateEvery aggregates every feed from source immediately and then once
+// per interval until ctx is cancelled, printing a summary line per run. A
+// failed run is reported and the loop carries on.
+func aggregateEvery(ctx context.Context, s *state, interval time.Duration, source feedSource, config AggregationConfig) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := aggregateFeedsBatched(ctx, source, aggBatchSize, config)
+		if ctx.Err() != nil {
+			fmt.Fprintln(s.out, "Stopped aggregating.")
+			return nil
+		}
+		stamp := time.Now().Format("15:04:05")
 		if err != nil {
-			return fmt.Errorf("couldn't retrieve feeds: %w", err)
+			fmt.Fprintf(s.errOut, "[%s] Aggregation failed: %v\n", stamp, err)
+		} else {
+			fmt.Fprintf(s.out, "[%s] Aggregated %d feeds, ~%d posts (%d fetch errors, %d save errors, %d timed out, %d skipped)\n",
+				stamp, result.FeedsProcessed, result.TotalPosts, result.FetchErrors, result.SaveErrors, result.Timeouts, result.Skipped)
 		}
 
-		if len(feeds) == 0 {
-			fmt.Println("No feeds found to aggregate.")
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(s.out, "Stopped aggregating.")
 			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// defaultAggMaxDuration bounds an `agg all` run unless --max-duration is given
+const defaultAggMaxDuration = 5 * time.Minute
+
+// aggMaxDuration parses --max-duration (a Go duration such as 90s or 10m)
+// from args, returning the default when it's absent
+func aggMaxDuration(args []string) (time.Duration, []string, error) {
+	value, rest, err := flagValue(args, "--max-duration")
+	if err != nil {
+		return 0, nil, err
+	}
+	if value == "" {
+		return defaultAggMaxDuration, rest, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, nil, fmt.Errorf("--max-duration must be a positive duration like 90s or 10m, got: %s", value)
+	}
+	return d, rest, nil
+}
+
+// aggregateAll aggregates every feed from source within maxDuration and
+// prints a summary. total is the number of feeds expected, used to report
+// progress when the deadline cuts the run short. In strict mode a truncated
+// run is returned as an error.
+func aggregateAll(s *state, source feedSource, total int, config AggregationConfig, maxDuration time.Duration, strict bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), maxDuration)
+	defer cancel()
+
+	start := time.Now()
+	result, err := aggregateFeedsBatched(ctx, source, aggBatchSize, config)
+	stoppedEarly := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if err != nil && !stoppedEarly {
+		return fmt.Errorf("couldn't retrieve feeds: %w", err)
+	}
+	if result.FeedsSeen == 0 && !stoppedEarly {
+		fmt.Fprintln(s.out, "No feeds found to aggregate.")
+		return nil
+	}
+
+	if stoppedEarly {
+		fmt.Fprintf(s.out, "Aggregation stopped early after %s (deadline reached); processed %d/%d feeds. Processed ~%d posts.\n",
+			time.Since(start).Round(time.Second), result.FeedsProcessed, total, result.TotalPosts)
+	} else {
+		fmt.Fprintf(s.out, "Finished aggregating %d feeds. Processed ~%d posts.\n", result.FeedsSeen, result.TotalPosts)
+	}
+	if result.FetchErrors > 0 || result.SaveErrors > 0 {
+		fmt.Fprintf(s.out, "Errors: %d fetch failures, %d save failures\n", result.FetchErrors, result.SaveErrors)
+	}
+	if result.Timeouts > 0 {
+		fmt.Fprintf(s.out, "Timed out on %d feeds\n", result.Timeouts)
+	}
+	if result.Skipped > 0 {
+		fmt.Fprintf(s.out, "Skipped %d feeds on unreachable hosts\n", result.Skipped)
+	}
+	if result.PossiblyMoved > 0 {
+		fmt.Fprintf(s.out, "%d feeds returned no items but previously had posts; run `gator doctor` for details\n", result.PossiblyMoved)
+	}
+	if result.Moved > 0 {
+		fmt.Fprintf(s.out, "Updated the URL of %d feeds that moved permanently\n", result.Moved)
+	}
+	printFailedFeeds(s.out, result.PerFeed)
+
+	if stoppedEarly && strict {
+		return fmt.Errorf("aggregation did not finish within %s", maxDuration)
+	}
+	return nil
+}
+
+// printFailedFeeds lists the feeds that failed to aggregate, sorted by URL
+func printFailedFeeds(w io.Writer, results []FeedResult) {
+	var failed []FeedResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
 		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+
+	sort.Slice(failed, func(i, j int) bool { return failed[i].URL < failed[j].URL })
+	fmt.Fprintf(w, "Failed feeds:\n")
+	for _, r := range failed {
+		fmt.Fprintf(w, "* %s: %v\n", r.URL, r.Err)
+	}
+}
 
-		// Create context with timeout for the aggregation operation
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
+// handlerAgg aggregates feeds: `agg all` once, `agg <interval>` continuously,
+// or `agg <url>` to fetch a single feed and print the entire struct to the console
+func handlerAgg(s *state, cmd command) error {
+	// If user asks to aggregate all feeds: `agg all [--workers N] [--max-duration D] [--strict]`
+	if len(cmd.args) >= 1 && cmd.args[0] == "all" {
+		strict, args := hasFlag(cmd.args[1:], "--strict")
+		maxDuration, args, err := aggMaxDuration(args)
+		if err != nil {
+			return err
+		}
+		workers, err := aggWorkers(args, s.cfg)
+		if err != nil {
+			return err
+		}
 
-		client := rss.NewHTTPClient()
+		config := newAggregationConfig(s, workers)
+		defer config.Close()
 
-		config := AggregationConfig{
-			Workers: workers,
-			Client:  client,
-			DB:      s.db,
+		// Feeds backing off after repeated failures aren't part of this run
+		total, err := s.db.CountFeedsDue(context.Background())
+		if err != nil {
+			return fmt.Errorf("couldn't count feeds: %w", err)
 		}
 
-		result := aggregateFeeds(ctx, feeds, config)
-		fmt.Printf("Finished aggregating %d feeds. Processed ~%d posts.\n", len(feeds), result.TotalPosts)
-		if result.FetchErrors > 0 || result.SaveErrors > 0 {
-			fmt.Printf("Errors: %d fetch failures, %d save failures\n", result.FetchErrors, result.SaveErrors)
+		// Feeds are loaded in batches so memory use doesn't grow with the feed count
+		return aggregateAll(s, dbFeedSource(s.db), int(total), config, maxDuration, strict)
+	}
+
+	// `agg <interval> [--workers N]` aggregates all feeds on a ticker until interrupted
+	if len(cmd.args) >= 1 {
+		if interval, err := time.ParseDuration(cmd.args[0]); err == nil {
+			if interval <= 0 {
+				return fmt.Errorf("agg interval must be positive, got: %s", cmd.args[0])
+			}
+			workers, err := aggWorkers(cmd.args[1:], s.cfg)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			fmt.Fprintf(s.out, "Aggregating all feeds every %s (Ctrl+C to stop)\n", interval)
+			config := newAggregationConfig(s, workers)
+			defer config.Close()
+			return aggregateEvery(ctx, s, interval, dbFeedSource(s.db), config)
 		}
-		return nil
 	}
 
 	// Otherwise, fetch a single feed. Prefer explicit URL arg, then FEED_URL env.
@@ -214,117 +598,208 @@ func handlerAgg(s *state, cmd command) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	feed, err := rss.FetchFeed(ctx, client, feedURL)
+	feed, err := s.fetchFeed(ctx, client, feedURL)
 	if err != nil {
 		return fmt.Errorf("couldn't fetch feed: %w", err)
 	}
 
 	// Print the entire struct to the console
-	fmt.Printf("%+v\n", feed)
+	fmt.Fprintf(s.out, "%+v\n", feed)
 	return nil
 }
 
 // handlerAddFeed creates a new feed for the current user
 func handlerAddFeed(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 2 {
-		return fmt.Errorf("addfeed requires name and url arguments")
+	cred, private, args, err := parseCredentialFlags(cmd.args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("addfeed requires a url argument, optionally preceded by a name")
+	}
+	// Without a name the feed is named after its URL until its title is known
+	name, rawURL := args[0], args[0]
+	named := len(args) >= 2
+	if named {
+		rawURL = args[1]
+	}
+	client := rss.NewHTTPClient()
+	// Requests for a private feed carry its credentials
+	baseCtx := context.Background()
+	if private {
+		baseCtx = rss.WithBasicAuth(baseCtx, basicAuth(cred))
 	}
-	name := cmd.args[0]
-	url := cmd.args[1]
 
-	// Create new feed in database
-	feed, err := s.db.CreateFeed(context.Background(), database.CreateFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      name,
-		Url:       url,
-		UserID:    user.ID,
-	})
+	// Resolve the URL the user pasted (possibly a homepage) to a feed URL, and
+	// follow the existing feed if it's already stored under either form
+	discoverCtx, discoverCancel := context.WithTimeout(baseCtx, 30*time.Second)
+	defer discoverCancel()
+	discover := func(ctx context.Context, pageURL string) (string, error) {
+		return s.discoverFeedURL(ctx, client, pageURL)
+	}
+	url, existing, err := resolveFeedForAdd(discoverCtx, s.db, discover, rawURL)
 	if err != nil {
-		return fmt.Errorf("couldn't create feed: %w", err)
+		return err
+	}
+	if existing != nil {
+		if private {
+			if err := saveOwnFeedCredentials(s, user, *existing, cred); err != nil {
+				return err
+			}
+		}
+		return followExistingFeed(s, user, *existing)
 	}
 
-	// Automatically create a feed follow record for the current user
-	feedFollow, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		UserID:    user.ID,
-		FeedID:    feed.ID,
-	})
+	// Create the feed (or pick up one added concurrently) and follow it atomically
+	feed, created, err := addAndFollowFeed(context.Background(), s.conn, user, name, url)
 	if err != nil {
-		return fmt.Errorf("couldn't create feed follow: %w", err)
+		return err
+	}
+	if private {
+		// The feed may have been added concurrently by another user
+		if err := saveOwnFeedCredentials(s, user, feed, cred); err != nil {
+			return err
+		}
+	}
+	if !created {
+		fmt.Fprintf(s.out, "Feed already exists as %s (%s)\n", feed.Name, feed.Url)
+		fmt.Fprintf(s.out, "Now following %s as %s\n", feed.Name, user.Name)
+		return nil
 	}
 
 	// Print the fields of the new feed record
-	fmt.Printf("Feed created successfully!\n")
-	fmt.Printf("ID: %s\n", feed.ID)
-	fmt.Printf("Name: %s\n", feed.Name)
-	fmt.Printf("URL: %s\n", feed.Url)
-	fmt.Printf("User ID: %s\n", feed.UserID)
-	fmt.Printf("Created: %s\n", feed.CreatedAt)
-	fmt.Printf("Updated: %s\n", feed.UpdatedAt)
-	fmt.Printf("Now following %s as %s\n", feedFollow.FeedName, feedFollow.UserName)
+	fmt.Fprintf(s.out, "Feed created successfully!\n")
+	fmt.Fprintf(s.out, "ID: %s\n", feed.ID)
+	fmt.Fprintf(s.out, "Name: %s\n", feed.Name)
+	fmt.Fprintf(s.out, "URL: %s\n", feed.Url)
+	fmt.Fprintf(s.out, "User ID: %s\n", feed.UserID)
+	fmt.Fprintf(s.out, "Created: %s\n", feed.CreatedAt)
+	fmt.Fprintf(s.out, "Updated: %s\n", feed.UpdatedAt)
+	fmt.Fprintf(s.out, "Now following %s as %s\n", feed.Name, user.Name)
 
 	// Fetch and save posts from the feed
-	fmt.Printf("Fetching posts from %s...\n", feed.Name)
-	client := rss.NewHTTPClient()
+	fmt.Fprintf(s.out, "Fetching posts from %s...\n", feed.Name)
 
 	// Create context with timeout for feed fetching
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(baseCtx, 30*time.Second)
 	defer cancel()
 
-	rssFeed, err := rss.FetchFeed(ctx, client, url)
+	rssFeed, err := s.fetchFeed(ctx, client, url)
 	if err != nil {
 		return fmt.Errorf("couldn't fetch RSS feed: %w", err)
 	}
 
-	err = rss.SavePostsToDatabase(ctx, s.db, rssFeed, feed.ID)
+	feed, err = rss.SaveFeedMetadata(ctx, s.db, feed, rssFeed, named)
+	if err != nil {
+		return fmt.Errorf("couldn't save feed details: %w", err)
+	}
+	if !named {
+		fmt.Fprintf(s.out, "Named feed %s\n", feed.Name)
+	}
+
+	_, err = rss.SavePostsToDatabase(ctx, s.db, rssFeed, feed.ID)
 	if err != nil {
 		return fmt.Errorf("couldn't save posts to database: %w", err)
 	}
 
-	fmt.Printf("Successfully processed %d posts from %s\n", len(rssFeed.Channel.Items), feed.Name)
+	fmt.Fprintf(s.out, "Successfully processed %d posts from %s\n", len(rssFeed.Channel.Items), feed.Name)
 	return nil
 }
 
-// handlerFeeds lists all feeds in the database with their associated user names
-func handlerFeeds(s *state, cmd command) error {
-	feeds, err := s.db.GetFeedsWithUsers(context.Background())
+// addAndFollowFeed creates the feed at url (or gets the one already stored
+// there) and makes user follow it, in a single transaction. Concurrent adds of
+// the same URL all end with one feed followed by every caller. It reports
+// whether this call created the feed.
+func addAndFollowFeed(ctx context.Context, conn *sql.DB, user database.User, name, url string) (database.Feed, bool, error) {
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("couldn't retrieve feeds: %w", err)
+		return database.Feed{}, false, fmt.Errorf("couldn't start transaction: %w", err)
 	}
+	defer tx.Rollback()
+	q := database.New(tx)
 
-	if len(feeds) == 0 {
-		fmt.Println("No feeds found in the database.")
-		return nil
+	now := time.Now().UTC()
+	row, err := q.CreateOrGetFeed(ctx, database.CreateOrGetFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      name,
+		Url:       url,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		return database.Feed{}, false, fmt.Errorf("couldn't create feed: %w", err)
 	}
 
-	for _, feed := range feeds {
-		fmt.Printf("* %s (%s) - %s\n", feed.Name, feed.UserName, feed.Url)
+	if _, err := q.FollowFeedIfNotFollowing(ctx, database.FollowFeedIfNotFollowingParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		FeedID:    row.ID,
+	}); err != nil {
+		return database.Feed{}, false, fmt.Errorf("couldn't create feed follow: %w", err)
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return database.Feed{}, false, fmt.Errorf("couldn't commit feed: %w", err)
+	}
+
+	feed := database.Feed{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		Name:        row.Name,
+		Url:         row.Url,
+		UserID:      row.UserID,
+		Description: row.Description,
+	}
+	return feed, row.Inserted, nil
 }
 
-// handlerFollow creates a new feed follow record for the current user
-func handlerFollow(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 1 {
-		return fmt.Errorf("follow requires a url argument")
+// resolveFeedForAdd normalizes rawURL and runs feed discovery on it. It returns
+// the feed URL to store and, if the feed is already stored under the pasted,
+// normalized, or discovered URL, that existing feed.
+func resolveFeedForAdd(ctx context.Context, db *database.Queries, discover func(context.Context, string) (string, error), rawURL string) (string, *database.Feed, error) {
+	feedURL := rss.NormalizeURL(rawURL)
+	existing, err := findFeedByURL(ctx, db, rawURL, feedURL)
+	if err != nil || existing != nil {
+		return feedURL, existing, err
 	}
-	url := cmd.args[0]
 
-	// Look up the feed by URL
-	feed, err := s.db.GetFeedByURL(context.Background(), url)
+	discovered, err := discover(ctx, feedURL)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("feed not found with URL: %s", url)
+		if errors.Is(err, rss.ErrNoFeedFound) {
+			return "", nil, err
 		}
-		return fmt.Errorf("database error while looking up feed with URL %s: %w", url, err)
+		// Discovery is best-effort; fall back to the URL as given
+		return feedURL, nil, nil
 	}
 
-	// Create new feed follow record
+	discovered = rss.NormalizeURL(discovered)
+	if discovered == feedURL {
+		return feedURL, nil, nil
+	}
+	existing, err = findFeedByURL(ctx, db, discovered)
+	return discovered, existing, err
+}
+
+// findFeedByURL returns the first feed stored under any of the given URLs, or nil if none is
+func findFeedByURL(ctx context.Context, db *database.Queries, urls ...string) (*database.Feed, error) {
+	for _, u := range urls {
+		feed, err := rss.LookupFeed(ctx, db, u)
+		if err == nil {
+			return &feed, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("database error while looking up feed with URL %s: %w", u, err)
+		}
+	}
+	return nil, nil
+}
+
+// followExistingFeed follows a feed that already exists instead of creating a duplicate
+func followExistingFeed(s *state, user database.User, feed database.Feed) error {
 	feedFollow, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now().UTC(),
@@ -333,43 +808,285 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 		FeedID:    feed.ID,
 	})
 	if err != nil {
+		if isUniqueViolation(err) {
+			fmt.Fprintf(s.out, "Feed already exists as %s (%s) and you're already following it\n", feed.Name, feed.Url)
+			return nil
+		}
 		return fmt.Errorf("couldn't create feed follow: %w", err)
 	}
 
-	// Print the feed name and current user
-	fmt.Printf("Now following %s as %s\n", feedFollow.FeedName, feedFollow.UserName)
+	fmt.Fprintf(s.out, "Feed already exists as %s (%s)\n", feed.Name, feed.Url)
+	fmt.Fprintf(s.out, "Now following %s as %s\n", feedFollow.FeedName, feedFollow.UserName)
 	return nil
 }
 
-// handlerFollowing lists all feeds the current user is following
-func handlerFollowing(s *state, cmd command, user database.User) error {
-	// Get all feed follows for the user
-	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.ID)
+// exportPostsPerFeed caps how many of each feed's newest posts `export` includes
+const exportPostsPerFeed = 50
+
+// handlerExport writes the current user's followed feeds and their recent posts.
+// Only --format json is supported.
+func handlerExport(s *state, cmd command, user database.User) error {
+	format, _, err := flagValue(cmd.args, "--format")
 	if err != nil {
-		return fmt.Errorf("couldn't retrieve feed follows: %w", err)
+		return err
+	}
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" {
+		return fmt.Errorf("unsupported export format %q (supported: json)", format)
 	}
 
-	if len(feedFollows) == 0 {
-		fmt.Printf("You're not following any feeds yet.\n")
-		return nil
+	ctx := context.Background()
+	follows, err := s.db.GetFeedFollowsForUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve feed follows: %w", err)
 	}
 
-	fmt.Printf("You're following %d feeds:\n", len(feedFollows))
-	for _, follow := range feedFollows {
-		fmt.Printf("* %s\n", follow.FeedName)
+	postsByFeed := make(map[uuid.UUID][]database.Post, len(follows))
+	for _, follow := range follows {
+		posts, err := s.db.GetPostsForFeed(ctx, database.GetPostsForFeedParams{
+			FeedID: follow.FeedID,
+			Limit:  exportPostsPerFeed,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't retrieve posts for %s: %w", follow.FeedUrl, err)
+		}
+		postsByFeed[follow.FeedID] = posts
 	}
 
-	return nil
+	return writeExportJSON(s.dataOut, user.Name, follows, postsByFeed)
 }
 
-// handlerUnfollow removes a feed follow record for the current user
-func handlerUnfollow(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 1 {
-		return fmt.Errorf("unfollow requires a url argument")
+// handlerFeeds lists all feeds in the database with their associated user names.
+// `feeds move <url> <username>` transfers a feed to another user.
+func handlerFeeds(s *state, cmd command) error {
+	if len(cmd.args) >= 1 && cmd.args[0] == "move" {
+		if len(cmd.args) < 3 {
+			return fmt.Errorf("feeds move requires url and username arguments")
+		}
+		feed, err := moveFeed(context.Background(), s.db, cmd.args[1], cmd.args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Moved %s (%s) to %s\n", feed.Name, feed.Url, cmd.args[2])
+		return nil
 	}
-	url := cmd.args[0]
 
-	// Delete the feed follow record
+	if unfollowed, _ := hasFlag(cmd.args, "--unfollowed"); unfollowed {
+		return middlewareLoggedIn(handlerFeedsUnfollowed)(s, cmd)
+	}
+
+	if failing, _ := hasFlag(cmd.args, "--failing"); failing {
+		return handlerFeedsFailing(s)
+	}
+
+	asJSON, _ := hasFlag(cmd.args, "--json")
+
+	feeds, err := s.db.GetFeedsWithUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve feeds: %w", err)
+	}
+
+	if asJSON {
+		return writeFeedsJSON(s.dataOut, feeds)
+	}
+
+	if len(feeds) == 0 {
+		fmt.Fprintln(s.out, "No feeds found in the database.")
+		return nil
+	}
+
+	for _, feed := range feeds {
+		fmt.Fprintf(s.out, "* %s (%s) - %s\n", feed.Name, feed.UserName, feed.Url)
+		if feed.Description.Valid {
+			fmt.Fprintf(s.out, "  %s\n", truncateDescription(feed.Description.String))
+		}
+	}
+
+	return nil
+}
+
+// handlerFeedsUnfollowed lists feeds the current user could follow
+func handlerFeedsUnfollowed(s *state, cmd command, user database.User) error {
+	feeds, err := s.db.GetUnfollowedFeeds(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve feeds: %w", err)
+	}
+
+	if len(feeds) == 0 {
+		fmt.Fprintln(s.out, "You're already following every feed.")
+		return nil
+	}
+
+	for _, feed := range feeds {
+		fmt.Fprintf(s.out, "* %s (%s) - %s - %d followers\n", feed.Name, feed.OwnerName, feed.Url, feed.FollowerCount)
+	}
+	fmt.Fprintln(s.out, "\nFollow one with: gator follow <url>")
+	return nil
+}
+
+// moveFeed transfers ownership of the feed at feedURL to the named user.
+// Posts and follows reference the feed, not its owner, so they are unaffected.
+func moveFeed(ctx context.Context, db *database.Queries, feedURL, username string) (database.Feed, error) {
+	feed, err := findFeedByURL(ctx, db, feedURL, rss.NormalizeURL(feedURL))
+	if err != nil {
+		return database.Feed{}, err
+	}
+	if feed == nil {
+		return database.Feed{}, fmt.Errorf("feed not found with URL: %s", feedURL)
+	}
+
+	user, err := db.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Feed{}, fmt.Errorf("user %s does not exist", username)
+		}
+		return database.Feed{}, fmt.Errorf("database error while looking up user: %w", err)
+	}
+
+	moved, err := db.UpdateFeedOwner(ctx, database.UpdateFeedOwnerParams{
+		ID:        feed.ID,
+		UserID:    user.ID,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("couldn't move feed: %w", err)
+	}
+	return moved, nil
+}
+
+// handlerFollow creates a new feed follow record for the current user
+func handlerFollow(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("follow requires a url argument")
+	}
+	url := cmd.args[0]
+
+	// Look up the feed by URL, however it's spelled
+	feed, err := findFeedByURL(context.Background(), s.db, url)
+	if err != nil {
+		return err
+	}
+	if feed == nil {
+		return fmt.Errorf("feed not found with URL: %s", url)
+	}
+
+	// Create new feed follow record
+	feedFollow, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't create feed follow: %w", err)
+	}
+
+	// Print the feed name and current user
+	fmt.Fprintf(s.out, "Now following %s as %s\n", feedFollow.FeedName, feedFollow.UserName)
+	return nil
+}
+
+// handlerFollowing lists all feeds the current user is following.
+// `following export` prints a share code and `following import <code>` follows its feeds.
+func handlerFollowing(s *state, cmd command, user database.User) error {
+	if len(cmd.args) >= 1 {
+		switch cmd.args[0] {
+		case "export":
+			return handlerFollowingExport(s, user)
+		case "import":
+			if len(cmd.args) < 2 {
+				return fmt.Errorf("following import requires a share code argument")
+			}
+			return handlerFollowingImport(s, user, cmd.args[1])
+		}
+	}
+
+	counts, _ := hasFlag(cmd.args, "--counts")
+	if counts {
+		return handlerFollowingCounts(s, user)
+	}
+
+	// Get all feed follows for the user
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve feed follows: %w", err)
+	}
+
+	if len(feedFollows) == 0 {
+		fmt.Fprintf(s.out, "You're not following any feeds yet.\n")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "You're following %d feeds:\n", len(feedFollows))
+	for _, follow := range feedFollows {
+		fmt.Fprintf(s.out, "* %s\n", follow.FeedName)
+		if follow.FeedDescription.Valid {
+			fmt.Fprintf(s.out, "  %s\n", truncateDescription(follow.FeedDescription.String))
+		}
+	}
+
+	return nil
+}
+
+// handlerFollowingCounts lists followed feeds with their unread post counts,
+// most unread first
+func handlerFollowingCounts(s *state, user database.User) error {
+	feeds, err := s.db.CountUnreadPerFeed(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't count unread posts: %w", err)
+	}
+
+	if len(feeds) == 0 {
+		fmt.Fprintf(s.out, "You're not following any feeds yet.\n")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "You're following %d feeds:\n", len(feeds))
+	for _, feed := range feeds {
+		fmt.Fprintf(s.out, "* %s (%d unread)\n", feed.FeedName, feed.UnreadCount)
+	}
+	return nil
+}
+
+// handlerFollowingExport prints a share code listing the current user's followed feeds
+func handlerFollowingExport(s *state, user database.User) error {
+	code, count, err := exportFollowsCode(context.Background(), s.db, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// The code itself is the output, so --quiet leaves it for scripts
+	fmt.Fprintf(s.out, "Share code for %d feeds:\n", count)
+	fmt.Fprintf(s.dataOut, "%s\n", code)
+	fmt.Fprintf(s.out, "Import it with: gator following import <code>\n")
+	return nil
+}
+
+// handlerFollowingImport follows every known feed listed in a share code
+func handlerFollowingImport(s *state, user database.User, code string) error {
+	result, err := importFollowsCode(context.Background(), s.db, user.ID, code)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Followed %d feeds (%d already followed)\n", result.Followed, result.AlreadyFollowing)
+	for _, feedURL := range result.NotFound {
+		fmt.Fprintf(s.out, "Skipped unknown feed: %s\n", feedURL)
+	}
+	return nil
+}
+
+// handlerUnfollow removes a feed follow record for the current user
+func handlerUnfollow(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("unfollow requires a url argument")
+	}
+	url := cmd.args[0]
+
+	// Delete the feed follow record
 	rowsAffected, err := s.db.DeleteFeedFollowByUserAndFeedURL(context.Background(), database.DeleteFeedFollowByUserAndFeedURLParams{
 		UserID: user.ID,
 		Url:    url,
@@ -382,31 +1099,114 @@ func handlerUnfollow(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("you're not following a feed with URL: %s", url)
 	}
 
-	fmt.Printf("Successfully unfollowed feed: %s\n", url)
+	fmt.Fprintf(s.out, "Successfully unfollowed feed: %s\n", url)
+	return nil
+}
+
+// handlerDeleteFeed deletes a feed the current user owns, along with its
+// posts and everyone's follows, bookmarks, and likes of those posts
+func handlerDeleteFeed(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("deletefeed requires a url argument")
+	}
+
+	feed, err := deleteFeed(context.Background(), s.db, user, cmd.args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Deleted feed %s (%s) and all of its posts\n", feed.Name, feed.Url)
 	return nil
 }
 
+// deleteFeed deletes the feed at feedURL if user owns it
+func deleteFeed(ctx context.Context, db *database.Queries, user database.User, feedURL string) (database.Feed, error) {
+	feed, err := findFeedByURL(ctx, db, feedURL, rss.NormalizeURL(feedURL))
+	if err != nil {
+		return database.Feed{}, err
+	}
+	if feed == nil {
+		return database.Feed{}, fmt.Errorf("feed not found with URL: %s", feedURL)
+	}
+	if feed.UserID != user.ID {
+		return database.Feed{}, fmt.Errorf("only the feed's owner can delete it; %s isn't owned by %s", feed.Url, user.Name)
+	}
+
+	rows, err := db.DeleteFeedByURL(ctx, database.DeleteFeedByURLParams{Url: feed.Url, UserID: user.ID})
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("couldn't delete feed: %w", err)
+	}
+	if rows == 0 {
+		return database.Feed{}, fmt.Errorf("feed not found with URL: %s", feedURL)
+	}
+	return *feed, nil
+}
+
+// handlerRenameFeed renames a feed the current user owns, keeping its posts:
+// `renamefeed <url> <new name>`
+func handlerRenameFeed(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 2 {
+		return fmt.Errorf("renamefeed requires url and new name arguments")
+	}
+	newName := strings.TrimSpace(strings.Join(cmd.args[1:], " "))
+	if newName == "" {
+		return fmt.Errorf("renamefeed requires a non-empty new name")
+	}
+
+	oldName, feed, err := renameFeed(context.Background(), s.db, user, cmd.args[0], newName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Renamed %q to %q (%s)\n", oldName, feed.Name, feed.Url)
+	return nil
+}
+
+// renameFeed sets the name of the feed at feedURL if user owns it, returning
+// the previous name and the updated feed
+func renameFeed(ctx context.Context, db *database.Queries, user database.User, feedURL, newName string) (string, database.Feed, error) {
+	feed, err := findFeedByURL(ctx, db, feedURL, rss.NormalizeURL(feedURL))
+	if err != nil {
+		return "", database.Feed{}, err
+	}
+	if feed == nil {
+		return "", database.Feed{}, fmt.Errorf("feed not found with URL: %s", feedURL)
+	}
+	if feed.UserID != user.ID {
+		return "", database.Feed{}, fmt.Errorf("only the feed's owner can rename it; %s isn't owned by %s", feed.Url, user.Name)
+	}
+
+	updated, err := db.UpdateFeedName(ctx, database.UpdateFeedNameParams{
+		ID:        feed.ID,
+		Name:      newName,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return "", database.Feed{}, fmt.Errorf("couldn't rename feed: %w", err)
+	}
+	return feed.Name, updated, nil
+}
+
 // handlerBrowse displays posts for the current user with pagination
+// `browse --liked [page]` restricts the listing to posts the user has liked
+// `browse --feed <url-or-name> [page]` restricts it to one followed feed
+// `browse --category <name> [page]` restricts it to posts the feeds filed under name
+// `browse --sort newest|oldest|added [page]` changes the order of the listing
+// `browse --limit N [page]` shows N posts per page instead of the default
 func handlerBrowse(s *state, cmd command, user database.User) error {
-	const postsPerPage = 5 // Number of posts to show per page
-	page := int32(1)       // Default to page 1
-	if len(cmd.args) >= 1 {
-		var err error
-		page, err = parsePageArg(cmd.args[0])
-		if err != nil {
-			return err
-		}
+	opts, err := parseBrowseArgs(cmd.args)
+	if err != nil {
+		return err
 	}
+	if opts.newSinceLast {
+		return handlerBrowseNew(s, user, opts.compact)
+	}
+	page := opts.page
+	postsPerPage := pageSize(opts.limit, s.cfg)
 
 	// Calculate offset based on page number
 	offset := (page - 1) * postsPerPage
 
 	// Get posts for the user with pagination (query for one extra to check if more pages exist)
-	posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit:  postsPerPage + 1, // Query for one extra post
-		Offset: offset,
-	})
+	posts, err := fetchBrowsePosts(context.Background(), s.db, user.ID, opts.filter, postsPerPage+1, offset)
 	if err != nil {
 		return fmt.Errorf("couldn't retrieve posts: %w", err)
 	}
@@ -420,59 +1220,624 @@ func handlerBrowse(s *state, cmd command, user database.User) error {
 	}
 
 	if len(posts) == 0 {
-		if page == 1 {
-			fmt.Printf("No posts found. Try following some feeds first!\n")
+		if page == 1 && opts.filter.liked {
+			fmt.Fprintf(s.out, "No liked posts found. Try liking some posts first!\n")
+		} else if page == 1 && opts.filter.unread {
+			fmt.Fprintf(s.out, "No unread posts. You're all caught up!\n")
+		} else if page == 1 && opts.filter.tag != "" {
+			fmt.Fprintf(s.out, "No posts tagged %q. Tag posts with: gator posts tag <post> %s\n", opts.filter.tag, opts.filter.tag)
+		} else if page == 1 && opts.filter.category != "" {
+			fmt.Fprintf(s.out, "No posts in category %q from the feeds you follow.\n", opts.filter.category)
+		} else if page == 1 && opts.filter.feed != "" {
+			fmt.Fprintf(s.out, "No posts found for feed %q. Check the URL or name with: gator following\n", opts.filter.feed)
+		} else if page == 1 {
+			fmt.Fprintf(s.out, "No posts found. Try following some feeds first!\n")
 		} else {
-			fmt.Printf("No posts found on page %d. Try a lower page number.\n", page)
+			fmt.Fprintf(s.out, "No posts found on page %d. Try a lower page number.\n", page)
 		}
 		return nil
 	}
 
-	fmt.Printf("Posts (page %d, showing %d posts):\n\n", page, len(posts))
+	fmt.Fprintf(s.out, "Posts (page %d, showing %d posts):\n\n", page, len(posts))
 	for i, post := range posts {
 		// Calculate the overall post number based on page and position
-		postNumber := offset + int32(i) + 1
-		fmt.Printf("%d. %s\n", postNumber, post.Title)
-		fmt.Printf("   Post ID: %s\n", post.ID)
-		fmt.Printf("   Feed: %s\n", post.FeedName)
-		if post.Description.Valid && post.Description.String != "" {
-			// Truncate description if it's too long
-			desc := post.Description.String
-			if len(desc) > 200 {
-				desc = desc[:200] + "..."
-			}
-			fmt.Printf("   %s\n", desc)
-		}
-		if post.PublishedAt.Valid {
-			fmt.Printf("   Published: %s\n", post.PublishedAt.Time.Format("2006-01-02 15:04:05"))
+		if opts.compact {
+			printCompactPost(s.out, offset+int32(i)+1, post.Title, post.FeedName)
+		} else {
+			printBrowsePost(s, offset+int32(i)+1, post)
 		}
-		fmt.Printf("   URL: %s\n", post.Url)
-		fmt.Println()
 	}
 
 	// Show pagination info
+	browseCmd := opts.command()
 	if hasMorePages {
-		fmt.Printf("To see more posts, run: gator browse %d\n", page+1)
+		fmt.Fprintf(s.out, "To see more posts, run: %s %d\n", browseCmd, page+1)
+	}
+	if page > 1 {
+		fmt.Fprintf(s.out, "To see previous posts, run: %s %d\n", browseCmd, page-1)
+	}
+
+	return nil
+}
+
+// browseFilter narrows which of the user's posts browse shows. At most one of
+// liked, unread, tag, category, and feed is set.
+type browseFilter struct {
+	liked  bool
+	unread bool
+	tag    string
+	// category matches a category the feed gave the post, ignoring case
+	category string
+	// feed matches a followed feed's URL or exact name
+	feed string
+	// sort orders the unfiltered listing; empty means browseSortNewest
+	sort string
+}
+
+// Orders accepted by `browse --sort`
+const (
+	browseSortNewest = "newest" // publication date, newest first
+	browseSortOldest = "oldest" // publication date, oldest first
+	browseSortAdded  = "added"  // when gator stored the post, most recent first
+)
+
+// browseOptions are the parsed arguments of `browse`
+type browseOptions struct {
+	filter       browseFilter
+	newSinceLast bool
+	compact      bool
+	page         int32
+	// limit is the page size from --limit, or 0 to use the default
+	limit int32
+}
+
+// parseBrowseArgs parses browse's flags and optional page number. Flags may
+// appear before or after the page number.
+func parseBrowseArgs(args []string) (browseOptions, error) {
+	opts := browseOptions{page: 1}
+	var err error
+	opts.filter.liked, args = hasFlag(args, "--liked")
+	opts.newSinceLast, args = hasFlag(args, "--new-since-last")
+	opts.compact, args = hasFlag(args, "--compact")
+	opts.filter.unread, args = hasFlag(args, "--unread")
+	opts.filter.tag, args, err = flagValue(args, "--tag")
+	if err != nil {
+		return browseOptions{}, err
+	}
+	opts.filter.category, args, err = flagValue(args, "--category")
+	if err != nil {
+		return browseOptions{}, err
+	}
+	opts.filter.feed, args, err = flagValue(args, "--feed")
+	if err != nil {
+		return browseOptions{}, err
+	}
+	opts.filter.sort, args, err = flagValue(args, "--sort")
+	if err != nil {
+		return browseOptions{}, err
+	}
+	opts.limit, args, err = parseLimitFlag(args)
+	if err != nil {
+		return browseOptions{}, err
+	}
+	switch opts.filter.sort {
+	case "", browseSortNewest, browseSortOldest, browseSortAdded:
+	default:
+		return browseOptions{}, fmt.Errorf("unknown sort %q: use %s, %s, or %s", opts.filter.sort, browseSortNewest, browseSortOldest, browseSortAdded)
+	}
+
+	filters := 0
+	for _, set := range []bool{opts.filter.liked, opts.filter.unread, opts.filter.tag != "", opts.filter.category != "", opts.filter.feed != ""} {
+		if set {
+			filters++
+		}
+	}
+	if filters > 1 {
+		return browseOptions{}, fmt.Errorf("only one of --liked, --unread, --tag, --category, and --feed can be used at a time")
+	}
+	if filters > 0 && opts.filter.sort != "" && opts.filter.sort != browseSortNewest {
+		return browseOptions{}, fmt.Errorf("--sort %s can't be combined with --liked, --unread, --tag, --category, or --feed", opts.filter.sort)
+	}
+
+	if len(args) >= 1 && args[0] == "new" {
+		opts.newSinceLast, args = true, args[1:]
+	}
+	if opts.newSinceLast {
+		if filters > 0 || opts.filter.sort != "" {
+			return browseOptions{}, fmt.Errorf("--liked, --unread, --tag, --category, --feed, and --sort can't be combined with --new-since-last")
+		}
+		return opts, nil
+	}
+
+	if len(args) >= 1 {
+		opts.page, err = parsePageArg(args[0])
+		if err != nil {
+			return browseOptions{}, err
+		}
+	}
+	return opts, nil
+}
+
+// command rebuilds the browse invocation for these options, without the page,
+// for pagination hints
+func (o browseOptions) command() string {
+	browseCmd := "gator browse"
+	if o.filter.liked {
+		browseCmd += " --liked"
+	}
+	if o.filter.unread {
+		browseCmd += " --unread"
+	}
+	if o.filter.tag != "" {
+		browseCmd += " --tag " + o.filter.tag
+	}
+	if o.filter.category != "" {
+		browseCmd += " --category " + strconv.Quote(o.filter.category)
+	}
+	if o.filter.feed != "" {
+		browseCmd += " --feed " + strconv.Quote(o.filter.feed)
+	}
+	if o.filter.sort != "" {
+		browseCmd += " --sort " + o.filter.sort
+	}
+	if o.compact {
+		browseCmd += " --compact"
+	}
+	if o.limit > 0 {
+		browseCmd += fmt.Sprintf(" --limit %d", o.limit)
+	}
+	return browseCmd
+}
+
+// maxNewPosts caps how many posts `browse --new-since-last` shows at once
+const maxNewPosts = 100
+
+// handlerBrowseNew shows posts stored since the user last ran it, then
+// advances the user's last-browsed timestamp
+func handlerBrowseNew(s *state, user database.User, compact bool) error {
+	posts, since, err := fetchNewPosts(context.Background(), s.db, user.ID, time.Now().UTC(), maxNewPosts)
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 && since.IsZero() {
+		fmt.Fprintf(s.out, "No posts found. Try following some feeds first!\n")
+		return nil
+	}
+	if len(posts) == 0 {
+		fmt.Fprintf(s.out, "No new posts since %s.\n", since.Format("2006-01-02 15:04:05"))
+		return nil
+	}
+
+	if since.IsZero() {
+		fmt.Fprintf(s.out, "New posts (%d):\n\n", len(posts))
+	} else {
+		fmt.Fprintf(s.out, "New posts since %s (%d):\n\n", since.Format("2006-01-02 15:04:05"), len(posts))
+	}
+	for i, post := range posts {
+		if compact {
+			printCompactPost(s.out, int32(i)+1, post.Title, post.FeedName)
+		} else {
+			printBrowsePost(s, int32(i)+1, post)
+		}
+	}
+	if len(posts) == maxNewPosts {
+		fmt.Fprintf(s.out, "Showing the newest %d posts; older new posts were skipped.\n", maxNewPosts)
+	}
+	return nil
+}
+
+// fetchNewPosts returns up to limit followed posts stored after the user's
+// last-browsed timestamp, along with that timestamp, and advances it to now.
+// On first use there is no timestamp, so every post is returned.
+func fetchNewPosts(ctx context.Context, db *database.Queries, userID uuid.UUID, now time.Time, limit int32) ([]database.GetPostsForUserRow, time.Time, error) {
+	since, err := db.GetLastBrowsedAt(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, fmt.Errorf("couldn't retrieve last browse time: %w", err)
+	}
+
+	rows, err := db.GetPostsForUserSince(ctx, database.GetPostsForUserSinceParams{
+		UserID:    userID,
+		CreatedAt: since,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("couldn't retrieve posts: %w", err)
+	}
+
+	if err := db.SetLastBrowsedAt(ctx, database.SetLastBrowsedAtParams{
+		UserID:        userID,
+		LastBrowsedAt: now,
+	}); err != nil {
+		return nil, time.Time{}, fmt.Errorf("couldn't update last browse time: %w", err)
+	}
+
+	posts := make([]database.GetPostsForUserRow, len(rows))
+	for i, row := range rows {
+		posts[i] = database.GetPostsForUserRow(row)
+	}
+	return posts, since, nil
+}
+
+// maxDescriptionLength is how many characters of a description list output shows
+const maxDescriptionLength = 200
+
+// truncateDescription shortens desc to maxDescriptionLength characters,
+// adding "..." when it was cut. It counts runes, not bytes, so multi-byte
+// characters are never split.
+func truncateDescription(desc string) string {
+	if utf8.RuneCountInString(desc) <= maxDescriptionLength {
+		return desc
+	}
+	return string([]rune(desc)[:maxDescriptionLength]) + "..."
+}
+
+// formatPublished formats a post's publication date, marking dates gator
+// inferred because the feed didn't give the post one
+func formatPublished(publishedAt time.Time, source sql.NullString) string {
+	formatted := publishedAt.Format("2006-01-02 15:04:05")
+	if source.Valid && source.String != rss.DateSourcePublished {
+		formatted += " (estimated)"
+	}
+	return formatted
+}
+
+// printBrowsePost prints one numbered post in browse output
+func printBrowsePost(s *state, number int32, post database.GetPostsForUserRow) {
+	fmt.Fprintf(s.out, "%d. %s\n", number, post.Title)
+	fmt.Fprintf(s.out, "   Post ID: %s\n", post.ID)
+	fmt.Fprintf(s.out, "   Feed: %s\n", post.FeedName)
+	if post.Author.Valid {
+		fmt.Fprintf(s.out, "   Author: %s\n", post.Author.String)
+	}
+	if post.Description.Valid && post.Description.String != "" {
+		fmt.Fprintf(s.out, "   %s\n", truncateDescription(post.Description.String))
+	}
+	if post.PublishedAt.Valid {
+		fmt.Fprintf(s.out, "   Published: %s\n", formatPublished(post.PublishedAt.Time, post.DateSource))
+	}
+	fmt.Fprintf(s.out, "   URL: %s\n", post.Url)
+	if post.EnclosureUrl.Valid {
+		fmt.Fprintf(s.out, "   Media: %s\n", post.EnclosureUrl.String)
+	}
+	fmt.Fprintln(s.out)
+}
+
+// printCompactPost writes a single-line "N. Title — FeedName" entry, as used
+// by the --compact flag of browse and search
+func printCompactPost(w io.Writer, number int32, title, feedName string) {
+	fmt.Fprintf(w, "%d. %s — %s\n", number, title, feedName)
+}
+
+// fetchBrowsePosts loads a page of posts for browse, either from followed feeds,
+// from one followed feed when feed is set, from unread posts when unread is set,
+// from the user's tagged posts when tag is set, from posts in a category when
+// category is set,
+// or, when liked is set, from the posts the user has liked
+func fetchBrowsePosts(ctx context.Context, db *database.Queries, userID uuid.UUID, filter browseFilter, limit, offset int32) ([]database.GetPostsForUserRow, error) {
+	if filter.feed != "" {
+		feedPosts, err := db.GetPostsForUserByFeed(ctx, database.GetPostsForUserByFeedParams{
+			UserID: userID,
+			Feed:   filter.feed,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(feedPosts))
+		for i, post := range feedPosts {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	}
+
+	if filter.unread {
+		unreadPosts, err := db.GetUnreadPostsForUser(ctx, database.GetUnreadPostsForUserParams{
+			UserID: userID,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(unreadPosts))
+		for i, post := range unreadPosts {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	}
+
+	if filter.tag != "" {
+		tagged, err := db.GetTaggedPostsForUser(ctx, database.GetTaggedPostsForUserParams{
+			UserID: userID,
+			Tag:    filter.tag,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(tagged))
+		for i, post := range tagged {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	}
+
+	if filter.category != "" {
+		categorized, err := db.GetPostsByCategory(ctx, database.GetPostsByCategoryParams{
+			UserID:   userID,
+			Category: filter.category,
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(categorized))
+		for i, post := range categorized {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	}
+
+	if !filter.liked {
+		return fetchFollowedPosts(ctx, db, userID, filter.sort, limit, offset)
+	}
+
+	likedPosts, err := db.GetLikedPostsForUser(ctx, database.GetLikedPostsForUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]database.GetPostsForUserRow, len(likedPosts))
+	for i, post := range likedPosts {
+		posts[i] = database.GetPostsForUserRow(post)
+	}
+	return posts, nil
+}
+
+// fetchFollowedPosts loads a page of posts from the user's followed feeds in
+// the given browse sort order
+func fetchFollowedPosts(ctx context.Context, db *database.Queries, userID uuid.UUID, sort string, limit, offset int32) ([]database.GetPostsForUserRow, error) {
+	switch sort {
+	case browseSortOldest:
+		oldest, err := db.GetPostsForUserOldest(ctx, database.GetPostsForUserOldestParams{
+			UserID: userID,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(oldest))
+		for i, post := range oldest {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	case browseSortAdded:
+		added, err := db.GetPostsForUserRecentlyAdded(ctx, database.GetPostsForUserRecentlyAddedParams{
+			UserID: userID,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		posts := make([]database.GetPostsForUserRow, len(added))
+		for i, post := range added {
+			posts[i] = database.GetPostsForUserRow(post)
+		}
+		return posts, nil
+	}
+	return db.GetPostsForUser(ctx, database.GetPostsForUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// handlerMarkRead marks a post read for the current user so `browse --unread` skips it
+func handlerMarkRead(s *state, cmd command, user database.User) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf("mark-read requires a post ID or URL")
+	}
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		ReadAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't mark post read: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		fmt.Fprintf(s.out, "Post %s is already marked read\n", post.ID)
+		return nil
+	}
+	fmt.Fprintf(s.out, "Marked post %s as read\n", post.ID)
+	return nil
+}
+
+// handlerMarkUnread marks a post unread again for the current user
+func handlerMarkUnread(s *state, cmd command, user database.User) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf("mark-unread requires a post ID or URL")
+	}
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.db.MarkPostUnread(context.Background(), database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't mark post unread: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		fmt.Fprintf(s.out, "Post %s is already unread\n", post.ID)
+		return nil
+	}
+	fmt.Fprintf(s.out, "Marked post %s as unread\n", post.ID)
+	return nil
+}
+
+// handlerPosts dispatches `posts` subcommands
+func handlerPosts(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("posts requires a subcommand: recent [limit], tag <post> <tag>, or untag <post> <tag>")
+	}
+
+	switch cmd.args[0] {
+	case "recent":
+		return handlerPostsRecent(s, command{name: "posts recent", args: cmd.args[1:]})
+	case "tag":
+		return handlerPostsTag(s, command{name: "posts tag", args: cmd.args[1:]}, user)
+	case "untag":
+		return handlerPostsUntag(s, command{name: "posts untag", args: cmd.args[1:]}, user)
+	default:
+		return fmt.Errorf("unknown posts subcommand: %s", cmd.args[0])
+	}
+}
+
+// handlerPostsRecent displays the newest posts across all feeds, regardless of follows
+func handlerPostsRecent(s *state, cmd command) error {
+	const maxLimit = 100
+	limit := int32(10)
+	if len(cmd.args) >= 1 {
+		l, err := strconv.Atoi(cmd.args[0])
+		if err != nil || l < 1 {
+			return fmt.Errorf("limit must be a positive number, got: %s", cmd.args[0])
+		}
+		if l > maxLimit {
+			l = maxLimit
+		}
+		limit = int32(l)
+	}
+
+	posts, err := s.db.GetRecentPosts(context.Background(), limit)
+	if err != nil {
+		return fmt.Errorf("couldn't retrieve recent posts: %w", err)
+	}
+
+	if len(posts) == 0 {
+		fmt.Fprintf(s.out, "No posts found.\n")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "Recent posts across all feeds (showing %d posts):\n\n", len(posts))
+	for i, post := range posts {
+		fmt.Fprintf(s.out, "%d. %s\n", i+1, post.Title)
+		fmt.Fprintf(s.out, "   Post ID: %s\n", post.ID)
+		fmt.Fprintf(s.out, "   Feed: %s\n", post.FeedName)
+		if post.PublishedAt.Valid {
+			fmt.Fprintf(s.out, "   Published: %s\n", formatPublished(post.PublishedAt.Time, post.DateSource))
+		}
+		fmt.Fprintf(s.out, "   URL: %s\n", post.Url)
+		fmt.Fprintln(s.out)
+	}
+
+	return nil
+}
+
+// handlerPostsTag attaches a personal tag to a post for the current user
+func handlerPostsTag(s *state, cmd command, user database.User) error {
+	if len(cmd.args) != 2 {
+		return fmt.Errorf("posts tag requires a post ID or URL and a tag")
+	}
+	tag := strings.TrimSpace(cmd.args[1])
+	if tag == "" {
+		return fmt.Errorf("tag can't be empty")
+	}
+
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.db.TagPost(context.Background(), database.TagPostParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+		Tag:       tag,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't tag post: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		fmt.Fprintf(s.out, "Post %s is already tagged %q\n", post.ID, tag)
+		return nil
+	}
+	fmt.Fprintf(s.out, "Tagged post %s as %q\n", post.ID, tag)
+	return nil
+}
+
+// handlerPostsUntag removes a personal tag from a post for the current user
+func handlerPostsUntag(s *state, cmd command, user database.User) error {
+	if len(cmd.args) != 2 {
+		return fmt.Errorf("posts untag requires a post ID or URL and a tag")
+	}
+	tag := strings.TrimSpace(cmd.args[1])
+
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
+	if err != nil {
+		return err
 	}
-	if page > 1 {
-		fmt.Printf("To see previous posts, run: gator browse %d\n", page-1)
+
+	rowsAffected, err := s.db.UntagPost(context.Background(), database.UntagPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		Tag:    tag,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't untag post: %w", err)
 	}
 
+	if rowsAffected == 0 {
+		return fmt.Errorf("post is not tagged %q", tag)
+	}
+	fmt.Fprintf(s.out, "Removed tag %q from post %s\n", tag, post.ID)
 	return nil
 }
 
 // handlerSearch searches posts for the current user by a fuzzy term (title/description)
 func handlerSearch(s *state, cmd command, user database.User) error {
-	const postsPerPage = 5
-	if len(cmd.args) < 1 {
+	compact, args := hasFlag(cmd.args, "--compact")
+	sinceID, args, err := flagValue(args, "--since-id")
+	if err != nil {
+		return err
+	}
+	limit, args, err := parseLimitFlag(args)
+	if err != nil {
+		return err
+	}
+	postsPerPage := pageSize(limit, s.cfg)
+	if len(args) < 1 {
 		return fmt.Errorf("search requires a search term")
 	}
 
-	query := cmd.args[0]
+	query := args[0]
+	if sinceID != "" {
+		return handlerSearchSince(s, user, query, sinceID, compact)
+	}
+
 	page := int32(1)
-	if len(cmd.args) >= 2 {
-		var err error
-		page, err = parsePageArg(cmd.args[1])
+	if len(args) >= 2 {
+		page, err = parsePageArg(args[1])
 		if err != nil {
 			return err
 		}
@@ -485,12 +1850,7 @@ func handlerSearch(s *state, cmd command, user database.User) error {
 	defer cancel()
 
 	// Query for one extra to determine if more pages exist
-	posts, err := s.db.SearchPostsForUser(ctx, database.SearchPostsForUserParams{
-		UserID:  user.ID,
-		Column2: sql.NullString{String: query, Valid: true},
-		Limit:   postsPerPage + 1,
-		Offset:  offset,
-	})
+	posts, err := search.Posts(ctx, s.db, user.ID, query, postsPerPage+1, offset)
 	if err != nil {
 		return fmt.Errorf("couldn't search posts: %w", err)
 	}
@@ -502,64 +1862,150 @@ func handlerSearch(s *state, cmd command, user database.User) error {
 
 	if len(posts) == 0 {
 		if page == 1 {
-			fmt.Printf("No matching posts found for '%s'.\n", query)
+			fmt.Fprintf(s.out, "No matching posts found for '%s'.\n", query)
 		} else {
-			fmt.Printf("No matching posts found on page %d for '%s'. Try a lower page number.\n", page, query)
+			fmt.Fprintf(s.out, "No matching posts found on page %d for '%s'. Try a lower page number.\n", page, query)
 		}
 		return nil
 	}
 
-	fmt.Printf("Search results for '%s' (page %d, showing %d posts):\n\n", query, page, len(posts))
+	fmt.Fprintf(s.out, "Search results for '%s' (page %d, showing %d posts):\n\n", query, page, len(posts))
 	for i, post := range posts {
-		postNumber := offset + int32(i) + 1
-		fmt.Printf("%d. %s\n", postNumber, post.Title)
-		fmt.Printf("   Post ID: %s\n", post.ID)
-		fmt.Printf("   Feed: %s\n", post.FeedName)
-		if post.Description.Valid && post.Description.String != "" {
-			desc := post.Description.String
-			if len(desc) > 200 {
-				desc = desc[:200] + "..."
-			}
-			fmt.Printf("   %s\n", desc)
-		}
-		if post.PublishedAt.Valid {
-			fmt.Printf("   Published: %s\n", post.PublishedAt.Time.Format("2006-01-02 15:04:05"))
-		}
-		fmt.Printf("   URL: %s\n", post.Url)
-		fmt.Println()
+		printSearchPost(s, offset+int32(i)+1, post, compact)
 	}
 
+	searchCmd := "gator search"
+	if compact {
+		searchCmd += " --compact"
+	}
+	if limit > 0 {
+		searchCmd += fmt.Sprintf(" --limit %d", limit)
+	}
 	if hasMorePages {
-		fmt.Printf("To see more results, run: gator search %s %d\n", query, page+1)
+		fmt.Fprintf(s.out, "To see more results, run: %s %s %d\n", searchCmd, query, page+1)
 	}
 	if page > 1 {
-		fmt.Printf("To see previous results, run: gator search %s %d\n", query, page-1)
+		fmt.Fprintf(s.out, "To see previous results, run: %s %s %d\n", searchCmd, query, page-1)
 	}
 
 	return nil
 }
 
-// handlerBookmark adds a post to the user's bookmarks
-func handlerBookmark(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 1 {
-		return fmt.Errorf("bookmark requires a post ID argument")
+// printSearchPost prints one search result, on a single line when compact
+func printSearchPost(s *state, number int32, post database.SearchPostsForUserRow, compact bool) {
+	if compact {
+		printCompactPost(s.out, number, post.Title, post.FeedName)
+		return
+	}
+	fmt.Fprintf(s.out, "%d. %s\n", number, post.Title)
+	fmt.Fprintf(s.out, "   Post ID: %s\n", post.ID)
+	fmt.Fprintf(s.out, "   Feed: %s\n", post.FeedName)
+	if post.Author.Valid {
+		fmt.Fprintf(s.out, "   Author: %s\n", post.Author.String)
+	}
+	if post.Description.Valid && post.Description.String != "" {
+		fmt.Fprintf(s.out, "   %s\n", truncateDescription(post.Description.String))
+	}
+	if post.PublishedAt.Valid {
+		fmt.Fprintf(s.out, "   Published: %s\n", formatPublished(post.PublishedAt.Time, post.DateSource))
+	}
+	fmt.Fprintf(s.out, "   URL: %s\n", post.Url)
+	if post.EnclosureUrl.Valid {
+		fmt.Fprintf(s.out, "   Media: %s\n", post.EnclosureUrl.String)
 	}
+	fmt.Fprintln(s.out)
+}
+
+// maxSearchSince caps how many results one `search --since-id` poll returns
+const maxSearchSince = 100
+
+// handlerSearchSince prints search matches stored after the post sinceID,
+// oldest first, and the cursor to pass on the next poll
+func handlerSearchSince(s *state, user database.User, query, sinceID string, compact bool) error {
+	cursorID, err := uuid.Parse(sinceID)
+	if err != nil {
+		return fmt.Errorf("--since-id must be a post ID, got: %s", sinceID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	// Parse the post ID
-	postIDStr := cmd.args[0]
-	postID, err := uuid.Parse(postIDStr)
+	cursor, err := s.db.GetPostByID(ctx, cursorID)
 	if err != nil {
-		return fmt.Errorf("invalid post ID format: %s", postIDStr)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("post not found: %s", sinceID)
+		}
+		return fmt.Errorf("couldn't look up post %s: %w", sinceID, err)
+	}
+
+	posts, err := s.db.SearchPostsForUserSince(ctx, database.SearchPostsForUserSinceParams{
+		UserID:    user.ID,
+		Column2:   sql.NullString{String: query, Valid: true},
+		CreatedAt: cursor.CreatedAt,
+		ID:        cursor.ID,
+		Limit:     maxSearchSince,
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't search posts: %w", err)
 	}
 
-	// Check if the post exists
-	_, err = s.db.GetPostByID(context.Background(), postID)
+	if len(posts) == 0 {
+		fmt.Fprintf(s.out, "No new matching posts for '%s'.\n", query)
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "New results for '%s' (%d posts):\n\n", query, len(posts))
+	for i, post := range posts {
+		printSearchPost(s, int32(i)+1, database.SearchPostsForUserRow(post), compact)
+	}
+	fmt.Fprintf(s.out, "To poll for newer results, run: gator search --since-id %s %s\n", posts[len(posts)-1].ID, query)
+	return nil
+}
+
+// resolvePostArg looks up a post given either its ID or its URL
+func resolvePostArg(ctx context.Context, db *database.Queries, arg string) (database.Post, error) {
+	var post database.Post
+	var err error
+	if postID, parseErr := uuid.Parse(arg); parseErr == nil {
+		post, err = db.GetPostByID(ctx, postID)
+	} else {
+		post, err = findPostByURL(ctx, db, arg)
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("post not found with ID: %s", postIDStr)
+			return database.Post{}, fmt.Errorf("post not found: %s", arg)
 		}
-		return fmt.Errorf("database error while looking up post: %w", err)
+		return database.Post{}, fmt.Errorf("database error while looking up post: %w", err)
+	}
+	return post, nil
+}
+
+// findPostByURL returns the post stored under rawURL, falling back to the
+// normalized form of rawURL so a pasted link with tracking parameters or a
+// fragment still finds its post
+func findPostByURL(ctx context.Context, db *database.Queries, rawURL string) (database.Post, error) {
+	post, err := db.GetPostByURL(ctx, rawURL)
+	if err == nil || !errors.Is(err, sql.ErrNoRows) {
+		return post, err
+	}
+	if normalized := rss.NormalizeURL(rawURL); normalized != rawURL {
+		return db.GetPostByURL(ctx, normalized)
+	}
+	return post, err
+}
+
+// handlerBookmark adds a post to the user's bookmarks
+func handlerBookmark(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("bookmark requires a post ID or URL argument")
+	}
+
+	// Look up the post by ID or URL
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
+	if err != nil {
+		return err
 	}
+	postID := post.ID
 
 	// Create the bookmark
 	bookmark, err := s.db.CreateBookmark(context.Background(), database.CreateBookmarkParams{
@@ -578,24 +2024,24 @@ func handlerBookmark(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("post is already bookmarked")
 	}
 
-	fmt.Printf("Successfully bookmarked post %s\n", postID)
-	fmt.Printf("Bookmark ID: %s\n", bookmark.ID)
-	fmt.Printf("Bookmarked at: %s\n", bookmark.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(s.out, "Successfully bookmarked post %s\n", postID)
+	fmt.Fprintf(s.out, "Bookmark ID: %s\n", bookmark.ID)
+	fmt.Fprintf(s.out, "Bookmarked at: %s\n", bookmark.CreatedAt.Format("2006-01-02 15:04:05"))
 	return nil
 }
 
 // handlerUnbookmark removes a post from the user's bookmarks
 func handlerUnbookmark(s *state, cmd command, user database.User) error {
 	if len(cmd.args) < 1 {
-		return fmt.Errorf("unbookmark requires a post ID argument")
+		return fmt.Errorf("unbookmark requires a post ID or URL argument")
 	}
 
-	// Parse the post ID
-	postIDStr := cmd.args[0]
-	postID, err := uuid.Parse(postIDStr)
+	// Look up the post by ID or URL
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
 	if err != nil {
-		return fmt.Errorf("invalid post ID format: %s", postIDStr)
+		return err
 	}
+	postID := post.ID
 
 	// Delete the bookmark
 	rowsAffected, err := s.db.DeleteBookmark(context.Background(), database.DeleteBookmarkParams{
@@ -607,23 +2053,45 @@ func handlerUnbookmark(s *state, cmd command, user database.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("post %s is not bookmarked", postIDStr)
+		return fmt.Errorf("post %s is not bookmarked", postID)
 	}
 
-	fmt.Printf("Successfully removed bookmark for post %s\n", postID)
+	fmt.Fprintf(s.out, "Successfully removed bookmark for post %s\n", postID)
 	return nil
 }
 
-// handlerBookmarks displays all bookmarked posts for the current user with pagination
+// handlerBookmarks displays all bookmarked posts for the current user with pagination,
+// optionally limited to one feed with --feed <url>. --limit N sets the page size.
 func handlerBookmarks(s *state, cmd command, user database.User) error {
-	const postsPerPage = 5 // Number of bookmarks to show per page
-	page := int32(1)       // Default to page 1
-	if len(cmd.args) >= 1 {
-		var err error
-		page, err = parsePageArg(cmd.args[0])
+	feedURL, args, err := flagValue(cmd.args, "--feed")
+	if err != nil {
+		return err
+	}
+	limit, args, err := parseLimitFlag(args)
+	if err != nil {
+		return err
+	}
+	postsPerPage := pageSize(limit, s.cfg)
+
+	page := int32(1) // Default to page 1
+	if len(args) >= 1 {
+		page, err = parsePageArg(args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	// Resolve the feed scope before querying
+	var feedID uuid.NullUUID
+	if feedURL != "" {
+		feed, err := findFeedByURL(context.Background(), s.db, feedURL, rss.NormalizeURL(feedURL))
 		if err != nil {
 			return err
 		}
+		if feed == nil {
+			return fmt.Errorf("feed not found: %s", feedURL)
+		}
+		feedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
 	}
 
 	// Calculate offset based on page number
@@ -634,6 +2102,7 @@ func handlerBookmarks(s *state, cmd command, user database.User) error {
 		UserID: user.ID,
 		Limit:  postsPerPage + 1, // Query for one extra bookmark
 		Offset: offset,
+		FeedID: feedID,
 	})
 	if err != nil {
 		return fmt.Errorf("couldn't retrieve bookmarks: %w", err)
@@ -649,42 +2118,44 @@ func handlerBookmarks(s *state, cmd command, user database.User) error {
 
 	if len(bookmarks) == 0 {
 		if page == 1 {
-			fmt.Printf("No bookmarked posts found. Try bookmarking some posts first!\n")
+			fmt.Fprintf(s.out, "No bookmarked posts found. Try bookmarking some posts first!\n")
 		} else {
-			fmt.Printf("No bookmarked posts found on page %d. Try a lower page number.\n", page)
+			fmt.Fprintf(s.out, "No bookmarked posts found on page %d. Try a lower page number.\n", page)
 		}
 		return nil
 	}
 
-	fmt.Printf("Bookmarked posts (page %d, showing %d posts):\n\n", page, len(bookmarks))
+	fmt.Fprintf(s.out, "Bookmarked posts (page %d, showing %d posts):\n\n", page, len(bookmarks))
 	for i, bookmark := range bookmarks {
 		// Calculate the overall bookmark number based on page and position
 		bookmarkNumber := offset + int32(i) + 1
-		fmt.Printf("%d. %s\n", bookmarkNumber, bookmark.Title)
-		fmt.Printf("   Post ID: %s\n", bookmark.ID)
-		fmt.Printf("   Feed: %s\n", bookmark.FeedName)
+		fmt.Fprintf(s.out, "%d. %s\n", bookmarkNumber, bookmark.Title)
+		fmt.Fprintf(s.out, "   Post ID: %s\n", bookmark.ID)
+		fmt.Fprintf(s.out, "   Feed: %s\n", bookmark.FeedName)
 		if bookmark.Description.Valid && bookmark.Description.String != "" {
-			// Truncate description if it's too long
-			desc := bookmark.Description.String
-			if len(desc) > 200 {
-				desc = desc[:200] + "..."
-			}
-			fmt.Printf("   %s\n", desc)
+			fmt.Fprintf(s.out, "   %s\n", truncateDescription(bookmark.Description.String))
 		}
 		if bookmark.PublishedAt.Valid {
-			fmt.Printf("   Published: %s\n", bookmark.PublishedAt.Time.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(s.out, "   Published: %s\n", formatPublished(bookmark.PublishedAt.Time, bookmark.DateSource))
 		}
-		fmt.Printf("   Bookmarked: %s\n", bookmark.BookmarkedAt.Format("2006-01-02 15:04:05"))
-		fmt.Printf("   URL: %s\n", bookmark.Url)
-		fmt.Println()
+		fmt.Fprintf(s.out, "   Bookmarked: %s\n", bookmark.BookmarkedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(s.out, "   URL: %s\n", bookmark.Url)
+		fmt.Fprintln(s.out)
 	}
 
 	// Show pagination info
+	bookmarksCmd := "gator bookmarks"
+	if feedURL != "" {
+		bookmarksCmd += " --feed " + feedURL
+	}
+	if limit > 0 {
+		bookmarksCmd += fmt.Sprintf(" --limit %d", limit)
+	}
 	if hasMorePages {
-		fmt.Printf("To see more bookmarks, run: gator bookmarks %d\n", page+1)
+		fmt.Fprintf(s.out, "To see more bookmarks, run: %s %d\n", bookmarksCmd, page+1)
 	}
 	if page > 1 {
-		fmt.Printf("To see previous bookmarks, run: gator bookmarks %d\n", page-1)
+		fmt.Fprintf(s.out, "To see previous bookmarks, run: %s %d\n", bookmarksCmd, page-1)
 	}
 
 	return nil
@@ -693,20 +2164,15 @@ func handlerBookmarks(s *state, cmd command, user database.User) error {
 // handlerLike adds a like to a post for the current user
 func handlerLike(s *state, cmd command, user database.User) error {
 	if len(cmd.args) != 1 {
-		return fmt.Errorf("like requires a post ID argument")
-	}
-
-	postIDStr := cmd.args[0]
-	postID, err := uuid.Parse(postIDStr)
-	if err != nil {
-		return fmt.Errorf("invalid post ID format: %w", err)
+		return fmt.Errorf("like requires a post ID or URL argument")
 	}
 
-	// Check if post exists
-	_, err = s.db.GetPostByID(context.Background(), postID)
+	// Look up the post by ID or URL
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
 	if err != nil {
-		return fmt.Errorf("post not found: %w", err)
+		return err
 	}
+	postID := post.ID
 
 	// Check if user already liked this post
 	_, err = s.db.GetLikeByUserAndPost(context.Background(), database.GetLikeByUserAndPostParams{
@@ -716,6 +2182,9 @@ func handlerLike(s *state, cmd command, user database.User) error {
 	if err == nil {
 		return fmt.Errorf("post is already liked")
 	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("couldn't check existing like: %w", err)
+	}
 
 	// Create the like
 	like, err := s.db.CreateLike(context.Background(), database.CreateLikeParams{
@@ -729,9 +2198,9 @@ func handlerLike(s *state, cmd command, user database.User) error {
 		return fmt.Errorf("couldn't create like: %w", err)
 	}
 
-	fmt.Printf("Successfully liked post %s\n", postID)
-	fmt.Printf("Like ID: %s\n", like.ID)
-	fmt.Printf("Liked at: %s\n", like.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(s.out, "Successfully liked post %s\n", postID)
+	fmt.Fprintf(s.out, "Like ID: %s\n", like.ID)
+	fmt.Fprintf(s.out, "Liked at: %s\n", like.CreatedAt.Format("2006-01-02 15:04:05"))
 
 	return nil
 }
@@ -739,14 +2208,15 @@ func handlerLike(s *state, cmd command, user database.User) error {
 // handlerUnlike removes a like from a post for the current user
 func handlerUnlike(s *state, cmd command, user database.User) error {
 	if len(cmd.args) != 1 {
-		return fmt.Errorf("unlike requires a post ID argument")
+		return fmt.Errorf("unlike requires a post ID or URL argument")
 	}
 
-	postIDStr := cmd.args[0]
-	postID, err := uuid.Parse(postIDStr)
+	// Look up the post by ID or URL
+	post, err := resolvePostArg(context.Background(), s.db, cmd.args[0])
 	if err != nil {
-		return fmt.Errorf("invalid post ID format: %w", err)
+		return err
 	}
+	postID := post.ID
 
 	// Remove the like
 	rowsAffected, err := s.db.DeleteLike(context.Background(), database.DeleteLikeParams{
@@ -758,10 +2228,10 @@ func handlerUnlike(s *state, cmd command, user database.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("post is not liked by user")
+		return fmt.Errorf("post %s is not liked", postID)
 	}
 
-	fmt.Printf("Successfully removed like from post %s\n", postID)
+	fmt.Fprintf(s.out, "Successfully removed like from post %s\n", postID)
 	return nil
 }
 
@@ -799,38 +2269,38 @@ func handlerLikes(s *state, cmd command, user database.User) error {
 
 	if len(likes) == 0 {
 		if page == 1 {
-			fmt.Println("No liked posts found. Try liking some posts first!")
+			fmt.Fprintln(s.out, "No liked posts found. Try liking some posts first!")
 		} else {
-			fmt.Printf("No liked posts found on page %d.\n", page)
+			fmt.Fprintf(s.out, "No liked posts found on page %d. Try a lower page number.\n", page)
 		}
 		return nil
 	}
 
-	fmt.Printf("Liked posts (page %d, showing %d posts):\n\n", page, len(likes))
+	fmt.Fprintf(s.out, "Liked posts (page %d, showing %d posts):\n\n", page, len(likes))
 	for i, like := range likes {
 		postNumber := int(page-1)*int(postsPerPage) + i + 1
 
-		fmt.Printf("%d. %s\n", postNumber, like.Title)
-		fmt.Printf("   Post ID: %s\n", like.ID)
-		fmt.Printf("   Feed: %s\n", like.FeedName)
+		fmt.Fprintf(s.out, "%d. %s\n", postNumber, like.Title)
+		fmt.Fprintf(s.out, "   Post ID: %s\n", like.ID)
+		fmt.Fprintf(s.out, "   Feed: %s\n", like.FeedName)
 
 		if like.Description.Valid && like.Description.String != "" {
-			fmt.Printf("   %s\n", like.Description.String)
+			fmt.Fprintf(s.out, "   %s\n", truncateDescription(like.Description.String))
 		}
 
 		if like.PublishedAt.Valid {
-			fmt.Printf("   Published: %s\n", like.PublishedAt.Time.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(s.out, "   Published: %s\n", formatPublished(like.PublishedAt.Time, like.DateSource))
 		}
-		fmt.Printf("   Liked: %s\n", like.LikedAt.Format("2006-01-02 15:04:05"))
-		fmt.Printf("   URL: %s\n\n", like.Url)
+		fmt.Fprintf(s.out, "   Liked: %s\n", like.LikedAt.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(s.out, "   URL: %s\n\n", like.Url)
 	}
 
 	// Show navigation hints
 	if hasMorePages {
-		fmt.Printf("To see more likes, run: gator likes %d\n", page+1)
+		fmt.Fprintf(s.out, "To see more likes, run: gator likes %d\n", page+1)
 	}
 	if page > 1 {
-		fmt.Printf("To see previous likes, run: gator likes %d\n", page-1)
+		fmt.Fprintf(s.out, "To see previous likes, run: gator likes %d\n", page-1)
 	}
 
 	return nil
@@ -838,27 +2308,177 @@ func handlerLikes(s *state, cmd command, user database.User) error {
 
 // handlerServe starts the HTTP API server
 func handlerServe(s *state, cmd command) error {
-	port := "8080" // default port
-	if len(cmd.args) >= 1 {
-		port = cmd.args[0]
+	origins, args, err := flagValue(cmd.args, "--cors-origins")
+	if err != nil {
+		return err
 	}
-
-	// Check for PORT environment variable
-	if envPort := os.Getenv("PORT"); envPort != "" {
-		port = envPort
+	if origins == "" {
+		origins = os.Getenv("GATOR_CORS_ORIGINS")
 	}
+	rps, burst, args, err := serveRateLimit(args)
+	if err != nil {
+		return err
+	}
+	port := servePort(args, os.Getenv("PORT"))
 
 	server := api.NewServer(s.db, port)
-	fmt.Printf("Starting Gator HTTP API server on port %s\n", port)
-	fmt.Printf("Health check: http://localhost:%s/health\n", port)
-	fmt.Printf("API documentation: http://localhost:%s/api/docs\n", port)
+	server.SetRateLimit(rps, burst)
+	server.SetUserAgent(s.cfg.FetchUserAgent())
+	if origins != "" {
+		server.SetAllowedOrigins(strings.Split(origins, ","))
+		fmt.Fprintf(s.out, "CORS enabled for: %s\n", origins)
+	}
+	fmt.Fprintf(s.out, "Gator HTTP API listening on http://localhost:%s (Ctrl+C to stop)\n", port)
+	fmt.Fprintf(s.out, "Health check: http://localhost:%s/health\n", port)
+	fmt.Fprintf(s.out, "API documentation: http://localhost:%s/api/docs\n", port)
+
+	// Serve until interrupted, letting in-flight requests finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := server.Run(ctx); err != nil {
+		return fmt.Errorf("couldn't run server on port %s: %w", port, err)
+	}
+	fmt.Fprintln(s.out, "Server stopped.")
+	return nil
+}
+
+// Default per-client API rate limit for `serve`
+const (
+	defaultRateLimit = 10 // requests per second
+	defaultRateBurst = 20
+)
+
+// serveRateLimit parses `serve --rate-limit <rps> --rate-burst <n>`, returning
+// the remaining args. --rate-limit 0 turns limiting off.
+func serveRateLimit(args []string) (float64, int, []string, error) {
+	rpsValue, args, err := flagValue(args, "--rate-limit")
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	burstValue, args, err := flagValue(args, "--rate-burst")
+	if err != nil {
+		return 0, 0, nil, err
+	}
 
-	return server.Start()
+	rps := float64(defaultRateLimit)
+	if rpsValue != "" {
+		rps, err = strconv.ParseFloat(rpsValue, 64)
+		if err != nil || rps < 0 || math.IsNaN(rps) || math.IsInf(rps, 0) {
+			return 0, 0, nil, fmt.Errorf("invalid --rate-limit %q: must be a non-negative number of requests per second", rpsValue)
+		}
+	}
+	burst := defaultRateBurst
+	if burstValue != "" {
+		burst, err = strconv.Atoi(burstValue)
+		if err != nil || burst < 1 {
+			return 0, 0, nil, fmt.Errorf("invalid --rate-burst %q: must be a positive integer", burstValue)
+		}
+	}
+	return rps, burst, args, nil
+}
+
+// servePort picks the port for `serve`: an explicit argument wins, then the
+// PORT environment variable, then 8080
+func servePort(args []string, envPort string) string {
+	if len(args) >= 1 {
+		return args[0]
+	}
+	if envPort != "" {
+		return envPort
+	}
+	return "8080"
 }
 
-// handlerTUI starts the Terminal User Interface for browsing posts
+// handlerTUI starts the Terminal User Interface for browsing posts.
+// With --live-search, search results update as the query is typed.
 func handlerTUI(s *state, cmd command, user database.User) error {
-	return tui.RunTUI(s.db, user.ID)
+	liveSearch, _ := hasFlag(cmd.args, "--live-search")
+	return tui.RunTUI(s.db, user.ID, liveSearch)
+}
+
+// isUniqueViolation checks if an error is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// PostgreSQL error code 23505 is unique_violation
+		return pqErr.Code == "23505"
+	}
+
+	// Also check for common unique constraint error messages as fallback
+	errMsg := strings.ToLower(err.Error())
+	return strings.Contains(errMsg, "unique constraint") ||
+		strings.Contains(errMsg, "duplicate key")
+}
+
+// hasFlag reports whether a boolean flag is present in args and returns the
+// remaining arguments with every occurrence of the flag removed.
+func hasFlag(args []string, flag string) (bool, []string) {
+	found := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return found, rest
+}
+
+// flagValue returns the value of a flag given as "--flag value" or "--flag=value"
+// and the remaining arguments with the flag and its value removed.
+// An error is returned if the flag is present without a value.
+func flagValue(args []string, flag string) (string, []string, error) {
+	value := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == flag:
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("%s requires a value", flag)
+			}
+			value = args[i+1]
+			i++
+		case strings.HasPrefix(arg, flag+"="):
+			value = strings.TrimPrefix(arg, flag+"=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return value, rest, nil
+}
+
+// Page sizes for browse, search, and bookmarks
+const (
+	defaultPageSize = 5
+	maxPageSize     = 100
+)
+
+// parseLimitFlag removes --limit N from args and returns N, capped at
+// maxPageSize, or 0 when the flag is absent
+func parseLimitFlag(args []string) (int32, []string, error) {
+	value, rest, err := flagValue(args, "--limit")
+	if err != nil || value == "" {
+		return 0, rest, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, nil, fmt.Errorf("limit must be a positive number, got: %s", value)
+	}
+	return int32(min(n, maxPageSize)), rest, nil
+}
+
+// pageSize is the number of posts per page: limit when set, otherwise
+// page_size from the config, otherwise defaultPageSize
+func pageSize(limit int32, cfg *config.Config) int32 {
+	if limit > 0 {
+		return limit
+	}
+	if n := cfg.DefaultPageSize(); n > 0 {
+		return int32(min(n, maxPageSize))
+	}
+	return defaultPageSize
 }
 
 // parsePageArg parses a page argument string and returns a validated int32 page number.
@@ -877,17 +2497,82 @@ func parsePageArg(s string) (int32, error) {
 type AggregationConfig struct {
 	Workers int
 	Fetch   func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error)
-	Save    func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) error
+	Save    func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error)
 	Client  *http.Client
 	DB      *database.Queries
+	// Statements, if set, holds the prepared statements behind DB; Close
+	// releases them
+	Statements *database.StmtCache
+	Breaker    *rss.CircuitBreaker
+	// PerHost caps how many feeds on the same host are fetched at once,
+	// within the overall Workers limit; zero means defaultPerHostFetches
+	PerHost int
+	// Hosts enforces PerHost; validateConfig creates it when unset
+	Hosts *rss.HostLimiter
+	// Hook, if set, is run for every newly saved post
+	Hook *PostHook
+	// Health, if set, records feeds that suddenly return no items
+	Health   FeedHealthRecorder
+	Discover func(ctx context.Context, client *http.Client, pageURL string) (string, error)
+	// FeedTimeout bounds the work on each feed, independently of the run's deadline
+	FeedTimeout time.Duration
+	// Retries is how many extra attempts a fetch gets after a network error
+	// or 5xx response; zero disables retrying
+	Retries int
+	// Fetches, if set, records each feed's success or failure for backoff
+	Fetches FeedFetchRecorder
+	// Moves, if set, updates the URL of feeds that permanently redirect
+	Moves FeedMoveRecorder
+	// Credentials, if set, supplies Basic Auth credentials for private feeds
+	Credentials FeedCredentialSource
+}
+
+// Close releases the prepared statements used by the aggregation, if any
+func (config AggregationConfig) Close() error {
+	if config.Statements == nil {
+		return nil
+	}
+	return config.Statements.Close()
+}
+
+// defaultFetchRetries is the number of retries `agg all` gives each feed
+const defaultFetchRetries = 2
+
+// defaultPerHostFetches is the per-host fetch limit used when PerHost is unset
+const defaultPerHostFetches = 2
+
+// newHostBreaker returns the circuit breaker used by aggregation: a host is
+// skipped for 10 minutes after 3 failures within 5 minutes
+func newHostBreaker() *rss.CircuitBreaker {
+	return rss.NewCircuitBreaker(3, 5*time.Minute, 10*time.Minute)
 }
 
+// defaultFeedTimeout is the per-feed timeout used when FeedTimeout is unset
+const defaultFeedTimeout = 30 * time.Second
+
 // AggregationResult holds the results of feed aggregation
 type AggregationResult struct {
+	FeedsSeen      int
 	FeedsProcessed int
 	TotalPosts     int
 	FetchErrors    int
 	SaveErrors     int
+	Skipped        int
+	PossiblyMoved  int
+	Moved          int
+	Timeouts       int
+	// PerFeed has an entry for every feed that was fetched, in no
+	// particular order
+	PerFeed []FeedResult
+}
+
+// FeedResult is the outcome of aggregating one feed
+type FeedResult struct {
+	URL string
+	// PostsSaved counts the posts that were new to the database
+	PostsSaved int
+	// Err is the fetch or save error, or nil if the feed was aggregated
+	Err error
 }
 
 // validateConfig ensures the aggregation config has valid settings
@@ -901,34 +2586,147 @@ func validateConfig(config *AggregationConfig) {
 	if config.Save == nil {
 		config.Save = rss.SavePostsToDatabase
 	}
+	if config.Breaker == nil {
+		config.Breaker = newHostBreaker()
+	}
+	if config.Discover == nil {
+		config.Discover = func(ctx context.Context, client *http.Client, pageURL string) (string, error) {
+			return rss.DiscoverFeedURL(ctx, client, pageURL, rss.FetchOptions{})
+		}
+	}
+	if config.FeedTimeout <= 0 {
+		config.FeedTimeout = defaultFeedTimeout
+	}
+	if config.PerHost <= 0 {
+		config.PerHost = defaultPerHostFetches
+	}
+	if config.Hosts == nil {
+		config.Hosts = rss.NewHostLimiter(config.PerHost)
+	}
 }
 
 // processFeed processes a single feed and updates shared counters
-func processFeed(ctx context.Context, feedURL string, feedID uuid.UUID, config *AggregationConfig, mu *sync.Mutex, result *AggregationResult) {
-	rssFeed, err := config.Fetch(ctx, config.Client, feedURL)
+func processFeed(ctx context.Context, feed database.GetFeedsWithUsersRow, config *AggregationConfig, mu *sync.Mutex, result *AggregationResult) {
+	feedURL := feed.Url
+
+	// Once the run's deadline has passed, remaining feeds are left for next time
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Skip hosts whose circuit is open after repeated failures
+	host := rss.HostKey(feedURL)
+	if !config.Breaker.Allow(host) {
+		mu.Lock()
+		result.Skipped++
+		mu.Unlock()
+		return
+	}
+
+	// Wait for a turn at a busy host before the feed's own timeout starts
+	if err := config.Hosts.Acquire(ctx, host); err != nil {
+		config.Breaker.Abandon(host)
+		return
+	}
+
+	// A hanging feed only uses up its own timeout, not the whole run's
+	feedCtx, cancel := context.WithTimeout(ctx, config.FeedTimeout)
+	defer cancel()
+	feedCtx = withFeedCredentials(feedCtx, feed, config)
+
+	fetch := rss.FetchFunc(config.Fetch)
+	if config.Retries > 0 {
+		fetch = rss.WithRetry(fetch, config.Retries)
+	}
+	rssFeed, err := fetch(feedCtx, config.Client, feedURL)
+	config.Hosts.Release(host)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching feed %s: %v\n", feedURL, err)
+		if ctx.Err() != nil {
+			// Cut off by the run's deadline, not a problem with the feed
+			config.Breaker.Abandon(host)
+			return
+		}
+		if rss.IsHostFailure(err) {
+			config.Breaker.RecordFailure(host)
+		} else {
+			// The host answered, so only this feed is broken
+			config.Breaker.RecordSuccess(host)
+		}
+		recordFetch(ctx, config, feed, err)
 		mu.Lock()
-		result.FetchErrors++
+		result.PerFeed = append(result.PerFeed, FeedResult{URL: feedURL, Err: err})
+		if errors.Is(feedCtx.Err(), context.DeadlineExceeded) {
+			fmt.Fprintf(os.Stderr, "Timed out fetching feed %s after %s\n", feedURL, config.FeedTimeout)
+			result.Timeouts++
+		} else {
+			fmt.Fprintf(os.Stderr, "Error fetching feed %s: %v\n", feedURL, err)
+			result.FetchErrors++
+		}
 		mu.Unlock()
 		return
 	}
+	config.Breaker.RecordSuccess(host)
+
+	if config.Health != nil && checkFeedHealth(feedCtx, feed, rssFeed, config) {
+		mu.Lock()
+		result.PossiblyMoved++
+		mu.Unlock()
+	}
+
+	if config.Moves != nil && recordFeedMove(feedCtx, feed, rssFeed, config) {
+		mu.Lock()
+		result.Moved++
+		mu.Unlock()
+	}
 
 	// attempt to save and track errors
-	if err := config.Save(ctx, config.DB, rssFeed, feedID); err != nil {
+	created, err := config.Save(feedCtx, config.DB, rssFeed, feed.ID)
+	if err != nil {
+		recordFetch(ctx, config, feed, err)
 		fmt.Fprintf(os.Stderr, "Error saving posts from feed %s: %v\n", feedURL, err)
 		mu.Lock()
 		result.SaveErrors++
+		result.PerFeed = append(result.PerFeed, FeedResult{URL: feedURL, Err: err})
 		mu.Unlock()
 		return
 	}
 
+	// Record the fetch so the least recently fetched feeds can be found later,
+	// and any failure backoff is cleared, even if saving used up the feed's
+	// own timeout
+	recordFetch(ctx, config, feed, nil)
+
+	if config.Hook != nil {
+		for _, post := range created {
+			config.Hook.Run(ctx, feed.Name, post)
+		}
+	}
+
 	mu.Lock()
 	result.FeedsProcessed++
 	result.TotalPosts += len(rssFeed.Channel.Items)
+	result.PerFeed = append(result.PerFeed, FeedResult{URL: feedURL, PostsSaved: len(created)})
 	mu.Unlock()
 }
 
+// recordFetch reports a feed's fetch outcome to config.Fetches, if set: a
+// success when fetchErr is nil, otherwise a failure caused by fetchErr.
+// Failing to record it is logged but doesn't fail the feed.
+func recordFetch(ctx context.Context, config *AggregationConfig, feed database.GetFeedsWithUsersRow, fetchErr error) {
+	if config.Fetches == nil {
+		return
+	}
+	var err error
+	if fetchErr == nil {
+		err = config.Fetches.Succeeded(ctx, feed.ID)
+	} else {
+		err = config.Fetches.Failed(ctx, feed.ID, fetchErr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error recording fetch result for feed %s: %v\n", feed.Url, err)
+	}
+}
+
 // aggregateFeeds concurrently fetches and saves posts for the provided feeds.
 // Returns the number of feeds processed and total posts processed.
 func aggregateFeeds(ctx context.Context, feeds []database.GetFeedsWithUsersRow, config AggregationConfig) AggregationResult {
@@ -937,7 +2735,7 @@ func aggregateFeeds(ctx context.Context, feeds []database.GetFeedsWithUsersRow,
 	sem := make(chan struct{}, config.Workers)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	result := AggregationResult{}
+	result := AggregationResult{FeedsSeen: len(feeds)}
 
 	for _, f := range feeds {
 		wg.Add(1)
@@ -947,7 +2745,7 @@ func aggregateFeeds(ctx context.Context, feeds []database.GetFeedsWithUsersRow,
 			defer wg.Done()
 			defer func() { <-sem }()
 
-			processFeed(ctx, feed.Url, feed.ID, &config, &mu, &result)
+			processFeed(ctx, feed, &config, &mu, &result)
 		}(f)
 	}
 
@@ -955,11 +2753,142 @@ func aggregateFeeds(ctx context.Context, feeds []database.GetFeedsWithUsersRow,
 	return result
 }
 
+// aggBatchSize is how many feeds `agg all` loads from the database at a time
+const aggBatchSize = 500
+
+// feedSource returns up to limit feeds following afterID, the last feed of
+// the previous batch. A uuid.Nil afterID asks for the first batch of a run.
+type feedSource func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error)
+
+// dbFeedSource pages through the feeds in the database, least recently
+// fetched first, so a run cut short by its deadline leaves the freshest feeds
+// for the next one. Batches must be requested in order: each continues from
+// the fetch time of the previous batch's last feed.
+func dbFeedSource(db *database.Queries) feedSource {
+	var startedAt time.Time
+	var afterFetchedAt sql.NullTime
+	return func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error) {
+		if afterID == uuid.Nil {
+			// Feeds fetched once the run has started aren't picked up again
+			startedAt = time.Now()
+			afterFetchedAt = sql.NullTime{}
+		}
+		rows, err := db.GetFeedsBatch(ctx, database.GetFeedsBatchParams{
+			StartedAt:      startedAt,
+			AfterFetchedAt: afterFetchedAt,
+			AfterID:        afterID,
+			Limit:          limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		feeds := make([]database.GetFeedsWithUsersRow, len(rows))
+		for i, row := range rows {
+			feeds[i] = database.GetFeedsWithUsersRow{
+				ID:          row.ID,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+				Name:        row.Name,
+				Url:         row.Url,
+				UserID:      row.UserID,
+				UserName:    row.UserName,
+				Description: row.Description,
+			}
+		}
+		if len(rows) > 0 {
+			afterFetchedAt = rows[len(rows)-1].LastFetchedAt
+		}
+		return feeds, nil
+	}
+}
+
+// aggregateFeedsBatched is like aggregateFeeds but loads feeds from source one
+// batch at a time and feeds them to a fixed pool of workers, so memory use is
+// bounded by the batch size rather than the total number of feeds.
+// An error is returned if a batch can't be loaded; feeds already dispatched
+// are still processed and counted.
+func aggregateFeedsBatched(ctx context.Context, source feedSource, batchSize int32, config AggregationConfig) (AggregationResult, error) {
+	validateConfig(&config)
+	if batchSize <= 0 {
+		batchSize = aggBatchSize
+	}
+
+	jobs := make(chan database.GetFeedsWithUsersRow)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	result := AggregationResult{}
+
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feed := range jobs {
+				processFeed(ctx, feed, &config, &mu, &result)
+			}
+		}()
+	}
+
+	var sourceErr error
+	afterID := uuid.Nil
+produce:
+	for {
+		batch, err := source(ctx, afterID, batchSize)
+		if err != nil {
+			sourceErr = err
+			break
+		}
+		for _, feed := range batch {
+			select {
+			case jobs <- feed:
+				mu.Lock()
+				result.FeedsSeen++
+				mu.Unlock()
+			case <-ctx.Done():
+				break produce
+			}
+		}
+		if len(batch) < int(batchSize) {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+	close(jobs)
+
+	wg.Wait()
+	return result, sourceErr
+}
+
+// offlineCommands don't need the database, so they still work when it can't
+// be reached or the config is invalid (e.g. `gator config edit` to fix db_url)
+var offlineCommands = map[string]bool{"config": true, "profile": true, "logout": true}
+
+// dbPingTimeout bounds the connection check made at startup
+const dbPingTimeout = 5 * time.Second
+
+// configureDBPool applies the connection pool settings to db
+func configureDBPool(db *sql.DB, pool config.DBPool) {
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+}
+
+// pingDatabase checks that db can be reached
+func pingDatabase(db *sql.DB) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbPingTimeout)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
 func main() {
+	// Global flags may appear anywhere on the command line
+	quiet, args := hasFlag(os.Args[1:], "--quiet")
+
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		// Don't exit if .env file doesn't exist, just log a warning
-		fmt.Fprintf(os.Stderr, "Warning: Could not load .env file: %v\n", err)
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Warning: Could not load .env file: %v\n", err)
+		}
 	}
 
 	// Read the config file
@@ -968,6 +2897,24 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
 		os.Exit(1)
 	}
+	if warning := currentUserWarning(cfg.CurrentUser()); warning != "" && !quiet {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Error: not enough arguments. Usage: gator [--quiet] <command> [args...]")
+		os.Exit(1)
+	}
+	cmd := command{name: args[0], args: args[1:]}
+
+	// Offline commands run on an invalid config so that it can be repaired
+	if !offlineCommands[cmd.name] {
+		if err := cfg.Validate(); err != nil {
+			path, _ := config.Path()
+			fmt.Fprintf(os.Stderr, "Error reading config: invalid config %s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
 
 	// Open database connection
 	db, err := sql.Open("postgres", cfg.DbURL)
@@ -976,19 +2923,27 @@ func main() {
 		os.Exit(1)
 	}
 	defer db.Close()
+	configureDBPool(db, cfg.DBPool())
 
 	dbQueries := database.New(db)
-	appState := &state{
-		db:  dbQueries,
-		cfg: &cfg,
-	}
+	appState := newState(dbQueries, cfg, quiet)
+	appState.conn = db
 
 	cmds := &commands{handlers: make(map[string]func(*state, command) error)}
 	cmds.register("login", handlerLogin)
+	cmds.register("logout", handlerLogout)
 	cmds.register("register", handlerRegister)
 	cmds.register("reset", handlerReset)
 	cmds.register("users", handlerUsers)
+	cmds.register("reset-apikey", handlerResetAPIKey)
+	cmds.register("config", handlerConfig)
+	cmds.register("profile", handlerProfile)
 	cmds.register("agg", handlerAgg)
+	cmds.register("doctor", handlerDoctor)
+	cmds.register("stats", handlerStats)
+	cmds.register("export", middlewareLoggedIn(handlerExport))
+	cmds.register("export-posts", middlewareLoggedIn(handlerExportPosts))
+	cmds.register("import-url", middlewareLoggedIn(handlerImportURL))
 	cmds.register("serve", handlerServe)
 	cmds.register("tui", middlewareLoggedIn(handlerTUI))
 	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed))
@@ -996,27 +2951,28 @@ func main() {
 	cmds.register("follow", middlewareLoggedIn(handlerFollow))
 	cmds.register("following", middlewareLoggedIn(handlerFollowing))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow))
+	cmds.register("deletefeed", middlewareLoggedIn(handlerDeleteFeed))
+	cmds.register("renamefeed", middlewareLoggedIn(handlerRenameFeed))
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
 	cmds.register("search", middlewareLoggedIn(handlerSearch))
+	cmds.register("posts", middlewareLoggedIn(handlerPosts))
 	cmds.register("bookmark", middlewareLoggedIn(handlerBookmark))
 	cmds.register("unbookmark", middlewareLoggedIn(handlerUnbookmark))
 	cmds.register("bookmarks", middlewareLoggedIn(handlerBookmarks))
 	cmds.register("like", middlewareLoggedIn(handlerLike))
 	cmds.register("unlike", middlewareLoggedIn(handlerUnlike))
 	cmds.register("likes", middlewareLoggedIn(handlerLikes))
-
-	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Error: not enough arguments. Usage: gator <command> [args...]")
-		os.Exit(1)
+	cmds.register("mark-read", middlewareLoggedIn(handlerMarkRead))
+	cmds.register("mark-unread", middlewareLoggedIn(handlerMarkUnread))
+
+	if !offlineCommands[cmd.name] {
+		// Fail fast on a database that can't be reached
+		if err := pingDatabase(db); err != nil {
+			fmt.Fprintf(os.Stderr, "Error connecting to database: %v\n", err)
+			os.Exit(1)
+		}
 	}
-
-	cmdName := os.Args[1]
-	cmdArgs := os.Args[2:]
-	cmd := command{name: cmdName, args: cmdArgs}
-
-	err = cmds.run(appState, cmd)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	if code := runCommand(cmds, appState, cmd); code != 0 {
+		os.Exit(code)
 	}
 }
@@ -1,10 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"gator/internal/config"
 	"gator/internal/database"
 	"gator/internal/rss"
 
@@ -15,13 +26,26 @@ func TestAggregateFeeds_WithFakes(t *testing.T) {
 	feeds := []database.GetFeedsWithUsersRow{
 		{ID: uuid.New(), Name: "a", Url: "u1"},
 		{ID: uuid.New(), Name: "b", Url: "u2"},
+		{ID: uuid.New(), Name: "c", Url: "u3"},
+		{ID: uuid.New(), Name: "d", Url: "u4"},
 	}
+	fetchErr := errors.New("connection refused")
+	saveErr := errors.New("disk full")
 
 	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if url == "u3" {
+			return nil, fetchErr
+		}
 		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
 	}
-	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) error {
-		return nil
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		switch feedID {
+		case feeds[0].ID:
+			return []database.Post{{ID: uuid.New()}}, nil
+		case feeds[3].ID:
+			return nil, saveErr
+		}
+		return nil, nil
 	}
 
 	config := AggregationConfig{
@@ -41,6 +65,36 @@ func TestAggregateFeeds_WithFakes(t *testing.T) {
 	if result.TotalPosts != 2 {
 		t.Fatalf("expected TotalPosts 2, got %d", result.TotalPosts)
 	}
+	if result.FetchErrors != 1 || result.SaveErrors != 1 {
+		t.Fatalf("expected 1 fetch and 1 save error, got %+v", result)
+	}
+
+	perFeed := map[string]FeedResult{}
+	for _, r := range result.PerFeed {
+		perFeed[r.URL] = r
+	}
+	if len(result.PerFeed) != 4 || len(perFeed) != 4 {
+		t.Fatalf("expected one result per feed, got %+v", result.PerFeed)
+	}
+	if r := perFeed["u1"]; r.PostsSaved != 1 || r.Err != nil {
+		t.Fatalf("expected u1 to save 1 post, got %+v", r)
+	}
+	if r := perFeed["u2"]; r.PostsSaved != 0 || r.Err != nil {
+		t.Fatalf("expected u2 to succeed with no new posts, got %+v", r)
+	}
+	if r := perFeed["u3"]; !errors.Is(r.Err, fetchErr) {
+		t.Fatalf("expected u3 to record the fetch error, got %+v", r)
+	}
+	if r := perFeed["u4"]; !errors.Is(r.Err, saveErr) {
+		t.Fatalf("expected u4 to record the save error, got %+v", r)
+	}
+
+	var out bytes.Buffer
+	printFailedFeeds(&out, result.PerFeed)
+	want := "Failed feeds:\n* u3: connection refused\n* u4: disk full\n"
+	if out.String() != want {
+		t.Fatalf("printFailedFeeds output = %q; want %q", out.String(), want)
+	}
 }
 
 func TestAggregationConfig_DefaultValues(t *testing.T) {
@@ -62,4 +116,736 @@ func TestAggregationConfig_DefaultValues(t *testing.T) {
 	if config.Save == nil {
 		t.Fatalf("expected Save to be set to default")
 	}
+	if config.Breaker == nil {
+		t.Fatalf("expected Breaker to be set to default")
+	}
+	if config.FeedTimeout != defaultFeedTimeout {
+		t.Fatalf("expected FeedTimeout to default to %v, got %v", defaultFeedTimeout, config.FeedTimeout)
+	}
+}
+
+func TestAggregateFeeds_PerFeedTimeout(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "slow", Url: "https://slow.example.com/feed"},
+		{ID: uuid.New(), Name: "fast", Url: "https://fast.example.com/feed"},
+	}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if url == "https://slow.example.com/feed" {
+			// Hang until the per-feed deadline fires
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	config := AggregationConfig{
+		Workers:     1,
+		Fetch:       fetch,
+		Save:        save,
+		Client:      &http.Client{},
+		FeedTimeout: 20 * time.Millisecond,
+	}
+
+	// The run's own deadline is far away, so only the slow feed's timeout fires
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result := aggregateFeeds(ctx, feeds, config)
+
+	if result.Timeouts != 1 {
+		t.Fatalf("expected 1 timeout, got %d", result.Timeouts)
+	}
+	if result.FetchErrors != 0 {
+		t.Fatalf("expected the timeout not to count as a fetch error, got %d", result.FetchErrors)
+	}
+	if result.FeedsProcessed != 1 {
+		t.Fatalf("expected the fast feed to still be processed, got %d", result.FeedsProcessed)
+	}
+}
+
+// errConnRefused is a network error, as returned when a host is down
+var errConnRefused = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func TestAggregateFeeds_SkipsOpenCircuitHosts(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "d1", Url: "https://dead.example.com/1"},
+		{ID: uuid.New(), Name: "d2", Url: "https://dead.example.com/2"},
+		{ID: uuid.New(), Name: "d3", Url: "https://dead.example.com/3"},
+		{ID: uuid.New(), Name: "d4", Url: "https://dead.example.com/4"},
+		{ID: uuid.New(), Name: "ok", Url: "https://alive.example.com/feed"},
+	}
+
+	var mu sync.Mutex
+	fetched := map[string]int{}
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		host := rss.HostKey(url)
+		mu.Lock()
+		fetched[host]++
+		mu.Unlock()
+		if host == "dead.example.com" {
+			return nil, errConnRefused
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	config := AggregationConfig{
+		Workers: 1, // sequential so the breaker trips deterministically
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Breaker: rss.NewCircuitBreaker(2, time.Minute, time.Hour),
+	}
+
+	result := aggregateFeeds(context.Background(), feeds, config)
+
+	if fetched["dead.example.com"] != 2 {
+		t.Fatalf("expected 2 fetches to dead host before opening, got %d", fetched["dead.example.com"])
+	}
+	if result.FetchErrors != 2 {
+		t.Fatalf("expected FetchErrors 2, got %d", result.FetchErrors)
+	}
+	if result.Skipped != 2 {
+		t.Fatalf("expected Skipped 2, got %d", result.Skipped)
+	}
+	if result.FeedsProcessed != 1 {
+		t.Fatalf("expected FeedsProcessed 1, got %d", result.FeedsProcessed)
+	}
+}
+
+func TestAggregateFeeds_FeedErrorsDontTripBreaker(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "g1", Url: "https://blogs.example.com/gone-1"},
+		{ID: uuid.New(), Name: "g2", Url: "https://blogs.example.com/gone-2"},
+		{ID: uuid.New(), Name: "g3", Url: "https://blogs.example.com/gone-3"},
+		{ID: uuid.New(), Name: "ok", Url: "https://blogs.example.com/ok"},
+	}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if strings.Contains(url, "gone") {
+			return nil, &rss.StatusError{StatusCode: http.StatusGone}
+		}
+		return &rss.RSSFeed{}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	config := AggregationConfig{
+		Workers: 1,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Breaker: rss.NewCircuitBreaker(2, time.Minute, time.Hour),
+	}
+
+	result := aggregateFeeds(context.Background(), feeds, config)
+	if result.Skipped != 0 || result.FeedsProcessed != 1 {
+		t.Fatalf("expected a healthy feed on the same host to be fetched, got %+v", result)
+	}
+}
+
+func TestNewAggregationConfig_OpenHostStaysSkippedOnNextRun(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "d1", Url: "https://dead.example.com/1"},
+		{ID: uuid.New(), Name: "d2", Url: "https://dead.example.com/2"},
+		{ID: uuid.New(), Name: "d3", Url: "https://dead.example.com/3"},
+	}
+
+	var mu sync.Mutex
+	fetches := 0
+	s, _, _ := newTestState(nil, false)
+	config := newAggregationConfig(s, 1)
+	config.Fetch = func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		return nil, errConnRefused
+	}
+	config.Save = func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+	config.Retries = 0
+	config.Health, config.Fetches, config.Moves, config.Credentials = nil, nil, nil, nil
+
+	// Each tick of `agg <interval>` runs with the same config
+	aggregateFeeds(context.Background(), feeds, config)
+	result := aggregateFeeds(context.Background(), feeds, config)
+
+	if fetches != 3 {
+		t.Fatalf("expected the dead host to be fetched 3 times before its circuit opened, got %d", fetches)
+	}
+	if result.Skipped != 3 {
+		t.Fatalf("expected every feed on the open host to be skipped on the next run, got %+v", result)
+	}
+}
+
+func TestAggregateFeeds_CancelledProbeLetsHostBeProbedAgain(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{{ID: uuid.New(), Name: "d1", Url: "https://dead.example.com/1"}}
+	host := rss.HostKey(feeds[0].Url)
+
+	// With no cooldown the second run's fetch is a half-open probe
+	config := AggregationConfig{
+		Workers: 1,
+		Client:  &http.Client{},
+		Breaker: rss.NewCircuitBreaker(1, time.Minute, 0),
+		Save: func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+			return nil, nil
+		},
+	}
+	config.Fetch = func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		return nil, errConnRefused
+	}
+	aggregateFeeds(context.Background(), feeds, config)
+
+	// The run's deadline cuts the probe off before it has an outcome
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config.Fetch = func(fetchCtx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		cancel()
+		return nil, fetchCtx.Err()
+	}
+	aggregateFeeds(ctx, feeds, config)
+
+	if !config.Breaker.Allow(host) {
+		t.Fatalf("expected the host to be probed again after a cancelled probe")
+	}
+}
+
+func TestAggregateFeeds_LimitsConcurrentFetchesPerHost(t *testing.T) {
+	var feeds []database.GetFeedsWithUsersRow
+	for i := 0; i < 6; i++ {
+		feeds = append(feeds, database.GetFeedsWithUsersRow{ID: uuid.New(), Url: fmt.Sprintf("https://busy.example.com/%d/feed", i)})
+	}
+	for i := 0; i < 2; i++ {
+		feeds = append(feeds, database.GetFeedsWithUsersRow{ID: uuid.New(), Url: fmt.Sprintf("https://other%d.example.com/feed", i)})
+	}
+
+	var mu sync.Mutex
+	inFlight := map[string]int{}
+	maxInFlight := map[string]int{}
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		host := rss.HostKey(url)
+		mu.Lock()
+		inFlight[host]++
+		maxInFlight[host] = max(maxInFlight[host], inFlight[host])
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight[host]--
+		mu.Unlock()
+		return &rss.RSSFeed{}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	config := AggregationConfig{
+		Workers: 8,
+		PerHost: 2,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+	}
+
+	result := aggregateFeeds(context.Background(), feeds, config)
+
+	if result.FeedsProcessed != len(feeds) {
+		t.Fatalf("expected all %d feeds processed, got %+v", len(feeds), result)
+	}
+	if got := maxInFlight["busy.example.com"]; got > 2 {
+		t.Fatalf("expected at most 2 concurrent fetches from busy.example.com, got %d", got)
+	}
+	// Other hosts aren't held up by the busy one
+	if maxInFlight["other0.example.com"] != 1 || maxInFlight["other1.example.com"] != 1 {
+		t.Fatalf("expected the other hosts to be fetched, got %v", maxInFlight)
+	}
+}
+
+func TestAggregateFeeds_RunsPostHookForNewPosts(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{
+		{ID: uuid.New(), Name: "a", Url: "u1"},
+		{ID: uuid.New(), Name: "b", Url: "u2"},
+	}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "old", Link: url + "/old"}, {Title: "new", Link: url + "/new"}}}}, nil
+	}
+	// Only the "new" item of each feed is newly saved
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return []database.Post{{ID: uuid.New(), Title: "new", Url: feed.Channel.Items[1].Link, FeedID: feedID}}, nil
+	}
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	hook := newPostHook("archive", io.Discard)
+	hook.Runner = func(ctx context.Context, command string, env []string) error {
+		if command != "archive" {
+			t.Errorf("command = %q; want %q", command, "archive")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, kv := range env {
+			if url, ok := strings.CutPrefix(kv, "GATOR_POST_URL="); ok {
+				calls[url]++
+			}
+		}
+		return errors.New("hook failures are not fatal")
+	}
+
+	config := AggregationConfig{
+		Workers: 2,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Hook:    hook,
+	}
+
+	result := aggregateFeeds(context.Background(), feeds, config)
+
+	if result.FeedsProcessed != 2 {
+		t.Fatalf("expected FeedsProcessed 2, got %d", result.FeedsProcessed)
+	}
+	want := map[string]int{"u1/new": 1, "u2/new": 1}
+	if len(calls) != len(want) {
+		t.Fatalf("expected hook calls %v, got %v", want, calls)
+	}
+	for url, n := range want {
+		if calls[url] != n {
+			t.Fatalf("expected hook calls %v, got %v", want, calls)
+		}
+	}
+}
+
+// fakeFeedHealth records flags in memory
+type fakeFeedHealth struct {
+	mu       sync.Mutex
+	hasPosts map[uuid.UUID]bool
+	flagged  map[uuid.UUID]string
+}
+
+func (f *fakeFeedHealth) HasPosts(ctx context.Context, feedID uuid.UUID) (bool, error) {
+	return f.hasPosts[feedID], nil
+}
+
+func (f *fakeFeedHealth) Flag(ctx context.Context, feedID uuid.UUID, suggestedURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flagged[feedID] = suggestedURL
+	return nil
+}
+
+func (f *fakeFeedHealth) Clear(ctx context.Context, feedID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.flagged, feedID)
+	return nil
+}
+
+func TestAggregateFeeds_FlagsPreviouslyPopulatedFeedWithNoItems(t *testing.T) {
+	moved := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "moved", Url: "https://old.example.com/feed"}
+	fresh := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "fresh", Url: "https://new.example.com/feed"}
+	healthy := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "healthy", Url: "https://ok.example.com/feed"}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if url == healthy.Url {
+			return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
+		}
+		return &rss.RSSFeed{}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+	discover := func(ctx context.Context, client *http.Client, pageURL string) (string, error) {
+		return "https://old.example.com/new-feed.xml", nil
+	}
+
+	health := &fakeFeedHealth{
+		// The fresh feed has never had posts, so an empty fetch is expected
+		hasPosts: map[uuid.UUID]bool{moved.ID: true, healthy.ID: true},
+		flagged:  map[uuid.UUID]string{healthy.ID: ""},
+	}
+
+	config := AggregationConfig{
+		Workers:  1,
+		Fetch:    fetch,
+		Save:     save,
+		Client:   &http.Client{},
+		Health:   health,
+		Discover: discover,
+	}
+
+	result := aggregateFeeds(context.Background(), []database.GetFeedsWithUsersRow{moved, fresh, healthy}, config)
+
+	if result.PossiblyMoved != 1 {
+		t.Fatalf("expected PossiblyMoved 1, got %d", result.PossiblyMoved)
+	}
+	suggested, ok := health.flagged[moved.ID]
+	if !ok {
+		t.Fatalf("expected previously-populated feed to be flagged")
+	}
+	if suggested != "https://old.example.com/new-feed.xml" {
+		t.Fatalf("suggested URL = %q; want discovered feed URL", suggested)
+	}
+	if _, ok := health.flagged[fresh.ID]; ok {
+		t.Fatalf("expected feed without previous posts not to be flagged")
+	}
+	if _, ok := health.flagged[healthy.ID]; ok {
+		t.Fatalf("expected flag to be cleared for feed that returned items")
+	}
+}
+
+// fakeFeedMoves is a FeedMoveRecorder recording new URLs in memory
+type fakeFeedMoves struct {
+	mu    sync.Mutex
+	moved map[uuid.UUID]string
+}
+
+func (f *fakeFeedMoves) Moved(ctx context.Context, feedID uuid.UUID, newURL string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moved[feedID] = newURL
+	return nil
+}
+
+func TestAggregateFeeds_UpdatesURLOfPermanentlyMovedFeed(t *testing.T) {
+	moved := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "moved", Url: "http://example.com/feed"}
+	temporary := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "temporary", Url: "https://busy.example.com/feed"}
+	stayed := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "stayed", Url: "https://ok.example.com/feed"}
+
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		switch url {
+		case moved.Url:
+			return &rss.RSSFeed{FinalURL: "https://example.com/feed", MovedPermanently: true}, nil
+		case temporary.Url:
+			return &rss.RSSFeed{FinalURL: "https://mirror.example.com/feed"}, nil
+		}
+		return &rss.RSSFeed{}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	moves := &fakeFeedMoves{moved: map[uuid.UUID]string{}}
+	config := AggregationConfig{
+		Workers: 1,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Moves:   moves,
+	}
+
+	result := aggregateFeeds(context.Background(), []database.GetFeedsWithUsersRow{moved, temporary, stayed}, config)
+
+	if result.Moved != 1 {
+		t.Fatalf("expected Moved 1, got %d", result.Moved)
+	}
+	if got := moves.moved[moved.ID]; got != "https://example.com/feed" {
+		t.Fatalf("moved feed URL = %q; want the redirect target", got)
+	}
+	if len(moves.moved) != 1 {
+		t.Fatalf("expected only the permanently moved feed to be updated, got %v", moves.moved)
+	}
+}
+
+func TestAggregateFeedsBatched_ProcessesAllBatches(t *testing.T) {
+	// 7 feeds with increasing IDs, served 3 at a time
+	var all []database.GetFeedsWithUsersRow
+	for i := 1; i <= 7; i++ {
+		id := uuid.UUID{15: byte(i)}
+		all = append(all, database.GetFeedsWithUsersRow{ID: id, Name: fmt.Sprintf("f%d", i), Url: fmt.Sprintf("https://example.com/%d", i)})
+	}
+
+	var pages []uuid.UUID
+	source := func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error) {
+		pages = append(pages, afterID)
+		var batch []database.GetFeedsWithUsersRow
+		for _, feed := range all {
+			if bytes.Compare(feed.ID[:], afterID[:]) > 0 && len(batch) < int(limit) {
+				batch = append(batch, feed)
+			}
+		}
+		return batch, nil
+	}
+
+	var mu sync.Mutex
+	fetched := map[string]int{}
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		mu.Lock()
+		fetched[url]++
+		mu.Unlock()
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: url + "/1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	config := AggregationConfig{
+		Workers: 2,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+	}
+
+	result, err := aggregateFeedsBatched(context.Background(), source, 3, config)
+	if err != nil {
+		t.Fatalf("aggregateFeedsBatched returned error: %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(pages))
+	}
+	if pages[0] != uuid.Nil || pages[1] != all[2].ID || pages[2] != all[5].ID {
+		t.Fatalf("unexpected batch cursors: %v", pages)
+	}
+	if result.FeedsSeen != 7 || result.FeedsProcessed != 7 || result.TotalPosts != 7 {
+		t.Fatalf("expected all 7 feeds processed, got %+v", result)
+	}
+	for _, feed := range all {
+		if fetched[feed.Url] != 1 {
+			t.Fatalf("expected %s to be fetched once, got %d", feed.Url, fetched[feed.Url])
+		}
+	}
+}
+
+func TestAggregateFeedsBatched_SourceError(t *testing.T) {
+	source := func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	_, err := aggregateFeedsBatched(context.Background(), source, 3, AggregationConfig{Workers: 1, Client: &http.Client{}})
+	if err == nil {
+		t.Fatalf("expected source error to be returned")
+	}
+}
+
+func TestAggregateAll_ReportsTruncationAtDeadline(t *testing.T) {
+	var all []database.GetFeedsWithUsersRow
+	for i := 1; i <= 4; i++ {
+		id := uuid.UUID{15: byte(i)}
+		all = append(all, database.GetFeedsWithUsersRow{ID: id, Name: fmt.Sprintf("f%d", i), Url: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	source := func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error) {
+		var batch []database.GetFeedsWithUsersRow
+		for _, feed := range all {
+			if bytes.Compare(feed.ID[:], afterID[:]) > 0 && len(batch) < int(limit) {
+				batch = append(batch, feed)
+			}
+		}
+		return batch, nil
+	}
+
+	// The first two feeds are quick; the rest hang until the run is cut off
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		if url == all[2].Url || url == all[3].Url {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: url + "/1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+	config := AggregationConfig{
+		Workers: 1,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+	}
+
+	s, out, _ := newTestState(nil, false)
+	if err := aggregateAll(s, source, len(all), config, 50*time.Millisecond, false); err != nil {
+		t.Fatalf("expected no error outside strict mode, got %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Aggregation stopped early after") || !strings.Contains(got, "(deadline reached); processed 2/4 feeds") {
+		t.Fatalf("expected truncation message with partial counts, got %q", got)
+	}
+	if strings.Contains(got, "Errors:") || strings.Contains(got, "Timed out on") {
+		t.Fatalf("feeds cut off by the deadline shouldn't be counted as failures, got %q", got)
+	}
+
+	s, out, _ = newTestState(nil, false)
+	if err := aggregateAll(s, source, len(all), config, 50*time.Millisecond, true); err == nil {
+		t.Fatalf("expected strict mode to return an error when the deadline is reached")
+	}
+	if !strings.Contains(out.String(), "processed 2/4 feeds") {
+		t.Fatalf("expected partial counts in strict mode too, got %q", out.String())
+	}
+}
+
+func TestAggregateFeeds_RetriesTransientFetchErrors(t *testing.T) {
+	feeds := []database.GetFeedsWithUsersRow{{ID: uuid.New(), Name: "flaky", Url: "https://flaky.example.com/feed"}}
+
+	attempts := 0
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		attempts++
+		if attempts <= 2 {
+			return nil, &rss.StatusError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+
+	result := aggregateFeeds(context.Background(), feeds, AggregationConfig{
+		Workers: 1,
+		Fetch:   fetch,
+		Save:    save,
+		Client:  &http.Client{},
+		Retries: 2,
+	})
+	if attempts != 3 {
+		t.Fatalf("expected 3 fetch attempts, got %d", attempts)
+	}
+	if result.FetchErrors != 0 || result.FeedsProcessed != 1 {
+		t.Fatalf("expected the feed to succeed after retrying, got %+v", result)
+	}
+}
+
+func TestAggregateEvery_RunsOnEachTick(t *testing.T) {
+	feed := database.GetFeedsWithUsersRow{ID: uuid.UUID{15: 1}, Name: "f", Url: "https://example.com/feed"}
+	source := func(ctx context.Context, afterID uuid.UUID, limit int32) ([]database.GetFeedsWithUsersRow, error) {
+		if afterID == uuid.Nil {
+			return []database.GetFeedsWithUsersRow{feed}, nil
+		}
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	fetches := 0
+	fetch := func(ctx context.Context, client *http.Client, url string) (*rss.RSSFeed, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		if fetches == 2 {
+			// Stop once the second tick has fetched
+			defer cancel()
+		}
+		return &rss.RSSFeed{Channel: rss.RSSChannel{Items: []rss.RSSItem{{Title: "t1", Link: "l1"}}}}, nil
+	}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+	config := AggregationConfig{Workers: 1, Fetch: fetch, Save: save, Client: &http.Client{}}
+
+	s, out, _ := newTestState(nil, false)
+	done := make(chan error, 1)
+	go func() {
+		done <- aggregateEvery(ctx, s, 10*time.Millisecond, source, config)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("aggregateEvery returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("aggregateEvery didn't stop after its context was cancelled")
+	}
+
+	if fetches != 2 {
+		t.Fatalf("expected 2 ticks to fetch, got %d fetches", fetches)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Aggregated 1 feeds") || !strings.Contains(got, "Stopped aggregating.") {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestNewAggregationConfig_SendsConfiguredUserAgent(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>UA</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	s, _, _ := newTestState(nil, false)
+	config := newAggregationConfig(s, 1)
+	if _, err := config.Fetch(context.Background(), config.Client, server.URL); err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	s.cfg.UserAgent = "my-reader/3.1 (ops@example.com)"
+	if _, err := config.Fetch(context.Background(), config.Client, server.URL); err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	if len(got) != 2 || got[0] != rss.DefaultUserAgent || got[1] != "my-reader/3.1 (ops@example.com)" {
+		t.Fatalf("User-Agent headers = %q; want the default, then user_agent from the config", got)
+	}
+}
+
+// fakeFeedCredentials is a FeedCredentialSource holding credentials in memory
+type fakeFeedCredentials map[uuid.UUID]rss.BasicAuth
+
+func (f fakeFeedCredentials) Credentials(ctx context.Context, feedID uuid.UUID) (rss.BasicAuth, bool, error) {
+	auth, ok := f[feedID]
+	return auth, ok, nil
+}
+
+func TestAggregateFeeds_SendsBasicAuthForPrivateFeeds(t *testing.T) {
+	var mu sync.Mutex
+	headers := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers[r.URL.Path] = r.Header.Get("Authorization")
+		mu.Unlock()
+		if user, pass, ok := r.BasicAuth(); r.URL.Path == "/private" && (!ok || user != "alice" || pass != "s3cret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	private := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "private", Url: server.URL + "/private"}
+	public := database.GetFeedsWithUsersRow{ID: uuid.New(), Name: "public", Url: server.URL + "/public"}
+	save := func(ctx context.Context, db *database.Queries, feed *rss.RSSFeed, feedID uuid.UUID) ([]database.Post, error) {
+		return nil, nil
+	}
+	config := AggregationConfig{
+		Workers:     1,
+		Save:        save,
+		Client:      rss.NewHTTPClient(),
+		Credentials: fakeFeedCredentials{private.ID: {Username: "alice", Password: "s3cret"}},
+	}
+
+	result := aggregateFeeds(context.Background(), []database.GetFeedsWithUsersRow{private, public}, config)
+
+	if result.FeedsProcessed != 2 || result.FetchErrors != 0 {
+		t.Fatalf("expected both feeds to be fetched, got %+v", result)
+	}
+	if want := "Basic YWxpY2U6czNjcmV0"; headers["/private"] != want {
+		t.Fatalf("private feed Authorization = %q; want %q", headers["/private"], want)
+	}
+	if headers["/public"] != "" {
+		t.Fatalf("public feed was sent credentials: %q", headers["/public"])
+	}
+}
+
+func TestConfigureDBPool_AppliesSettings(t *testing.T) {
+	// sql.Open doesn't connect, so no database is needed
+	db, err := sql.Open("postgres", "postgres://gator@localhost:1/gator?sslmode=disable")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	configureDBPool(db, config.DBPool{MaxOpenConns: 7, MaxIdleConns: 2, ConnMaxLifetime: time.Minute})
+	if got := db.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("expected MaxOpenConnections 7, got %d", got)
+	}
 }
@@ -1,6 +1,15 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gator/internal/config"
+)
 
 func TestParsePageArg_Valid(t *testing.T) {
 	cases := map[string]int32{
@@ -27,3 +36,269 @@ func TestParsePageArg_Invalid(t *testing.T) {
 		}
 	}
 }
+
+func TestHasFlag(t *testing.T) {
+	found, rest := hasFlag([]string{"2", "--json", "x"}, "--json")
+	if !found {
+		t.Fatalf("expected --json to be found")
+	}
+	if len(rest) != 2 || rest[0] != "2" || rest[1] != "x" {
+		t.Fatalf("unexpected remaining args: %v", rest)
+	}
+
+	found, rest = hasFlag([]string{"2"}, "--json")
+	if found || len(rest) != 1 {
+		t.Fatalf("expected flag to be absent, got found=%v rest=%v", found, rest)
+	}
+}
+
+func TestFlagValue(t *testing.T) {
+	value, rest, err := flagValue([]string{"--format", "json", "x"}, "--format")
+	if err != nil || value != "json" || len(rest) != 1 || rest[0] != "x" {
+		t.Fatalf("unexpected result: value=%q rest=%v err=%v", value, rest, err)
+	}
+
+	value, rest, err = flagValue([]string{"--format=json"}, "--format")
+	if err != nil || value != "json" || len(rest) != 0 {
+		t.Fatalf("unexpected result: value=%q rest=%v err=%v", value, rest, err)
+	}
+
+	if _, _, err := flagValue([]string{"--format"}, "--format"); err == nil {
+		t.Fatalf("expected error for flag without a value")
+	}
+}
+
+func TestParseBrowseArgs_FeedFlagAnyPosition(t *testing.T) {
+	cases := [][]string{
+		{"--feed", "https://a.example.com/feed.xml", "2"},
+		{"2", "--feed", "https://a.example.com/feed.xml"},
+		{"2", "--feed=https://a.example.com/feed.xml"},
+	}
+	for _, args := range cases {
+		opts, err := parseBrowseArgs(args)
+		if err != nil {
+			t.Fatalf("parseBrowseArgs(%v) returned error: %v", args, err)
+		}
+		if opts.filter.feed != "https://a.example.com/feed.xml" || opts.page != 2 {
+			t.Fatalf("parseBrowseArgs(%v) = %+v; want feed URL on page 2", args, opts)
+		}
+	}
+
+	opts, err := parseBrowseArgs([]string{"--feed", "Tech News"})
+	if err != nil || opts.filter.feed != "Tech News" || opts.page != 1 {
+		t.Fatalf("unexpected result: opts=%+v err=%v", opts, err)
+	}
+}
+
+func TestParseBrowseArgs_Sort(t *testing.T) {
+	opts, err := parseBrowseArgs([]string{"3", "--sort", "oldest"})
+	if err != nil || opts.filter.sort != browseSortOldest || opts.page != 3 {
+		t.Fatalf("unexpected result: opts=%+v err=%v", opts, err)
+	}
+	if got := opts.command(); got != "gator browse --sort oldest" {
+		t.Fatalf("command() = %q", got)
+	}
+
+	// newest is the default order, so it combines with any filter
+	if _, err := parseBrowseArgs([]string{"--liked", "--sort", "newest"}); err != nil {
+		t.Fatalf("expected --sort newest to combine with --liked, got %v", err)
+	}
+}
+
+func TestParseBrowseArgs_Invalid(t *testing.T) {
+	cases := [][]string{
+		{"--feed"},
+		{"--feed", "A", "--liked"},
+		{"--feed", "A", "--tag", "later"},
+		{"--tag", "later", "--category", "go"},
+		{"--feed", "A", "new"},
+		{"--unread", "--liked"},
+		{"--feed", "A", "zero"},
+		{"--sort", "random"},
+		{"--sort", "oldest", "--liked"},
+		{"--sort", "added", "new"},
+	}
+	for _, args := range cases {
+		if _, err := parseBrowseArgs(args); err == nil {
+			t.Fatalf("expected error for args %v", args)
+		}
+	}
+}
+
+func TestPrintCompactPost_OneLinePerPost(t *testing.T) {
+	var out bytes.Buffer
+	printCompactPost(&out, 1, "First post", "Blog")
+	printCompactPost(&out, 2, "Second post", "News")
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), out.String())
+	}
+	if lines[0] != "1. First post — Blog" || lines[1] != "2. Second post — News" {
+		t.Fatalf("unexpected compact output: %q", lines)
+	}
+}
+
+func TestAggWorkers(t *testing.T) {
+	cfg := &config.Config{AggWorkers: 12}
+
+	if w, err := aggWorkers(nil, cfg); err != nil || w != 12 {
+		t.Fatalf("expected config value 12 without a flag, got %d (err %v)", w, err)
+	}
+	if w, err := aggWorkers([]string{"--workers", "3"}, cfg); err != nil || w != 3 {
+		t.Fatalf("expected --workers to override the config, got %d (err %v)", w, err)
+	}
+	if w, err := aggWorkers([]string{"4"}, cfg); err != nil || w != 4 {
+		t.Fatalf("expected a bare number to override the config, got %d (err %v)", w, err)
+	}
+	if w, err := aggWorkers(nil, &config.Config{}); err != nil || w != defaultAggWorkers {
+		t.Fatalf("expected default %d without config or flag, got %d (err %v)", defaultAggWorkers, w, err)
+	}
+	if _, err := aggWorkers([]string{"--workers", "0"}, cfg); err == nil {
+		t.Fatalf("expected an error for a non-positive worker count")
+	}
+}
+
+func TestParseLimitFlag(t *testing.T) {
+	limit, rest, err := parseLimitFlag([]string{"rust", "--limit", "20", "2"})
+	if err != nil || limit != 20 || len(rest) != 2 || rest[0] != "rust" || rest[1] != "2" {
+		t.Fatalf("unexpected result: limit=%d rest=%v err=%v", limit, rest, err)
+	}
+
+	limit, rest, err = parseLimitFlag([]string{"2"})
+	if err != nil || limit != 0 || len(rest) != 1 {
+		t.Fatalf("expected 0 without the flag, got limit=%d rest=%v err=%v", limit, rest, err)
+	}
+
+	if limit, _, err := parseLimitFlag([]string{"--limit=5000"}); err != nil || limit != maxPageSize {
+		t.Fatalf("expected the limit clamped to %d, got %d (err %v)", maxPageSize, limit, err)
+	}
+
+	for _, value := range []string{"0", "-3", "ten"} {
+		if _, _, err := parseLimitFlag([]string{"--limit", value}); err == nil {
+			t.Fatalf("expected an error for --limit %s", value)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	if n := pageSize(0, &config.Config{}); n != defaultPageSize {
+		t.Fatalf("expected default %d without config or flag, got %d", defaultPageSize, n)
+	}
+	if n := pageSize(0, &config.Config{PageSize: 15}); n != 15 {
+		t.Fatalf("expected config value 15 without a flag, got %d", n)
+	}
+	if n := pageSize(7, &config.Config{PageSize: 15}); n != 7 {
+		t.Fatalf("expected --limit to override the config, got %d", n)
+	}
+	if n := pageSize(0, &config.Config{PageSize: 1000}); n != maxPageSize {
+		t.Fatalf("expected the config value clamped to %d, got %d", maxPageSize, n)
+	}
+}
+
+func TestParseBrowseArgs_LimitInPaginationHint(t *testing.T) {
+	opts, err := parseBrowseArgs([]string{"--unread", "--limit", "20", "2"})
+	if err != nil || opts.limit != 20 || opts.page != 2 {
+		t.Fatalf("unexpected result: opts=%+v err=%v", opts, err)
+	}
+	if got := opts.command(); got != "gator browse --unread --limit 20" {
+		t.Fatalf("command() = %q", got)
+	}
+}
+
+func TestCurrentUserWarning(t *testing.T) {
+	for _, name := range []string{"", "alice", "bob.smith"} {
+		if warning := currentUserWarning(name); warning != "" {
+			t.Errorf("currentUserWarning(%q) = %q; want no warning", name, warning)
+		}
+	}
+	for _, name := range []string{"a", "has space", "browse"} {
+		if warning := currentUserWarning(name); !strings.Contains(warning, name) {
+			t.Errorf("currentUserWarning(%q) = %q; want a warning naming the user", name, warning)
+		}
+	}
+}
+
+func TestTruncateDescription_KeepsRunesWhole(t *testing.T) {
+	// 199 ASCII bytes then a 4-byte emoji, so byte 200 falls inside the emoji
+	desc := strings.Repeat("a", 199) + strings.Repeat("🎉", 5)
+
+	got := truncateDescription(desc)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated description is not valid UTF-8: %q", got)
+	}
+	if want := strings.Repeat("a", 199) + "🎉..."; got != want {
+		t.Fatalf("truncateDescription = %q; want %q", got, want)
+	}
+	if short := "héllo wörld"; truncateDescription(short) != short {
+		t.Fatalf("expected short description to be unchanged")
+	}
+}
+
+func TestServeRateLimit(t *testing.T) {
+	rps, burst, rest, err := serveRateLimit([]string{"3000"})
+	if err != nil || rps != defaultRateLimit || burst != defaultRateBurst || len(rest) != 1 {
+		t.Fatalf("unexpected defaults: rps=%v burst=%d rest=%v err=%v", rps, burst, rest, err)
+	}
+
+	rps, burst, rest, err = serveRateLimit([]string{"--rate-limit", "2.5", "3000", "--rate-burst=5"})
+	if err != nil || rps != 2.5 || burst != 5 || len(rest) != 1 || rest[0] != "3000" {
+		t.Fatalf("unexpected result: rps=%v burst=%d rest=%v err=%v", rps, burst, rest, err)
+	}
+
+	for _, args := range [][]string{{"--rate-limit", "fast"}, {"--rate-limit", "-1"}, {"--rate-limit", "NaN"}, {"--rate-limit", "Inf"}, {"--rate-limit", "-inf"}, {"--rate-burst", "0"}} {
+		if _, _, _, err := serveRateLimit(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestServePort(t *testing.T) {
+	cases := []struct {
+		args    []string
+		envPort string
+		want    string
+	}{
+		{nil, "", "8080"},
+		{nil, "9000", "9000"},
+		{[]string{"3000"}, "9000", "3000"},
+		{[]string{"3000"}, "", "3000"},
+	}
+	for _, tc := range cases {
+		if got := servePort(tc.args, tc.envPort); got != tc.want {
+			t.Errorf("servePort(%v, %q) = %q; want %q", tc.args, tc.envPort, got, tc.want)
+		}
+	}
+}
+
+func TestHandlerConfigEdit_RepairsInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	t.Setenv("GATOR_CONFIG", path)
+	if err := os.WriteFile(path, []byte(`{"db_url": "mysql://root@localhost/gator"}`), 0644); err != nil {
+		t.Fatalf("couldn't write config: %v", err)
+	}
+	fixed := filepath.Join(dir, "fixed.json")
+	if err := os.WriteFile(fixed, []byte(`{"db_url": "postgres://localhost:5432/gator"}`), 0644); err != nil {
+		t.Fatalf("couldn't write config: %v", err)
+	}
+	// The "editor" replaces the config with the fixed one
+	t.Setenv("EDITOR", "cp "+fixed)
+
+	cfg, err := config.Read()
+	if err != nil {
+		t.Fatalf("config.Read returned error for an invalid config: %v", err)
+	}
+	s := newState(nil, cfg, true)
+	if err := handlerConfig(s, command{name: "config", args: []string{"edit"}}); err != nil {
+		t.Fatalf("config edit returned error: %v", err)
+	}
+
+	cfg, err = config.Read()
+	if err != nil {
+		t.Fatalf("config.Read returned error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected the repaired config to be valid, got %v", err)
+	}
+}
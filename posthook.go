@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// postHookTimeout bounds how long a single on_new_post_cmd invocation may run
+const postHookTimeout = 30 * time.Second
+
+// maxConcurrentPostHooks caps how many on_new_post_cmd invocations run at once
+const maxConcurrentPostHooks = 4
+
+// commandRunner runs a shell command with extra environment variables
+type commandRunner func(ctx context.Context, command string, env []string) error
+
+// PostHook runs a user-configured command for each new post found by agg.
+// Failures are logged and never abort aggregation.
+type PostHook struct {
+	Command string
+	Timeout time.Duration
+	Runner  commandRunner
+
+	sem    chan struct{}
+	errOut io.Writer
+}
+
+// newPostHook returns a PostHook that runs command through the shell
+func newPostHook(command string, errOut io.Writer) *PostHook {
+	return &PostHook{
+		Command: command,
+		Timeout: postHookTimeout,
+		Runner:  runShellCommand,
+		sem:     make(chan struct{}, maxConcurrentPostHooks),
+		errOut:  errOut,
+	}
+}
+
+// Run invokes the hook for post, waiting for a free slot if too many are running.
+// The post is exposed to the command as GATOR_POST_TITLE, GATOR_POST_URL, and GATOR_FEED_NAME.
+func (h *PostHook) Run(ctx context.Context, feedName string, post database.Post) {
+	select {
+	case h.sem <- struct{}{}:
+		defer func() { <-h.sem }()
+	case <-ctx.Done():
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	env := []string{
+		"GATOR_POST_TITLE=" + post.Title,
+		"GATOR_POST_URL=" + post.Url,
+		"GATOR_FEED_NAME=" + feedName,
+	}
+	if err := h.Runner(ctx, h.Command, env); err != nil {
+		fmt.Fprintf(h.errOut, "on_new_post_cmd failed for %s: %v\n", post.Url, err)
+	}
+}
+
+// runShellCommand runs command with sh -c, adding env to the current environment
+func runShellCommand(ctx context.Context, command string, env []string) error {
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	c.Env = append(os.Environ(), env...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
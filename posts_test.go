@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+	"gator/internal/rss"
+
+	"github.com/google/uuid"
+)
+
+func TestGetRecentPosts_OrderedNewestFirstAcrossFeeds(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, bob, "B", "https://b.example.com/feed.xml")
+
+	now := time.Now().UTC()
+	createTestPost(t, db, feedA, "week old", "https://a.example.com/1", now.Add(-7*24*time.Hour))
+	createTestPost(t, db, feedB, "yesterday", "https://b.example.com/1", now.Add(-24*time.Hour))
+	createTestPost(t, db, feedA, "undated", "https://a.example.com/2", time.Time{})
+	createTestPost(t, db, feedB, "an hour ago", "https://b.example.com/2", now.Add(-time.Hour))
+
+	posts, err := db.GetRecentPosts(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetRecentPosts returned error: %v", err)
+	}
+
+	want := []string{"an hour ago", "yesterday", "week old"}
+	if len(posts) != len(want) {
+		t.Fatalf("expected %d posts, got %d", len(want), len(posts))
+	}
+	for i, title := range want {
+		if posts[i].Title != title {
+			t.Fatalf("post %d = %q; want %q", i, posts[i].Title, title)
+		}
+	}
+	if posts[0].FeedName != "B" {
+		t.Fatalf("expected feed name B, got %q", posts[0].FeedName)
+	}
+}
+
+func TestFindPostByURL_ExactAndNormalized(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	target := createTestPost(t, db, feed, "target", "https://a.example.com/posts/1", time.Now())
+	createTestPost(t, db, feed, "other", "https://a.example.com/posts/2", time.Now())
+
+	for _, url := range []string{
+		"https://a.example.com/posts/1",
+		"https://A.example.com/posts/1/?utm_source=newsletter#comments",
+	} {
+		post, err := findPostByURL(context.Background(), db, url)
+		if err != nil {
+			t.Fatalf("findPostByURL(%q) returned error: %v", url, err)
+		}
+		if post.ID != target.ID {
+			t.Fatalf("findPostByURL(%q) = %q; want %q", url, post.Title, target.Title)
+		}
+	}
+
+	if _, err := findPostByURL(context.Background(), db, "https://a.example.com/posts/3"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows for unknown URL, got %v", err)
+	}
+}
+
+func TestGetBookmarksForUser_ScopedToFeed(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, alice, "B", "https://b.example.com/feed.xml")
+
+	now := time.Now().UTC()
+	a1 := createTestPost(t, db, feedA, "a1", "https://a.example.com/1", now)
+	a2 := createTestPost(t, db, feedA, "a2", "https://a.example.com/2", now)
+	b1 := createTestPost(t, db, feedB, "b1", "https://b.example.com/1", now)
+	for _, post := range []database.Post{a1, b1, a2} {
+		bookmarkTestPost(t, db, alice, post)
+	}
+
+	all, err := db.GetBookmarksForUser(ctx, database.GetBookmarksForUserParams{UserID: alice.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetBookmarksForUser returned error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 bookmarks without a feed scope, got %d", len(all))
+	}
+
+	scoped, err := db.GetBookmarksForUser(ctx, database.GetBookmarksForUserParams{
+		UserID: alice.ID,
+		Limit:  10,
+		FeedID: uuid.NullUUID{UUID: feedA.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("GetBookmarksForUser returned error: %v", err)
+	}
+	if len(scoped) != 2 {
+		t.Fatalf("expected 2 bookmarks from feed A, got %d", len(scoped))
+	}
+	for _, bookmark := range scoped {
+		if bookmark.FeedID != feedA.ID {
+			t.Fatalf("expected only feed A bookmarks, got %q from %s", bookmark.Title, bookmark.FeedName)
+		}
+	}
+
+	// Pagination applies within the scope
+	page2, err := db.GetBookmarksForUser(ctx, database.GetBookmarksForUserParams{
+		UserID: alice.ID,
+		Limit:  1,
+		Offset: 1,
+		FeedID: uuid.NullUUID{UUID: feedA.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("GetBookmarksForUser returned error: %v", err)
+	}
+	if len(page2) != 1 || page2[0].FeedID != feedA.ID {
+		t.Fatalf("expected one feed A bookmark on page 2, got %+v", page2)
+	}
+}
+
+func TestFindDuplicatePosts_GroupsAcrossFeeds(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, alice, "B", "https://b.example.com/feed.xml")
+
+	now := time.Now().UTC()
+	createTestPost(t, db, feedA, "Go 1.24 released", "https://news.example.com/go", now)
+	createTestPost(t, db, feedB, "go 1.24   Released", "https://mirror.example.com/go-release", now)
+	createTestPost(t, db, feedA, "Story", "https://blog.example.com/story", now)
+	createTestPost(t, db, feedB, "Story (repost)", "https://blog.example.com/story/?ref=b", now)
+	createTestPost(t, db, feedB, "Unique", "https://b.example.com/unique", now)
+
+	groups, err := findDuplicatePosts(context.Background(), db)
+	if err != nil {
+		t.Fatalf("findDuplicatePosts returned error: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 duplicate groups, got %+v", groups)
+	}
+
+	if groups[0].Reason != "url" || groups[0].Key != "https://blog.example.com/story" || len(groups[0].Posts) != 2 {
+		t.Fatalf("unexpected URL group: %+v", groups[0])
+	}
+	if groups[1].Reason != "title" || groups[1].Key != "go 1.24 released" || len(groups[1].Posts) != 2 {
+		t.Fatalf("unexpected title group: %+v", groups[1])
+	}
+	if groups[1].Posts[0].FeedName == groups[1].Posts[1].FeedName {
+		t.Fatalf("expected the title group to span both feeds, got %+v", groups[1].Posts)
+	}
+}
+
+func TestSearchPostsForUserSince_OnlyPostsAfterCursor(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+
+	// Stored an hour apart, in this order
+	base := time.Now().UTC().Add(-24 * time.Hour)
+	var posts []database.Post
+	for i, title := range []string{"golang one", "golang two", "rust three", "golang four"} {
+		post, err := db.CreatePost(ctx, database.CreatePostParams{
+			ID:        uuid.New(),
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			UpdatedAt: base.Add(time.Duration(i) * time.Hour),
+			Title:     title,
+			Url:       "https://a.example.com/" + uuid.NewString(),
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			t.Fatalf("couldn't create post: %v", err)
+		}
+		posts = append(posts, post)
+	}
+
+	cursor := posts[0]
+	results, err := db.SearchPostsForUserSince(ctx, database.SearchPostsForUserSinceParams{
+		UserID:    alice.ID,
+		Column2:   sql.NullString{String: "golang", Valid: true},
+		CreatedAt: cursor.CreatedAt,
+		ID:        cursor.ID,
+		Limit:     10,
+	})
+	if err != nil {
+		t.Fatalf("SearchPostsForUserSince returned error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Title != "golang two" || results[1].Title != "golang four" {
+		t.Fatalf("expected the two later golang posts oldest first, got %+v", results)
+	}
+
+	// Polling again from the last result finds nothing new
+	last := results[len(results)-1]
+	results, err = db.SearchPostsForUserSince(ctx, database.SearchPostsForUserSinceParams{
+		UserID:    alice.ID,
+		Column2:   sql.NullString{String: "golang", Valid: true},
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+		Limit:     10,
+	})
+	if err != nil {
+		t.Fatalf("SearchPostsForUserSince returned error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results after the last cursor, got %+v", results)
+	}
+}
+
+func TestSavePostsToDatabase_StoresLinkUnchanged(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	links := []string{
+		"https://a.example.com/posts/1/?utm_source=rss",
+		"https://a.example.com/posts/1/#comments",
+	}
+	items := make([]rss.RSSItem, 0, len(links))
+	for i, link := range links {
+		items = append(items, rss.RSSItem{Title: fmt.Sprintf("post %d", i), Link: link})
+	}
+
+	if _, err := rss.SavePostsToDatabase(ctx, db, &rss.RSSFeed{Channel: rss.RSSChannel{Items: items}}, feed.ID); err != nil {
+		t.Fatalf("SavePostsToDatabase returned error: %v", err)
+	}
+	// Links differing only in what normalization strips are separate posts
+	for _, link := range links {
+		if _, err := db.GetPostByURL(ctx, link); err != nil {
+			t.Errorf("expected a post stored under %q, got %v", link, err)
+		}
+	}
+}
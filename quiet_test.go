@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"gator/internal/config"
+	"gator/internal/database"
+)
+
+// newTestState builds state writing to buffers instead of the standard streams.
+// Under quiet, informational output stays discarded and only dataOut is captured.
+func newTestState(db *database.Queries, quiet bool) (*state, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	s := newState(db, &config.Config{}, quiet)
+	if !quiet {
+		s.out = &out
+	}
+	s.dataOut = &out
+	s.errOut = &errOut
+	return s, &out, &errOut
+}
+
+func TestNewState_QuietDiscardsInformationalOutput(t *testing.T) {
+	s := newState(nil, &config.Config{}, true)
+	if s.out != io.Discard {
+		t.Fatalf("expected quiet state to discard informational output")
+	}
+	if s.dataOut != os.Stdout {
+		t.Fatalf("expected quiet state to keep machine-readable output on stdout")
+	}
+	if s.errOut == nil {
+		t.Fatalf("expected errOut to be set")
+	}
+}
+
+func TestHandlerFeeds_QuietErrorStillReported(t *testing.T) {
+	db := database.New(failingDB{err: errors.New("connection refused")})
+	s, out, errOut := newTestState(db, true)
+
+	cmds := &commands{handlers: map[string]func(*state, command) error{}}
+	cmds.register("feeds", handlerFeeds)
+
+	code := runCommand(cmds, s, command{name: "feeds"})
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no output under --quiet, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "couldn't retrieve feeds: connection refused") {
+		t.Fatalf("expected error on errOut, got %q", errOut.String())
+	}
+}
+
+func TestHandlerFeeds_QuietPrintsNothing(t *testing.T) {
+	db := openTestQueries(t)
+	user := createTestUser(t, db, "alice")
+	createTestFeed(t, db, user, "A", "https://a.example.com/feed.xml")
+
+	s, out, errOut := newTestState(db, true)
+	if err := handlerFeeds(s, command{name: "feeds"}); err != nil {
+		t.Fatalf("handlerFeeds returned error: %v", err)
+	}
+	if out.Len() != 0 || errOut.Len() != 0 {
+		t.Fatalf("expected no output under --quiet, got out=%q errOut=%q", out.String(), errOut.String())
+	}
+
+	// --json output is machine-readable and survives --quiet
+	if err := handlerFeeds(s, command{name: "feeds", args: []string{"--json"}}); err != nil {
+		t.Fatalf("handlerFeeds --json returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"schema_version"`) {
+		t.Fatalf("expected JSON output under --quiet, got %q", out.String())
+	}
+}
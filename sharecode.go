@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxShareCodeFeeds caps how many feed URLs a share code may carry
+const maxShareCodeFeeds = 500
+
+// maxShareCodeBytes caps the decompressed size of a share code payload
+const maxShareCodeBytes = 1 << 20
+
+// encodeShareCode packs feed URLs into a compact, copy-pasteable string
+// (newline-separated URLs, gzipped, then base64url-encoded)
+func encodeShareCode(urls []string) (string, error) {
+	if len(urls) > maxShareCodeFeeds {
+		return "", fmt.Errorf("too many feeds to share: %d (max %d)", len(urls), maxShareCodeFeeds)
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := zw.Write([]byte(strings.Join(urls, "\n"))); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeShareCode unpacks and validates the feed URLs in a share code
+func decodeShareCode(code string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, fmt.Errorf("invalid share code: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid share code: %w", err)
+	}
+	defer zr.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(zr, maxShareCodeBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid share code: %w", err)
+	}
+	if len(payload) > maxShareCodeBytes {
+		return nil, fmt.Errorf("share code is too large")
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(payload), "\n") {
+		feedURL := strings.TrimSpace(line)
+		if feedURL == "" || seen[feedURL] {
+			continue
+		}
+		if err := validateFeedURL(feedURL); err != nil {
+			return nil, fmt.Errorf("invalid share code: %w", err)
+		}
+		seen[feedURL] = true
+		urls = append(urls, feedURL)
+	}
+
+	if len(urls) > maxShareCodeFeeds {
+		return nil, fmt.Errorf("share code contains too many feeds: %d (max %d)", len(urls), maxShareCodeFeeds)
+	}
+	return urls, nil
+}
+
+// validateFeedURL checks that a feed URL is an absolute http(s) URL
+func validateFeedURL(feedURL string) error {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return fmt.Errorf("invalid feed URL %q: %w", feedURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid feed URL %q: must be an absolute http(s) URL", feedURL)
+	}
+	return nil
+}
+
+// exportFollowsCode builds a share code from the feeds a user follows
+func exportFollowsCode(ctx context.Context, db *database.Queries, userID uuid.UUID) (string, int, error) {
+	follows, err := db.GetFeedFollowsForUser(ctx, userID)
+	if err != nil {
+		return "", 0, fmt.Errorf("couldn't retrieve feed follows: %w", err)
+	}
+
+	urls := make([]string, len(follows))
+	for i, follow := range follows {
+		urls[i] = follow.FeedUrl
+	}
+
+	code, err := encodeShareCode(urls)
+	if err != nil {
+		return "", 0, err
+	}
+	return code, len(urls), nil
+}
+
+// shareImportResult summarizes a share code import
+type shareImportResult struct {
+	Followed         int
+	AlreadyFollowing int
+	NotFound         []string
+}
+
+// importFollowsCode follows every known feed listed in a share code
+func importFollowsCode(ctx context.Context, db *database.Queries, userID uuid.UUID, code string) (shareImportResult, error) {
+	var result shareImportResult
+
+	urls, err := decodeShareCode(code)
+	if err != nil {
+		return result, err
+	}
+
+	for _, feedURL := range urls {
+		feed, err := rss.LookupFeed(ctx, db, feedURL)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				result.NotFound = append(result.NotFound, feedURL)
+				continue
+			}
+			return result, fmt.Errorf("database error while looking up feed with URL %s: %w", feedURL, err)
+		}
+
+		_, err = db.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    userID,
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				result.AlreadyFollowing++
+				continue
+			}
+			return result, fmt.Errorf("couldn't follow feed %s: %w", feedURL, err)
+		}
+		result.Followed++
+	}
+
+	return result, nil
+}
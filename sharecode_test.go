@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestShareCode_RoundTrip(t *testing.T) {
+	urls := []string{
+		"https://a.example.com/feed.xml",
+		"https://b.example.com/rss",
+	}
+
+	code, err := encodeShareCode(urls)
+	if err != nil {
+		t.Fatalf("encodeShareCode returned error: %v", err)
+	}
+
+	got, err := decodeShareCode(code)
+	if err != nil {
+		t.Fatalf("decodeShareCode returned error: %v", err)
+	}
+	if len(got) != len(urls) || got[0] != urls[0] || got[1] != urls[1] {
+		t.Fatalf("decodeShareCode = %v; want %v", got, urls)
+	}
+}
+
+func TestShareCode_Invalid(t *testing.T) {
+	if _, err := decodeShareCode("not-a-code!"); err == nil {
+		t.Fatalf("expected error for garbage share code")
+	}
+
+	code, err := encodeShareCode([]string{"ftp://example.com/feed"})
+	if err != nil {
+		t.Fatalf("encodeShareCode returned error: %v", err)
+	}
+	if _, err := decodeShareCode(code); err == nil {
+		t.Fatalf("expected error for non-http feed URL")
+	}
+
+	tooMany := make([]string, maxShareCodeFeeds+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("https://example.com/%d", i)
+	}
+	if _, err := encodeShareCode(tooMany); err == nil {
+		t.Fatalf("expected error when exceeding %d feeds", maxShareCodeFeeds)
+	}
+}
+
+func TestShareCode_ExportImportBetweenUsers(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, alice, "B", "https://b.example.com/rss")
+	createTestFeed(t, db, alice, "C", "https://c.example.com/rss") // not followed
+	followTestFeed(t, db, alice, feedA)
+	followTestFeed(t, db, alice, feedB)
+
+	code, count, err := exportFollowsCode(ctx, db, alice.ID)
+	if err != nil {
+		t.Fatalf("exportFollowsCode returned error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 exported feeds, got %d", count)
+	}
+
+	result, err := importFollowsCode(ctx, db, bob.ID, code)
+	if err != nil {
+		t.Fatalf("importFollowsCode returned error: %v", err)
+	}
+	if result.Followed != 2 {
+		t.Fatalf("expected 2 new follows, got %+v", result)
+	}
+
+	follows, err := db.GetFeedFollowsForUser(ctx, bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser returned error: %v", err)
+	}
+	var got []string
+	for _, f := range follows {
+		got = append(got, f.FeedUrl)
+	}
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != feedA.Url || got[1] != feedB.Url {
+		t.Fatalf("bob follows %v; want %v and %v", got, feedA.Url, feedB.Url)
+	}
+}
+
+func TestHandlerFollowingExport_QuietPrintsOnlyTheCode(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	followTestFeed(t, db, alice, createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml"))
+
+	s, out, _ := newTestState(db, true)
+	if err := handlerFollowingExport(s, alice); err != nil {
+		t.Fatalf("handlerFollowingExport returned error: %v", err)
+	}
+	code := strings.TrimSpace(out.String())
+	if strings.Contains(code, "\n") || strings.Contains(code, "Import it with") {
+		t.Fatalf("expected only the share code with --quiet, got %q", out.String())
+	}
+	if _, err := decodeShareCode(code); err != nil {
+		t.Fatalf("expected a valid share code, got %q: %v", code, err)
+	}
+}
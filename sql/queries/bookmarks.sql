@@ -8,6 +8,7 @@ DELETE FROM bookmarks
 WHERE user_id = $1 AND post_id = $2;
 
 -- name: GetBookmarksForUser :many
+-- Optionally scoped to a single feed when feed_id is non-null.
 SELECT 
     b.id as bookmark_id,
     b.created_at as bookmarked_at,
@@ -19,13 +20,23 @@ SELECT
     p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM bookmarks b
 JOIN posts p ON b.post_id = p.id
 JOIN feeds f ON p.feed_id = f.id
-WHERE b.user_id = $1
+WHERE b.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(feed_id)::uuid IS NULL OR p.feed_id = sqlc.narg(feed_id))
 ORDER BY b.created_at DESC
-LIMIT $2 OFFSET $3;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetBookmarkedPostIDs :many
+SELECT post_id FROM bookmarks WHERE user_id = $1;
 
 -- name: GetPostByID :one
 SELECT * FROM posts WHERE id = $1;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts WHERE url = $1;
@@ -0,0 +1,8 @@
+-- name: GetLastBrowsedAt :one
+SELECT last_browsed_at FROM browse_marks WHERE user_id = $1;
+
+-- name: SetLastBrowsedAt :exec
+INSERT INTO browse_marks (user_id, last_browsed_at)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET last_browsed_at = EXCLUDED.last_browsed_at;
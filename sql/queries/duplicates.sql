@@ -0,0 +1,31 @@
+-- name: GetDuplicatePostsByURL :many
+-- Posts whose URLs match once case, query string, fragment, and trailing
+-- slashes are ignored, grouped by that key. Only groups of two or more are returned.
+WITH keyed AS (
+    SELECT p.id, p.created_at, p.title, p.url, p.feed_id,
+        lower(regexp_replace(p.url, '([?#].*$)|(/+$)', '', 'g'))::text AS dup_key
+    FROM posts p
+)
+SELECT k.dup_key, k.id, k.title, k.url, f.name as feed_name
+FROM keyed k
+JOIN feeds f ON k.feed_id = f.id
+WHERE k.dup_key IN (
+    SELECT dup_key FROM keyed GROUP BY dup_key HAVING COUNT(*) > 1
+)
+ORDER BY k.dup_key, k.created_at;
+
+-- name: GetDuplicatePostsByTitle :many
+-- Posts whose titles match once case and whitespace are ignored, grouped by
+-- that key. Only groups of two or more are returned.
+WITH keyed AS (
+    SELECT p.id, p.created_at, p.title, p.url, p.feed_id,
+        lower(regexp_replace(trim(p.title), '\s+', ' ', 'g'))::text AS dup_key
+    FROM posts p
+)
+SELECT k.dup_key, k.id, k.title, k.url, f.name as feed_name
+FROM keyed k
+JOIN feeds f ON k.feed_id = f.id
+WHERE k.dup_key <> '' AND k.dup_key IN (
+    SELECT dup_key FROM keyed GROUP BY dup_key HAVING COUNT(*) > 1
+)
+ORDER BY k.dup_key, k.created_at;
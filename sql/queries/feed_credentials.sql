@@ -0,0 +1,13 @@
+-- name: SetFeedCredentials :exec
+-- Stores the Basic Auth credentials for a feed, replacing any earlier ones.
+-- password_env names an environment variable holding the password instead.
+INSERT INTO feed_credentials (feed_id, username, password, password_env, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (feed_id) DO UPDATE
+SET username = EXCLUDED.username,
+    password = EXCLUDED.password,
+    password_env = EXCLUDED.password_env,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: GetFeedCredentials :one
+SELECT * FROM feed_credentials WHERE feed_id = $1;
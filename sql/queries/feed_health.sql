@@ -0,0 +1,23 @@
+-- name: FeedHasPosts :one
+SELECT EXISTS (SELECT 1 FROM posts WHERE feed_id = $1);
+
+-- name: FlagFeedPossiblyMoved :exec
+-- Records that a feed which used to have posts now returns none.
+INSERT INTO feed_health (feed_id, flagged_at, suggested_url)
+VALUES ($1, $2, $3)
+ON CONFLICT (feed_id) DO UPDATE
+SET flagged_at = EXCLUDED.flagged_at, suggested_url = EXCLUDED.suggested_url;
+
+-- name: ClearFeedHealthFlag :exec
+DELETE FROM feed_health WHERE feed_id = $1;
+
+-- name: GetFlaggedFeeds :many
+SELECT
+    f.id,
+    f.name,
+    f.url,
+    h.flagged_at,
+    h.suggested_url
+FROM feed_health h
+JOIN feeds f ON h.feed_id = f.id
+ORDER BY h.flagged_at DESC;
@@ -10,6 +10,20 @@ VALUES (
 )
 RETURNING *;
 
+-- name: CreateOrGetFeed :one
+-- Inserts a feed, or returns the feed already stored under the URL. The no-op
+-- update makes RETURNING yield the existing row on conflict; inserted is true
+-- only when this call created the feed.
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (url) DO UPDATE SET url = EXCLUDED.url
+RETURNING *, (xmax = 0) AS inserted;
+
+-- name: FollowFeedIfNotFollowing :execrows
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, feed_id) DO NOTHING;
+
 -- name: GetFeedsWithUsers :many
 SELECT 
     f.id,
@@ -18,14 +32,121 @@ SELECT
     f.name,
     f.url,
     f.user_id,
-    u.name as user_name
+    u.name as user_name,
+    f.description
 FROM feeds f
 JOIN users u ON f.user_id = u.id
 ORDER BY f.created_at DESC;
 
+-- name: CountFeeds :one
+SELECT COUNT(*) FROM feeds;
+
+-- name: CountFeedsDue :one
+-- Feeds not currently backing off after repeated failures.
+SELECT COUNT(*) FROM feeds
+WHERE next_retry_at IS NULL OR next_retry_at <= NOW();
+
+-- name: GetFeedsBatch :many
+-- Keyset-paginated feeds, least recently fetched first with never-fetched
+-- feeds ahead of all others: the next limit feeds after the one with
+-- after_fetched_at and after_id. A NULL after_fetched_at starts among the
+-- never-fetched feeds. Feeds fetched since started_at, i.e. earlier in the
+-- same run, and feeds backing off after repeated failures are left out.
+SELECT
+    f.id,
+    f.created_at,
+    f.updated_at,
+    f.name,
+    f.url,
+    f.user_id,
+    u.name as user_name,
+    f.description,
+    f.last_fetched_at
+FROM feeds f
+JOIN users u ON f.user_id = u.id
+WHERE (f.next_retry_at IS NULL OR f.next_retry_at <= NOW())
+  AND (f.last_fetched_at IS NULL OR f.last_fetched_at < sqlc.arg(started_at)::timestamptz)
+  AND (
+    (sqlc.narg(after_fetched_at)::timestamp IS NULL AND (f.last_fetched_at IS NOT NULL OR f.id > sqlc.arg(after_id)))
+    OR f.last_fetched_at > sqlc.narg(after_fetched_at)
+    OR (f.last_fetched_at = sqlc.narg(after_fetched_at) AND f.id > sqlc.arg(after_id))
+  )
+ORDER BY f.last_fetched_at ASC NULLS FIRST, f.id
+LIMIT sqlc.arg('limit');
+
+-- name: MarkFeedFetched :exec
+-- Records a successful fetch, clearing any failure backoff.
+UPDATE feeds
+SET last_fetched_at = NOW(), consecutive_failures = 0, next_retry_at = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: RecordFeedFailure :one
+-- Counts a failed fetch and returns the new number of failures in a row.
+UPDATE feeds SET consecutive_failures = consecutive_failures + 1, updated_at = NOW()
+WHERE id = $1
+RETURNING consecutive_failures;
+
+-- name: ScheduleFeedRetry :exec
+UPDATE feeds SET next_retry_at = $2 WHERE id = $1;
+
+-- name: GetFailingFeeds :many
+-- Feeds whose most recent fetches failed, worst first.
+SELECT id, name, url, consecutive_failures, next_retry_at, last_fetched_at
+FROM feeds
+WHERE consecutive_failures > 0
+ORDER BY consecutive_failures DESC, name;
+
+-- name: GetFeedByID :one
+SELECT * FROM feeds WHERE id = $1;
+
 -- name: GetFeedByURL :one
 SELECT * FROM feeds WHERE url = $1;
 
+-- name: DeleteFeedByURL :execrows
+-- Deletes a feed owned by $2. Posts, follows, and everything hanging off
+-- posts (bookmarks, likes, tags) go with it via ON DELETE CASCADE.
+DELETE FROM feeds WHERE url = $1 AND user_id = $2;
+
+-- name: DeleteFeedByID :execrows
+-- Deletes a feed owned by $2, cascading like DeleteFeedByURL.
+DELETE FROM feeds WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateFeedMetadata :one
+-- Stores the name and description a feed advertises about itself.
+UPDATE feeds SET name = $2, description = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateFeedName :one
+UPDATE feeds SET name = $2, updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateFeedOwner :one
+UPDATE feeds SET user_id = $2, updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateFeedURL :exec
+-- Points a feed at the URL it permanently moved to.
+UPDATE feeds SET url = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetUnfollowedFeeds :many
+-- Feeds the user doesn't follow, most-followed first.
+SELECT
+    f.id,
+    f.name,
+    f.url,
+    u.name as owner_name,
+    (SELECT COUNT(*) FROM feed_follows c WHERE c.feed_id = f.id) as follower_count
+FROM feeds f
+JOIN users u ON f.user_id = u.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM feed_follows ff WHERE ff.feed_id = f.id AND ff.user_id = $1
+)
+ORDER BY follower_count DESC, f.name;
+
 -- name: CreateFeedFollow :one
 WITH inserted_feed_follow AS (
     INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
@@ -52,7 +173,9 @@ SELECT
     ff.user_id,
     ff.feed_id,
     u.name as user_name,
-    f.name as feed_name
+    f.name as feed_name,
+    f.url as feed_url,
+    f.description as feed_description
 FROM feed_follows ff
 JOIN users u ON ff.user_id = u.id
 JOIN feeds f ON ff.feed_id = f.id
@@ -64,12 +187,22 @@ DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1 
 AND feed_follows.feed_id = (SELECT id FROM feeds WHERE url = $2);
 
+-- name: DeleteFeedFollowByUserAndFeedID :execrows
+DELETE FROM feed_follows
+WHERE user_id = $1 AND feed_id = $2;
+
 -- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author, enclosure_url, enclosure_type, enclosure_length, date_source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 ON CONFLICT (url) DO NOTHING
 RETURNING *;
 
+-- name: GetPostsForFeed :many
+SELECT * FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC NULLS LAST, created_at DESC
+LIMIT $2;
+
 -- name: GetPostsForUser :many
 SELECT 
     p.id,
@@ -80,6 +213,9 @@ SELECT
     p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
@@ -88,6 +224,165 @@ WHERE ff.user_id = $1
 ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
 LIMIT $2 OFFSET $3;
 
+-- name: GetPostsForUserByFeed :many
+-- Posts from one followed feed, matched by URL or exact name, newest first.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+  AND (f.url = sqlc.arg(feed) OR f.name = sqlc.arg(feed))
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetPostsForUserAfter :many
+-- Keyset-paginated posts from the user's followed feeds, newest first with
+-- undated posts last and ties broken by id: the next limit posts after the
+-- one with after_published_at and after_id, or the newest limit posts when
+-- first is set. A non-empty feed keeps only the followed feed with that URL or
+-- exact name.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(feed)::text = '' OR f.url = sqlc.arg(feed) OR f.name = sqlc.arg(feed))
+  AND (
+    sqlc.arg(first)::boolean
+    OR (sqlc.narg(after_published_at)::timestamp IS NULL AND p.published_at IS NULL AND p.id < sqlc.arg(after_id))
+    OR (sqlc.narg(after_published_at)::timestamp IS NOT NULL AND p.published_at IS NULL)
+    OR p.published_at < sqlc.narg(after_published_at)
+    OR (p.published_at = sqlc.narg(after_published_at) AND p.id < sqlc.arg(after_id))
+  )
+ORDER BY p.published_at DESC NULLS LAST, p.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = $1;
+
+-- name: CountPostsForUserByFeed :one
+-- Count of posts matched by GetPostsForUserByFeed, for pagination.
+SELECT COUNT(*)
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+  AND (f.url = sqlc.arg(feed) OR f.name = sqlc.arg(feed));
+
+-- name: GetPostsForUserOldest :many
+-- Posts from followed feeds, oldest publication date first. Posts without a
+-- publication date come last.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.published_at ASC NULLS LAST, p.created_at ASC
+LIMIT $2 OFFSET $3;
+
+-- name: GetPostsForUserRecentlyAdded :many
+-- Posts from followed feeds in the order gator stored them, most recent first.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+ORDER BY p.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetPostsForUserSince :many
+-- Posts from followed feeds that were stored after $2, newest first.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1 AND p.created_at > $2
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $3;
+
+-- name: GetRecentPosts :many
+-- Newest posts across every feed, regardless of who follows them.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $1;
+
 -- name: SearchPostsForUser :many
 -- Search posts for a user by fuzzy match against title or description.
 -- Params: user_id uuid, q text (search term), limit int, offset int
@@ -100,6 +395,9 @@ SELECT
         p.description,
         p.published_at,
         p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
         f.name as feed_name
 FROM posts p
 JOIN feeds f ON p.feed_id = f.id
@@ -111,3 +409,75 @@ WHERE ff.user_id = $1
     )
 ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
 LIMIT $3 OFFSET $4;
+
+-- name: SearchPostsForUserSince :many
+-- Search matches stored after the cursor post (by created_at, then id),
+-- oldest first, so a poller can resume from the last post it saw.
+SELECT
+        p.id,
+        p.created_at,
+        p.updated_at,
+        p.title,
+        p.url,
+        p.description,
+        p.published_at,
+        p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
+        f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+    AND (
+        p.title ILIKE ('%' || $2 || '%')
+        OR p.description ILIKE ('%' || $2 || '%')
+    )
+    AND (p.created_at, p.id) > ($3::timestamp, $4::uuid)
+ORDER BY p.created_at, p.id
+LIMIT $5;
+
+-- name: CountSearchPostsForUser :one
+-- Count of posts matched by SearchPostsForUser, for pagination.
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = $1
+    AND (
+        p.title ILIKE ('%' || $2 || '%')
+        OR p.description ILIKE ('%' || $2 || '%')
+    );
+
+-- name: SearchPostsFTS :many
+-- Full-text search of a user's posts against title and description, most
+-- relevant first.
+SELECT
+        p.id,
+        p.created_at,
+        p.updated_at,
+        p.title,
+        p.url,
+        p.description,
+        p.published_at,
+        p.feed_id,
+        p.author,
+        p.enclosure_url,
+        p.date_source,
+        f.name as feed_name,
+        ts_rank(p.search_vector, plainto_tsquery('english', sqlc.arg(query))) AS rank
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND p.search_vector @@ plainto_tsquery('english', sqlc.arg(query))
+ORDER BY rank DESC, p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchPostsFTS :one
+-- Count of posts matched by SearchPostsFTS, for pagination.
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND p.search_vector @@ plainto_tsquery('english', sqlc.arg(query));
@@ -0,0 +1,3 @@
+-- name: Ping :exec
+-- Used by the API readiness probe to confirm the database is reachable.
+SELECT 1;
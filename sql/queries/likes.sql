@@ -19,6 +19,9 @@ SELECT
     p.description,
     p.published_at,
     p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
     f.name as feed_name
 FROM likes l
 JOIN posts p ON l.post_id = p.id
@@ -32,3 +35,25 @@ SELECT * FROM likes WHERE user_id = $1 AND post_id = $2;
 
 -- name: GetLikeCountForPost :one
 SELECT COUNT(*) FROM likes WHERE post_id = $1;
+
+-- name: GetLikedPostsForUser :many
+-- Posts the user has liked, ordered like browse (by publication date).
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM likes l
+JOIN posts p ON l.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+WHERE l.user_id = $1
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,31 @@
+-- name: AddPostCategory :exec
+-- Records a category the feed gave a post. Adding it twice is a no-op.
+INSERT INTO post_categories (post_id, category)
+VALUES ($1, $2)
+ON CONFLICT (post_id, category) DO NOTHING;
+
+-- name: GetPostsByCategory :many
+-- Posts from followed feeds in the given category (ignoring case), ordered like browse.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+  AND EXISTS (
+    SELECT 1 FROM post_categories c
+    WHERE c.post_id = p.id AND lower(c.category) = lower(sqlc.arg(category))
+  )
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
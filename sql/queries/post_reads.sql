@@ -0,0 +1,52 @@
+-- name: MarkPostRead :execrows
+-- Marks a post read for the user. Marking it again keeps the first read time.
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetUnreadPostsForUser :many
+-- Posts from the user's followed feeds they haven't read, ordered like browse.
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM posts p
+JOIN feeds f ON p.feed_id = f.id
+JOIN feed_follows ff ON f.id = ff.feed_id
+WHERE ff.user_id = $1
+  AND NOT EXISTS (
+      SELECT 1 FROM post_reads r WHERE r.user_id = $1 AND r.post_id = p.id
+  )
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountUnreadPerFeed :many
+-- Each feed the user follows with how many of its posts they haven't read,
+-- most unread first.
+SELECT
+    f.id AS feed_id,
+    f.name AS feed_name,
+    f.url AS feed_url,
+    COUNT(p.id) AS unread_count
+FROM feed_follows ff
+JOIN feeds f ON ff.feed_id = f.id
+LEFT JOIN posts p ON p.feed_id = f.id
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads r WHERE r.user_id = $1 AND r.post_id = p.id
+    )
+WHERE ff.user_id = $1
+GROUP BY f.id, f.name, f.url
+ORDER BY unread_count DESC, f.name;
@@ -0,0 +1,31 @@
+-- name: TagPost :execrows
+-- Tags a post for the user. Tagging a post twice with the same tag is a no-op.
+INSERT INTO post_user_tags (id, created_at, user_id, post_id, tag)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, post_id, tag) DO NOTHING;
+
+-- name: UntagPost :execrows
+DELETE FROM post_user_tags
+WHERE user_id = $1 AND post_id = $2 AND tag = $3;
+
+-- name: GetTaggedPostsForUser :many
+-- Posts the user has tagged with $2, ordered like browse (by publication date).
+SELECT
+    p.id,
+    p.created_at,
+    p.updated_at,
+    p.title,
+    p.url,
+    p.description,
+    p.published_at,
+    p.feed_id,
+    p.author,
+    p.enclosure_url,
+    p.date_source,
+    f.name as feed_name
+FROM post_user_tags t
+JOIN posts p ON t.post_id = p.id
+JOIN feeds f ON p.feed_id = f.id
+WHERE t.user_id = $1 AND t.tag = $2
+ORDER BY p.published_at DESC NULLS LAST, p.created_at DESC
+LIMIT $3 OFFSET $4;
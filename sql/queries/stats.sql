@@ -0,0 +1,53 @@
+-- name: GetFeedStats :one
+-- Totals for a single feed: post count, average description length in
+-- characters, and when its newest post was stored.
+SELECT
+    COUNT(*) AS total_posts,
+    COALESCE(AVG(length(COALESCE(p.description, ''))), 0)::float8 AS avg_length,
+    MAX(p.created_at)::timestamp AS last_post_at
+FROM posts p
+WHERE p.feed_id = $1;
+
+-- name: GetFeedWeeklyPostCounts :many
+-- Posts per week (weeks start Monday) for a feed since the given time, dated by
+-- publication time, falling back to when the post was stored.
+SELECT
+    date_trunc('week', COALESCE(p.published_at, p.created_at))::timestamp AS week,
+    COUNT(*) AS post_count
+FROM posts p
+WHERE p.feed_id = sqlc.arg(feed_id)
+    AND COALESCE(p.published_at, p.created_at) >= sqlc.arg(since)
+GROUP BY week
+ORDER BY week;
+
+-- name: CountFeedFollows :one
+SELECT COUNT(*)
+FROM feed_follows
+WHERE user_id = $1;
+
+-- name: CountRecentPostsForUser :one
+-- Posts from followed feeds that were stored at or after the given time.
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND p.created_at >= sqlc.arg(since);
+
+-- name: CountBookmarksForUser :one
+SELECT COUNT(*)
+FROM bookmarks
+WHERE user_id = $1;
+
+-- name: TopFeedsForUser :many
+-- The user's followed feeds with the most posts, largest first.
+SELECT
+    f.name,
+    f.url,
+    COUNT(p.id) AS post_count
+FROM feed_follows ff
+JOIN feeds f ON ff.feed_id = f.id
+LEFT JOIN posts p ON p.feed_id = f.id
+WHERE ff.user_id = $1
+GROUP BY f.id, f.name, f.url
+ORDER BY post_count DESC, f.name
+LIMIT $2;
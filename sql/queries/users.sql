@@ -1,5 +1,5 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name, api_key)
+INSERT INTO users (id, created_at, updated_at, name, api_key_hash)
 VALUES (
     $1,
     $2,
@@ -9,14 +9,28 @@ VALUES (
 )
 RETURNING *;
 
+-- name: CreateUserIfNotExists :one
+-- Inserts a user unless the name is already taken. Returns no row on conflict,
+-- so concurrent registrations of the same name resolve to exactly one winner.
+INSERT INTO users (id, created_at, updated_at, name, api_key_hash)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
 -- name: GetUser :one
 SELECT * FROM users WHERE name = $1;
 
--- name: GetUserByAPIKey :one
-SELECT * FROM users WHERE api_key = $1;
+-- name: GetUserByAPIKeyHash :one
+SELECT * FROM users WHERE api_key_hash = $1;
 
 -- name: UpdateUserAPIKey :exec
-UPDATE users SET api_key = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+UPDATE users SET api_key_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
 
 -- name: GetUsers :many
 SELECT * FROM users;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE feed_health (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    flagged_at TIMESTAMP NOT NULL,
+    suggested_url TEXT
+);
+
+-- +goose Down
+DROP TABLE feed_health;
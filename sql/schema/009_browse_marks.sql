@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE browse_marks (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_browsed_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE browse_marks;
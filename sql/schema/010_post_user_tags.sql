@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE post_user_tags (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    UNIQUE(user_id, post_id, tag)
+);
+
+CREATE INDEX post_user_tags_user_tag_idx ON post_user_tags (user_id, tag);
+
+-- +goose Down
+DROP TABLE post_user_tags;
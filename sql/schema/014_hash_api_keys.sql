@@ -0,0 +1,12 @@
+-- +goose Up
+-- API keys are stored as hex-encoded SHA-256 hashes. Existing plaintext keys
+-- are hashed in place, so clients keep working with the keys they hold.
+ALTER TABLE users RENAME COLUMN api_key TO api_key_hash;
+UPDATE users
+SET api_key_hash = encode(sha256(convert_to(api_key_hash, 'UTF8')), 'hex')
+WHERE api_key_hash IS NOT NULL;
+
+-- +goose Down
+-- Hashes can't be reversed, so keys are cleared; reissue them with reset-apikey.
+UPDATE users SET api_key_hash = NULL;
+ALTER TABLE users RENAME COLUMN api_key_hash TO api_key;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE post_categories (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    PRIMARY KEY (post_id, category)
+);
+
+CREATE INDEX post_categories_category_idx ON post_categories (lower(category));
+
+-- +goose Down
+DROP TABLE post_categories;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN enclosure_url TEXT;
+ALTER TABLE posts ADD COLUMN enclosure_type TEXT;
+ALTER TABLE posts ADD COLUMN enclosure_length BIGINT;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN enclosure_length;
+ALTER TABLE posts DROP COLUMN enclosure_type;
+ALTER TABLE posts DROP COLUMN enclosure_url;
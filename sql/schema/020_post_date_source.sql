@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN date_source TEXT;
+UPDATE posts SET date_source = 'published' WHERE published_at IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN date_source;
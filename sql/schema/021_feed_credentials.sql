@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE feed_credentials (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    username TEXT NOT NULL,
+    password TEXT NOT NULL DEFAULT '',
+    password_env TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE feed_credentials;
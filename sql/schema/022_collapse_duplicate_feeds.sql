@@ -0,0 +1,113 @@
+-- +goose Up
+-- Feeds added before URLs were normalized may be stored more than once under
+-- spellings that differ only by scheme, host case, default port, trailing
+-- slashes, fragment, or tracking parameters. Each set of duplicates is merged
+-- into its oldest feed, which takes over the others' posts and followers and
+-- is then stored under its normalized URL.
+--
+-- Every feed's URL is rewritten the way rss.NormalizeURL would write it: the
+-- scheme and host lowercased, the default port for the scheme dropped, the
+-- path's trailing slashes and the fragment dropped, and the query's tracking
+-- parameters dropped with the rest sorted by name, stably, and otherwise left
+-- as written. URLs that aren't absolute http(s) URLs are only trimmed.
+CREATE TEMP TABLE feed_url_keys AS
+SELECT
+    id,
+    created_at,
+    normalized,
+    plain,
+    CASE WHEN plain THEN normalized ELSE regexp_replace(normalized, '^https?://', '') END AS url_key
+FROM (
+    SELECT
+        id,
+        created_at,
+        plain,
+        CASE WHEN plain THEN trimmed ELSE
+            scheme || '://'
+            || userinfo
+            || regexp_replace(host, CASE scheme WHEN 'http' THEN ':80$' ELSE ':443$' END, '')
+            || rtrim(path, '/')
+            || coalesce('?' || (
+                SELECT string_agg(param, '&' ORDER BY split_part(param, '=', 1) COLLATE "C", n)
+                FROM unnest(string_to_array(query, '&')) WITH ORDINALITY AS q(param, n)
+                WHERE param <> ''
+                  AND lower(split_part(param, '=', 1)) !~ '^(utm_.*|fbclid|gclid|mc_cid|mc_eid)$'
+            ), '')
+        END AS normalized
+    FROM (
+        SELECT
+            id,
+            created_at,
+            trimmed,
+            parts IS NULL OR regexp_replace(parts[2], '^.*@', '') = '' AS plain,
+            lower(parts[1]) AS scheme,
+            coalesce(substring(parts[2] FROM '^(.*@)'), '') AS userinfo,
+            lower(regexp_replace(parts[2], '^.*@', '')) AS host,
+            parts[3] AS path,
+            parts[4] AS query
+        FROM (
+            SELECT
+                id,
+                created_at,
+                trimmed,
+                regexp_match(trimmed, '^(https?)://([^/?#]*)([^?#]*)(?:\?([^#]*))?', 'i') AS parts
+            FROM (
+                SELECT id, created_at, btrim(url, E' \t\n\r\f\x0B') AS trimmed
+                FROM feeds
+            ) trimmed_feeds
+        ) matched
+    ) split
+) normalized_feeds;
+
+CREATE TEMP TABLE duplicate_feeds AS
+SELECT id AS duplicate_id, keeper_id
+FROM (
+    SELECT id, first_value(id) OVER (PARTITION BY plain, url_key ORDER BY created_at, id) AS keeper_id
+    FROM feed_url_keys
+) ranked
+WHERE id <> keeper_id;
+
+-- A user following several spellings keeps a single follow of the keeper
+DELETE FROM feed_follows ff
+USING duplicate_feeds d
+WHERE ff.feed_id = d.duplicate_id
+  AND EXISTS (
+    SELECT 1 FROM feed_follows other
+    LEFT JOIN duplicate_feeds od ON od.duplicate_id = other.feed_id
+    WHERE other.user_id = ff.user_id
+      AND coalesce(od.keeper_id, other.feed_id) = d.keeper_id
+      AND (other.feed_id = d.keeper_id OR other.id < ff.id)
+  );
+
+UPDATE feed_follows ff
+SET feed_id = d.keeper_id
+FROM duplicate_feeds d
+WHERE ff.feed_id = d.duplicate_id;
+
+UPDATE posts p
+SET feed_id = d.keeper_id
+FROM duplicate_feeds d
+WHERE p.feed_id = d.duplicate_id;
+
+INSERT INTO feed_credentials (feed_id, username, password, password_env, updated_at)
+SELECT DISTINCT ON (d.keeper_id) d.keeper_id, c.username, c.password, c.password_env, c.updated_at
+FROM feed_credentials c
+JOIN duplicate_feeds d ON c.feed_id = d.duplicate_id
+ORDER BY d.keeper_id, c.updated_at DESC
+ON CONFLICT (feed_id) DO NOTHING;
+
+DELETE FROM feeds WHERE id IN (SELECT duplicate_id FROM duplicate_feeds);
+
+-- Stored under its normalized URL, a feed is found however it's spelled
+UPDATE feeds f
+SET url = k.normalized
+FROM feed_url_keys k
+WHERE f.id = k.id
+  AND f.url <> k.normalized;
+
+DROP TABLE duplicate_feeds;
+DROP TABLE feed_url_keys;
+
+-- +goose Down
+-- Merged feeds can't be split apart again, so there is nothing to undo.
+SELECT 1;
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gator/internal/database"
+	"gator/internal/rss"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statsWeeks is how many weeks of history `stats --feed` shows
+const statsWeeks = 8
+
+// statsTopFeeds is how many feeds the `stats` overview ranks by post count
+const statsTopFeeds = 5
+
+// statsRecentWindow is how far back the `stats` overview counts new posts
+const statsRecentWindow = 24 * time.Hour
+
+// userStats is the `stats` overview of everything available to one user
+type userStats struct {
+	Follows     int64
+	Posts       int64
+	RecentPosts int64 // posts stored within statsRecentWindow
+	Bookmarks   int64
+	TopFeeds    []database.TopFeedsForUserRow
+}
+
+// feedStats summarises a single feed's posting activity
+type feedStats struct {
+	Feed       database.Feed
+	TotalPosts int64
+	AvgLength  float64
+	LastPostAt time.Time // zero if the feed has no posts
+	// Weekly holds post counts for the last statsWeeks weeks, oldest first;
+	// the last entry is the current (partial) week
+	Weekly []int64
+	// WeekStarts holds the Monday each Weekly entry starts on
+	WeekStarts []time.Time
+}
+
+// startOfWeek returns midnight UTC on the Monday of t's week, matching
+// Postgres's date_trunc('week', ...)
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// loadFeedStats gathers totals and the weekly post histogram for feed as of now
+func loadFeedStats(ctx context.Context, db *database.Queries, feed database.Feed, now time.Time) (feedStats, error) {
+	totals, err := db.GetFeedStats(ctx, feed.ID)
+	if err != nil {
+		return feedStats{}, fmt.Errorf("couldn't retrieve feed stats: %w", err)
+	}
+
+	first := startOfWeek(now).AddDate(0, 0, -7*(statsWeeks-1))
+	rows, err := db.GetFeedWeeklyPostCounts(ctx, database.GetFeedWeeklyPostCountsParams{
+		FeedID: feed.ID,
+		Since:  first,
+	})
+	if err != nil {
+		return feedStats{}, fmt.Errorf("couldn't retrieve weekly post counts: %w", err)
+	}
+
+	stats := feedStats{
+		Feed:       feed,
+		TotalPosts: totals.TotalPosts,
+		AvgLength:  totals.AvgLength,
+		Weekly:     make([]int64, statsWeeks),
+		WeekStarts: make([]time.Time, statsWeeks),
+	}
+	if totals.LastPostAt.Valid {
+		stats.LastPostAt = totals.LastPostAt.Time
+	}
+	for i := range stats.WeekStarts {
+		stats.WeekStarts[i] = first.AddDate(0, 0, 7*i)
+	}
+	// Weeks without posts have no row, so place each count by its week
+	for _, row := range rows {
+		i := int(row.Week.Sub(first).Hours() / (24 * 7))
+		if i >= 0 && i < statsWeeks {
+			stats.Weekly[i] = row.PostCount
+		}
+	}
+	return stats, nil
+}
+
+// loadUserStats gathers the `stats` overview for userID as of now
+func loadUserStats(ctx context.Context, db *database.Queries, userID uuid.UUID, now time.Time) (userStats, error) {
+	var stats userStats
+	var err error
+	if stats.Follows, err = db.CountFeedFollows(ctx, userID); err != nil {
+		return userStats{}, fmt.Errorf("couldn't count followed feeds: %w", err)
+	}
+	if stats.Posts, err = db.CountPostsForUser(ctx, userID); err != nil {
+		return userStats{}, fmt.Errorf("couldn't count posts: %w", err)
+	}
+	stats.RecentPosts, err = db.CountRecentPostsForUser(ctx, database.CountRecentPostsForUserParams{
+		UserID: userID,
+		Since:  now.Add(-statsRecentWindow),
+	})
+	if err != nil {
+		return userStats{}, fmt.Errorf("couldn't count recent posts: %w", err)
+	}
+	if stats.Bookmarks, err = db.CountBookmarksForUser(ctx, userID); err != nil {
+		return userStats{}, fmt.Errorf("couldn't count bookmarks: %w", err)
+	}
+	stats.TopFeeds, err = db.TopFeedsForUser(ctx, database.TopFeedsForUserParams{
+		UserID: userID,
+		Limit:  statsTopFeeds,
+	})
+	if err != nil {
+		return userStats{}, fmt.Errorf("couldn't retrieve top feeds: %w", err)
+	}
+	return stats, nil
+}
+
+// sparkline renders counts as a row of block characters scaled to the largest count
+func sparkline(counts []int64) string {
+	const bars = "▁▂▃▄▅▆▇█"
+	levels := []rune(bars)
+
+	var highest int64
+	for _, c := range counts {
+		highest = max(highest, c)
+	}
+
+	var b strings.Builder
+	for _, c := range counts {
+		if highest == 0 {
+			b.WriteRune(levels[0])
+			continue
+		}
+		b.WriteRune(levels[int(c*int64(len(levels)-1)/highest)])
+	}
+	return b.String()
+}
+
+// handlerStats shows an overview for the current user, or posting analytics
+// for one feed with `stats --feed <url>`
+func handlerStats(s *state, cmd command) error {
+	feedURL, _, err := flagValue(cmd.args, "--feed")
+	if err != nil {
+		return err
+	}
+	if feedURL == "" {
+		return middlewareLoggedIn(handlerStatsOverview)(s, cmd)
+	}
+
+	ctx := context.Background()
+	feed, err := findFeedByURL(ctx, s.db, feedURL, rss.NormalizeURL(feedURL))
+	if err != nil {
+		return err
+	}
+	if feed == nil {
+		return fmt.Errorf("feed not found: %s", feedURL)
+	}
+
+	stats, err := loadFeedStats(ctx, s.db, *feed, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "%s (%s)\n", feed.Name, feed.Url)
+	fmt.Fprintf(s.out, "Total posts: %d\n", stats.TotalPosts)
+	fmt.Fprintf(s.out, "Average post length: %.0f characters\n", stats.AvgLength)
+	if stats.LastPostAt.IsZero() {
+		fmt.Fprintln(s.out, "Last updated: never")
+	} else {
+		fmt.Fprintf(s.out, "Last updated: %s\n", stats.LastPostAt.Format("2006-01-02 15:04:05"))
+	}
+
+	fmt.Fprintf(s.out, "\nPosts per week (last %d weeks): %s\n", statsWeeks, sparkline(stats.Weekly))
+	for i, count := range stats.Weekly {
+		fmt.Fprintf(s.out, "  %s  %d\n", stats.WeekStarts[i].Format("2006-01-02"), count)
+	}
+	return nil
+}
+
+// handlerStatsOverview prints follow, post, and bookmark counts for the
+// current user and their busiest feeds
+func handlerStatsOverview(s *state, cmd command, user database.User) error {
+	stats, err := loadUserStats(context.Background(), s.db, user.ID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Feeds followed: %d\n", stats.Follows)
+	fmt.Fprintf(s.out, "Posts available: %d\n", stats.Posts)
+	fmt.Fprintf(s.out, "Posts added in the last 24h: %d\n", stats.RecentPosts)
+	fmt.Fprintf(s.out, "Bookmarks: %d\n", stats.Bookmarks)
+
+	if len(stats.TopFeeds) == 0 {
+		return nil
+	}
+	fmt.Fprintf(s.out, "\nTop feeds by post count:\n")
+	for i, feed := range stats.TopFeeds {
+		fmt.Fprintf(s.out, "  %d. %s (%s) - %d posts\n", i+1, feed.Name, feed.Url, feed.PostCount)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gator/internal/database"
+)
+
+func TestLoadFeedStats_WeeklyBuckets(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+	other := createTestFeed(t, db, alice, "Other", "https://other.example.com/feed.xml")
+
+	// A Wednesday, so the current week started two days earlier
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	thisWeek := startOfWeek(now)
+	createTestPost(t, db, feed, "this week", "https://blog.example.com/1", thisWeek.Add(time.Hour))
+	createTestPost(t, db, feed, "this week too", "https://blog.example.com/2", now)
+	createTestPost(t, db, feed, "last week", "https://blog.example.com/3", thisWeek.AddDate(0, 0, -3))
+	createTestPost(t, db, feed, "oldest shown week", "https://blog.example.com/4", thisWeek.AddDate(0, 0, -7*7))
+	createTestPost(t, db, feed, "too old", "https://blog.example.com/5", thisWeek.AddDate(0, 0, -7*8))
+	createTestPost(t, db, other, "other feed", "https://other.example.com/1", now)
+
+	stats, err := loadFeedStats(context.Background(), db, feed, now)
+	if err != nil {
+		t.Fatalf("loadFeedStats returned error: %v", err)
+	}
+
+	want := []int64{1, 0, 0, 0, 0, 0, 1, 2}
+	for i, count := range want {
+		if stats.Weekly[i] != count {
+			t.Fatalf("weekly counts = %v; want %v", stats.Weekly, want)
+		}
+	}
+	if stats.TotalPosts != 5 {
+		t.Fatalf("TotalPosts = %d; want 5", stats.TotalPosts)
+	}
+	if !stats.WeekStarts[statsWeeks-1].Equal(thisWeek) {
+		t.Fatalf("last week starts %v; want %v", stats.WeekStarts[statsWeeks-1], thisWeek)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	cases := map[time.Time]time.Time{
+		time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC): time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC):  time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC): time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+	}
+	for in, want := range cases {
+		if got := startOfWeek(in); !got.Equal(want) {
+			t.Fatalf("startOfWeek(%v) = %v; want %v", in, got, want)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int64{0, 1, 2, 4}); got != "▁▂▄█" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]int64{0, 0}); got != "▁▁" {
+		t.Fatalf("sparkline of zeros = %q", got)
+	}
+}
+
+func TestStatsCountQueries(t *testing.T) {
+	db := openTestQueries(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, db, "alice")
+	bob := createTestUser(t, db, "bob")
+	feedA := createTestFeed(t, db, alice, "A", "https://a.example.com/feed.xml")
+	feedB := createTestFeed(t, db, alice, "B", "https://b.example.com/feed.xml")
+	unfollowed := createTestFeed(t, db, bob, "C", "https://c.example.com/feed.xml")
+	followTestFeed(t, db, alice, feedA)
+	followTestFeed(t, db, alice, feedB)
+	followTestFeed(t, db, bob, unfollowed)
+
+	now := time.Now().UTC()
+	post := createTestPost(t, db, feedA, "a1", "https://a.example.com/1", now)
+	createTestPost(t, db, feedA, "a2", "https://a.example.com/2", now)
+	createTestPost(t, db, feedB, "b1", "https://b.example.com/1", now)
+	createTestPost(t, db, unfollowed, "c1", "https://c.example.com/1", now)
+	bookmarkTestPost(t, db, alice, post)
+
+	follows, err := db.CountFeedFollows(ctx, alice.ID)
+	if err != nil || follows != 2 {
+		t.Fatalf("CountFeedFollows = %d, %v; want 2", follows, err)
+	}
+	posts, err := db.CountPostsForUser(ctx, alice.ID)
+	if err != nil || posts != 3 {
+		t.Fatalf("CountPostsForUser = %d, %v; want 3", posts, err)
+	}
+	bookmarks, err := db.CountBookmarksForUser(ctx, alice.ID)
+	if err != nil || bookmarks != 1 {
+		t.Fatalf("CountBookmarksForUser = %d, %v; want 1", bookmarks, err)
+	}
+
+	recent, err := db.CountRecentPostsForUser(ctx, database.CountRecentPostsForUserParams{
+		UserID: alice.ID,
+		Since:  now.Add(-time.Hour),
+	})
+	if err != nil || recent != 3 {
+		t.Fatalf("CountRecentPostsForUser = %d, %v; want 3", recent, err)
+	}
+	recent, err = db.CountRecentPostsForUser(ctx, database.CountRecentPostsForUserParams{
+		UserID: alice.ID,
+		Since:  now.Add(time.Hour),
+	})
+	if err != nil || recent != 0 {
+		t.Fatalf("CountRecentPostsForUser after the posts = %d, %v; want 0", recent, err)
+	}
+
+	top, err := db.TopFeedsForUser(ctx, database.TopFeedsForUserParams{UserID: alice.ID, Limit: 1})
+	if err != nil {
+		t.Fatalf("TopFeedsForUser returned error: %v", err)
+	}
+	if len(top) != 1 || top[0].Name != "A" || top[0].PostCount != 2 {
+		t.Fatalf("TopFeedsForUser = %+v; want A with 2 posts", top)
+	}
+}
+
+func TestHandlerStats_Overview(t *testing.T) {
+	db := openTestQueries(t)
+
+	alice := createTestUser(t, db, "alice")
+	feed := createTestFeed(t, db, alice, "Blog", "https://blog.example.com/feed.xml")
+	followTestFeed(t, db, alice, feed)
+	createTestPost(t, db, feed, "new", "https://blog.example.com/1", time.Now())
+
+	s, out, _ := newTestState(db, false)
+	if err := handlerStatsOverview(s, command{name: "stats"}, alice); err != nil {
+		t.Fatalf("handlerStatsOverview returned error: %v", err)
+	}
+	want := "Feeds followed: 1\nPosts available: 1\nPosts added in the last 24h: 1\nBookmarks: 0\n\n" +
+		"Top feeds by post count:\n  1. Blog (https://blog.example.com/feed.xml) - 1 posts\n"
+	if got := out.String(); got != want {
+		t.Fatalf("output = %q; want %q", got, want)
+	}
+}